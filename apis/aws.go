@@ -25,6 +25,7 @@ import (
 	apigatewayv2 "github.com/crossplane/provider-aws/apis/apigatewayv2/v1alpha1"
 	cachev1alpha1 "github.com/crossplane/provider-aws/apis/cache/v1alpha1"
 	cachev1beta1 "github.com/crossplane/provider-aws/apis/cache/v1beta1"
+	cloudformationv1alpha1 "github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
 	cloudfrontv1alpha1 "github.com/crossplane/provider-aws/apis/cloudfront/v1alpha1"
 	databasev1beta1 "github.com/crossplane/provider-aws/apis/database/v1beta1"
 	docdbv1alpha1 "github.com/crossplane/provider-aws/apis/docdb/v1alpha1"
@@ -99,6 +100,7 @@ func init() {
 		kafkav1alpha1.SchemeBuilder.AddToScheme,
 		transferv1alpha1.SchemeBuilder.AddToScheme,
 		gluev1alpha1.SchemeBuilder.AddToScheme,
+		cloudformationv1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
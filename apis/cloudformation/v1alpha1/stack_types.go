@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TemplateConfigMapReference refers to the key of a ConfigMap that holds a
+// CloudFormation template.
+type TemplateConfigMapReference struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key of the template in the ConfigMap.
+	// +kubebuilder:default=template
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// StackParameters define the desired state of an AWS CloudFormation Stack.
+type StackParameters struct {
+	// Region is the region you'd like your Stack to be created in.
+	Region string `json:"region"`
+
+	// TemplateBody is the inline CloudFormation template of the stack in YAML
+	// or JSON. Exactly one of TemplateBody and TemplateConfigMapRef must be
+	// set.
+	// +optional
+	TemplateBody *string `json:"templateBody,omitempty"`
+
+	// TemplateConfigMapRef refers to the key of a ConfigMap that holds the
+	// CloudFormation template of the stack in YAML or JSON. Exactly one of
+	// TemplateBody and TemplateConfigMapRef must be set.
+	// +optional
+	TemplateConfigMapRef *TemplateConfigMapReference `json:"templateConfigMapRef,omitempty"`
+
+	// Parameters are the values of the parameters of the template by their
+	// name. Parameters that are not set use their default value.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Capabilities that the template requires, such as CAPABILITY_IAM,
+	// CAPABILITY_NAMED_IAM or CAPABILITY_AUTO_EXPAND.
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// RoleARN is the ARN of the IAM role that CloudFormation assumes to
+	// create, update and delete the resources of the stack.
+	// +optional
+	RoleARN *string `json:"roleARN,omitempty"`
+
+	// Tags to apply to the stack and the resources it creates.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// StackSpec defines the desired state of an AWS CloudFormation Stack.
+type StackSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       StackParameters `json:"forProvider"`
+}
+
+// StackEvent is an event of an AWS CloudFormation Stack.
+type StackEvent struct {
+	// Timestamp of the event.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// LogicalResourceID is the logical ID of the resource in the template.
+	LogicalResourceID string `json:"logicalResourceID,omitempty"`
+
+	// ResourceType is the type of the resource, such as AWS::S3::Bucket.
+	ResourceType string `json:"resourceType,omitempty"`
+
+	// ResourceStatus is the status of the resource.
+	ResourceStatus string `json:"resourceStatus,omitempty"`
+
+	// ResourceStatusReason is the reason of the status of the resource.
+	ResourceStatusReason string `json:"resourceStatusReason,omitempty"`
+}
+
+// StackObservation is the representation of the current state that is
+// observed.
+type StackObservation struct {
+	// StackID is the unique ID of the stack.
+	StackID string `json:"stackID,omitempty"`
+
+	// StackStatus is the status of the stack, such as CREATE_COMPLETE.
+	StackStatus string `json:"stackStatus,omitempty"`
+
+	// StackStatusReason is the reason of the status of the stack.
+	StackStatusReason string `json:"stackStatusReason,omitempty"`
+
+	// DriftStatus is the result of the last drift detection of the stack,
+	// such as IN_SYNC or DRIFTED.
+	DriftStatus string `json:"driftStatus,omitempty"`
+
+	// LastDriftCheckTime is the time of the last drift detection of the
+	// stack.
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
+
+	// Outputs of the stack by their key. They are also published as the
+	// connection details of the stack.
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Events are the most recent events of the stack, newest first.
+	Events []StackEvent `json:"events,omitempty"`
+}
+
+// StackStatus represents the observed state of an AWS CloudFormation Stack.
+type StackStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          StackObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Stack is a managed resource that represents an AWS CloudFormation Stack.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.stackStatus"
+// +kubebuilder:printcolumn:name="DRIFT",type="string",JSONPath=".status.atProvider.driftStatus"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,aws}
+type Stack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StackSpec   `json:"spec"`
+	Status StackStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StackList contains a list of Stack
+type StackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Stack `json:"items"`
+}
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 // RepositoryPolicyParameters define the desired state of an AWS Elastic Container Repository
@@ -44,6 +46,11 @@ type RepositoryPolicyParameters struct {
 	// +optional
 	RawPolicy *string `json:"rawPolicy,omitempty"`
 
+	// PolicyDocument is the repository's policy in YAML form. It takes
+	// precedence over policy and rawPolicy.
+	// +optional
+	PolicyDocument *awsv1beta1.PolicyDocument `json:"policyDocument,omitempty"`
+
 	// The AWS account ID associated with the registry that contains the repository.
 	// If you do not specify a registry, the default registry is assumed.
 	// +optional
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 // IAMPolicyParameters define the desired state of an AWS IAM Policy.
@@ -32,8 +34,15 @@ type IAMPolicyParameters struct {
 	// +optional
 	Path *string `json:"path,omitempty"`
 
-	// The JSON policy document that is the content for the policy.
-	Document string `json:"document"`
+	// The JSON policy document that is the content for the policy. Either
+	// document or policyDocument must be specified.
+	// +optional
+	Document string `json:"document,omitempty"`
+
+	// PolicyDocument is the content for the policy in YAML form. It takes
+	// precedence over document.
+	// +optional
+	PolicyDocument *awsv1beta1.PolicyDocument `json:"policyDocument,omitempty"`
 
 	// The name of the policy.
 	Name string `json:"name"`
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 // Tag represents user-provided metadata that can be associated
@@ -49,9 +51,16 @@ type Tag struct {
 type IAMRoleParameters struct {
 
 	// AssumeRolePolicyDocument is the the trust relationship policy document
-	// that grants an entity permission to assume the role.
+	// that grants an entity permission to assume the role. Either
+	// assumeRolePolicyDocument or assumeRolePolicy must be specified.
 	// +immutable
-	AssumeRolePolicyDocument string `json:"assumeRolePolicyDocument"`
+	// +optional
+	AssumeRolePolicyDocument string `json:"assumeRolePolicyDocument,omitempty"`
+
+	// AssumeRolePolicy is the trust relationship policy document in YAML
+	// form. It takes precedence over assumeRolePolicyDocument.
+	// +optional
+	AssumeRolePolicy *awsv1beta1.PolicyDocument `json:"assumeRolePolicy,omitempty"`
 
 	// Description is a description of the role.
 	// +optional
@@ -22,6 +22,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -102,6 +103,11 @@ func (in *IAMRoleList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAMRoleParameters) DeepCopyInto(out *IAMRoleParameters) {
 	*out = *in
+	if in.AssumeRolePolicy != nil {
+		in, out := &in.AssumeRolePolicy, &out.AssumeRolePolicy
+		*out = new(apisv1beta1.PolicyDocument)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Description != nil {
 		in, out := &in.Description, &out.Description
 		*out = new(string)
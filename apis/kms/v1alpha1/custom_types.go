@@ -1,5 +1,9 @@
 package v1alpha1
 
+import (
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
 // CustomKeyParameters are custom parameters for Key.
 type CustomKeyParameters struct {
 	// Specifies whether the CMK is enabled.
@@ -7,4 +11,9 @@ type CustomKeyParameters struct {
 
 	// Specifies how many days the Key is retained when scheduled for deletion. Defaults to 30 days.
 	PendingWindowInDays *int64 `json:"pendingWindowInDays,omitempty"`
+
+	// PolicyDocument is the key policy in YAML form. It takes precedence over
+	// policy.
+	// +optional
+	PolicyDocument *awsv1beta1.PolicyDocument `json:"policyDocument,omitempty"`
 }
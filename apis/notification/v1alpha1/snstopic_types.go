@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 // Tag represent a user-provided metadata that can be associated with a
@@ -71,6 +73,11 @@ type SNSTopicParameters struct {
 	// +optional
 	Policy *string `json:"policy,omitempty"`
 
+	// PolicyDocument is the policy that defines who can access your topic in
+	// YAML form. It takes precedence over policy.
+	// +optional
+	PolicyDocument *awsv1beta1.PolicyDocument `json:"policyDocument,omitempty"`
+
 	// DeliveryRetryPolicy - the JSON serialization of the effective
 	// delivery policy, taking system defaults into account
 	// +optional
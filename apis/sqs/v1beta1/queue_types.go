@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	awsv1beta1 "github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 // Enum values for Queue attribute names
@@ -92,6 +94,11 @@ type QueueParameters struct {
 	// +optional
 	Policy *string `json:"policy,omitempty"`
 
+	// PolicyDocument is the queue's policy in YAML form. It takes precedence
+	// over policy.
+	// +optional
+	PolicyDocument *awsv1beta1.PolicyDocument `json:"policyDocument,omitempty"`
+
 	// ReceiveMessageWaitTimeSeconds - The length of time, in seconds, for
 	// which a ReceiveMessage action waits for a message to arrive. Valid values:
 	// an integer from 0 to 20 (seconds). Default: 0.
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// A PolicyDocument is an IAM policy document, e.g. the trust policy of an IAM
+// role or the resource policy of a queue, topic or key, written in YAML
+// rather than as a JSON string.
+// See https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements.html
+type PolicyDocument struct {
+	// Version of the policy language.
+	// +kubebuilder:validation:Enum="2012-10-17";"2008-10-17"
+	// +kubebuilder:default:="2012-10-17"
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ID is the optional identifier of the policy.
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// Statements of the policy.
+	Statements []PolicyStatement `json:"statements"`
+}
+
+// A PolicyStatement is a statement of a PolicyDocument.
+type PolicyStatement struct {
+	// SID is the optional identifier of the statement, which must be unique
+	// within the policy.
+	// +optional
+	SID *string `json:"sid,omitempty"`
+
+	// Effect of the statement.
+	// +kubebuilder:validation:Enum=Allow;Deny
+	Effect string `json:"effect"`
+
+	// Principal the statement allows or denies access to the resource.
+	// +optional
+	Principal *PolicyPrincipal `json:"principal,omitempty"`
+
+	// NotPrincipal are the principals the statement does not apply to.
+	// +optional
+	NotPrincipal *PolicyPrincipal `json:"notPrincipal,omitempty"`
+
+	// Action the statement allows or denies, e.g. sqs:SendMessage.
+	// +optional
+	Action []string `json:"action,omitempty"`
+
+	// NotAction are the actions the statement does not apply to.
+	// +optional
+	NotAction []string `json:"notAction,omitempty"`
+
+	// Resource the statement applies to, e.g. the ARN of a queue.
+	// +optional
+	Resource []string `json:"resource,omitempty"`
+
+	// NotResource are the resources the statement does not apply to.
+	// +optional
+	NotResource []string `json:"notResource,omitempty"`
+
+	// Condition for the statement to be in effect.
+	// +optional
+	Condition []PolicyCondition `json:"condition,omitempty"`
+}
+
+// A PolicyPrincipal is the principal of a PolicyStatement.
+type PolicyPrincipal struct {
+	// AllowAnon applies the statement to everyone, i.e. Principal: "*".
+	// +optional
+	AllowAnon *bool `json:"allowAnon,omitempty"`
+
+	// AWS are the AWS accounts, IAM users and IAM roles, by their ARN or
+	// account ID.
+	// +optional
+	AWS []string `json:"aws,omitempty"`
+
+	// Service are the AWS services, e.g. sns.amazonaws.com.
+	// +optional
+	Service []string `json:"service,omitempty"`
+
+	// Federated are the web identity or SAML providers.
+	// +optional
+	Federated []string `json:"federated,omitempty"`
+
+	// CanonicalUser are the canonical user IDs of AWS accounts.
+	// +optional
+	CanonicalUser []string `json:"canonicalUser,omitempty"`
+}
+
+// A PolicyCondition is a condition of a PolicyStatement.
+type PolicyCondition struct {
+	// Operator of the condition, e.g. StringEquals or
+	// ForAnyValue:StringLike.
+	Operator string `json:"operator"`
+
+	// Key of the request context the condition applies to, e.g.
+	// aws:SourceArn.
+	Key string `json:"key"`
+
+	// Values the key is compared with by the operator.
+	Values []string `json:"values"`
+}
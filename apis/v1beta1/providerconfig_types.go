@@ -26,12 +26,142 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Region is the default region used for the resources that do not
+	// specify one.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Endpoints overrides the endpoints of AWS services, keyed by service ID,
+	// e.g. "EC2" or "ec2". The key "*" applies to every service that does not
+	// have its own entry. The endpoint annotations of a resource take
+	// precedence over these.
+	// +optional
+	Endpoints map[string]EndpointConfig `json:"endpoints,omitempty"`
+
+	// AssumeRoleChain is a list of intermediate roles that are assumed in
+	// order, starting with the supplied credentials, before AssumeRole.
+	// +optional
+	AssumeRoleChain []AssumeRoleOptions `json:"assumeRoleChain,omitempty"`
+
+	// AssumeRole is the role that will be assumed on top of the supplied
+	// credentials, and any roles in AssumeRoleChain, to make the AWS API
+	// calls. The resulting STS credentials are cached and refreshed before
+	// they expire.
+	// +optional
+	AssumeRole *AssumeRoleOptions `json:"assumeRole,omitempty"`
+
+	// DefaultTags are added to the tags of every managed resource that uses
+	// this ProviderConfig, e.g. to tag all resources with a cost center. The
+	// tags of a managed resource take precedence over these.
+	// +optional
+	DefaultTags map[string]string `json:"defaultTags,omitempty"`
+}
+
+// EndpointConfig overrides the endpoint of an AWS service.
+type EndpointConfig struct {
+	// URL of the endpoint, e.g. http://localstack:4566.
+	URL string `json:"url"`
+
+	// SigningRegion is the region used to sign the requests. The region of
+	// the resource is used if not given.
+	// +optional
+	SigningRegion *string `json:"signingRegion,omitempty"`
+
+	// PartitionID is the ID of the partition the endpoint belongs to, e.g.
+	// aws, aws-cn or aws-us-gov.
+	// +optional
+	PartitionID *string `json:"partitionID,omitempty"`
+
+	// HostnameImmutable prevents the clients from modifying the hostname of
+	// the URL, e.g. to use virtual hosted style S3 addressing. It only
+	// applies to the clients using AWS SDK v2.
+	// +optional
+	HostnameImmutable *bool `json:"hostnameImmutable,omitempty"`
+}
+
+// AssumeRoleOptions define the options for assuming an IAM role.
+type AssumeRoleOptions struct {
+	// RoleARN is the Amazon Resource Name (ARN) of the role to assume.
+	RoleARN string `json:"roleARN"`
+
+	// ExternalID is a unique identifier that might be required when you
+	// assume a role in another account.
+	// +optional
+	ExternalID *string `json:"externalID,omitempty"`
+
+	// RoleSessionName is an identifier for the assumed role session. It is
+	// generated if not given.
+	// +optional
+	RoleSessionName *string `json:"roleSessionName,omitempty"`
+
+	// Tags are the session tags passed when assuming the role.
+	// +optional
+	Tags []Tag `json:"tags,omitempty"`
+
+	// TransitiveTagKeys is a list of keys of session tags that persist for
+	// the subsequent sessions in a role chain.
+	// +optional
+	TransitiveTagKeys []string `json:"transitiveTagKeys,omitempty"`
+}
+
+// Tag is a session tag passed when assuming a role.
+type Tag struct {
+	// Key of the tag.
+	Key string `json:"key"`
+
+	// Value of the tag.
+	Value string `json:"value"`
 }
 
+// CredentialsSourceWebIdentity indicates that the provider should exchange
+// an OIDC token for credentials of an IAM role through
+// AssumeRoleWithWebIdentity.
+const CredentialsSourceWebIdentity xpv1.CredentialsSource = "WebIdentity"
+
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
 	// Source of the provider credentials.
-	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
+	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem;WebIdentity
+	Source xpv1.CredentialsSource `json:"source"`
+
+	// WebIdentity defines the role and the OIDC token to use when the source
+	// is WebIdentity.
+	// +optional
+	WebIdentity *WebIdentityConfig `json:"webIdentity,omitempty"`
+
+	// Profile is the profile of the credentials to use when they are in the
+	// format of a shared config or credentials file, i.e. ~/.aws/config or
+	// ~/.aws/credentials. Its role_arn, source_profile, external_id,
+	// duration_seconds and region are honored, and so is its
+	// credential_process if the provider runs with --enable-credential-process.
+	// +kubebuilder:default=default
+	// +optional
+	Profile *string `json:"profile,omitempty"`
+
+	xpv1.CommonCredentialSelectors `json:",inline"`
+}
+
+// WebIdentityConfig defines the options for assuming an IAM role with an
+// OIDC token.
+type WebIdentityConfig struct {
+	// RoleARN is the Amazon Resource Name (ARN) of the role to assume.
+	RoleARN string `json:"roleARN"`
+
+	// RoleSessionName is an identifier for the assumed role session. It is
+	// generated if not given.
+	// +optional
+	RoleSessionName *string `json:"roleSessionName,omitempty"`
+
+	// TokenConfig locates the OIDC token, either in a projected token file
+	// or in a Kubernetes secret.
+	TokenConfig TokenConfig `json:"tokenConfig"`
+}
+
+// TokenConfig locates an OIDC token.
+type TokenConfig struct {
+	// Source of the token.
+	// +kubebuilder:validation:Enum=Secret;Filesystem
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
@@ -40,11 +170,27 @@ type ProviderCredentials struct {
 // A ProviderConfigStatus represents the status of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// AccountID is the ID of the AWS account of the credentials, as
+	// reported by STS the last time they were verified.
+	// +optional
+	AccountID string `json:"accountID,omitempty"`
+
+	// CallerARN is the ARN of the identity of the credentials, as reported
+	// by STS the last time they were verified.
+	// +optional
+	CallerARN string `json:"callerARN,omitempty"`
+
+	// LastVerifiedTime is the last time the credentials were verified.
+	// +optional
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 
 // A ProviderConfig configures how AWS controllers will connect to AWS API.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="ACCOUNT",type="string",JSONPath=".status.accountID"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,aws}
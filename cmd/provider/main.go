@@ -21,6 +21,8 @@ import (
 	"path/filepath"
 
 	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -29,7 +31,12 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
 	"github.com/crossplane/provider-aws/apis"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/controller"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+	"github.com/crossplane/provider-aws/pkg/controller/resync"
+	"github.com/crossplane/provider-aws/pkg/webhook/immutable"
+	"github.com/crossplane/provider-aws/pkg/webhook/policylint"
 )
 
 func main() {
@@ -38,7 +45,26 @@ func main() {
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncInterval   = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
 		pollInterval   = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
+		pollOverrides  = app.Flag("poll-interval-override", "Poll interval of the managed resources of a kind, e.g. IAMPolicy=1h or Table.dynamodb.aws.crossplane.io=30s. Overridden by the aws.crossplane.io/poll-interval annotation.").StringMap()
+		pollConfig     = app.Flag("poll-interval-config", "Path of a YAML file of the poll intervals of kinds, e.g. a mounted ConfigMap. Overridden by --poll-interval-override.").Default("").String()
 		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		awsRateLimit   = app.Flag("aws-rate-limit", "Maximum number of AWS API requests per second to a service in a region of an account. Zero disables client-side rate limiting.").Default("0").Float64()
+		awsRateBurst   = app.Flag("aws-rate-limit-burst", "Maximum number of AWS API requests that can be made at once to a service in a region of an account.").Default("10").Int()
+		awsRateMin     = app.Flag("aws-rate-limit-min", "Lowest number of AWS API requests per second the client-side rate limit slows down to while the requests are throttled.").Default("1").Float64()
+		resyncQueueURL = app.Flag("resync-queue-url", "URL of an SQS queue of EventBridge events, e.g. of CloudTrail API calls, that trigger the reconciliation of the managed resources they report changed. Empty disables event-driven resync.").Default("").String()
+		resyncConfig   = app.Flag("resync-provider-config", "Name of the ProviderConfig whose credentials are used to receive the events of the resync queue.").Default("default").String()
+		enabled        = app.Flag("enable-controllers", "API group, e.g. ec2, or kind, e.g. IAMPolicy or Cluster.eks.aws.crossplane.io, whose controllers are the only ones set up. Repeat for more. All controllers are set up if omitted.").Strings()
+		disabled       = app.Flag("disable-controllers", "API group or kind whose controllers are not set up, even if enabled. Repeat for more.").Strings()
+		shardSelector  = app.Flag("shard-selector", "Label selector of the managed resources reconciled by this provider instance, e.g. team=payments.").Default("").String()
+		shardCount     = app.Flag("shard-count", "Number of provider instances the managed resources are split between by the hash of their name.").Default("1").Int()
+		shardIndex     = app.Flag("shard-index", "Index of the shard of managed resources reconciled by this provider instance, from 0 to --shard-count minus 1.").Default("0").Int()
+		webhookPort    = app.Flag("webhook-port", "Port the admission webhooks are served at.").Default("9443").Int()
+		webhookCertDir = app.Flag("webhook-tls-cert-dir", "Directory of the tls.crt and tls.key files the admission webhooks are served with.").Default("/tmp/k8s-webhook-server/serving-certs").String()
+		policyLint     = app.Flag("policy-lint", "Serve a validating webhook that lints the IAM and resource policies of managed resources before they reach AWS.").Default("false").Bool()
+		policyRules    = app.Flag("policy-lint-rules", "Namespace and name of the ConfigMap of the policy linter rules, e.g. crossplane-system/policy-lint-rules. The default rules apply if empty.").Default("").String()
+		immutableCheck = app.Flag("immutable-fields", "Serve a validating webhook that rejects changes to the immutable fields of managed resources that do not opt into replacement.").Default("false").Bool()
+		credentialProc = app.Flag("enable-credential-process", "Run the credential_process of the profiles of credentials secrets. It runs a command taken from a secret in the provider container, so it is disabled by default.").Default("false").Bool()
+		dryRun         = app.Flag("dry-run", "Report the changes that would be made to the external resources in the DryRun condition of every managed resource instead of making them. Overridden by the aws.crossplane.io/dry-run annotation.").Default("false").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -60,11 +86,45 @@ func main() {
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: "crossplane-leader-election-provider-aws",
 		SyncPeriod:       syncInterval,
+		Port:             *webhookPort,
+		CertDir:          *webhookCertDir,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
+	awsclient.SetRateLimits(awsclient.RateLimitOptions{RPS: *awsRateLimit, Burst: *awsRateBurst, MinRPS: *awsRateMin})
+	awsclient.SetCredentialProcess(*credentialProc)
+	lifecycle.SetDryRun(*dryRun)
+
+	intervals := map[string]string{}
+	if *pollConfig != "" {
+		intervals, err = lifecycle.ReadPollIntervals(*pollConfig)
+		kingpin.FatalIfError(err, "Cannot read poll intervals")
+	}
+	for k, v := range *pollOverrides {
+		intervals[k] = v
+	}
+	polls, err := lifecycle.ParsePollIntervals(intervals)
+	kingpin.FatalIfError(err, "Cannot parse poll intervals")
+	lifecycle.SetPollIntervals(polls)
+
+	shard, err := lifecycle.ParseShard(*shardSelector, *shardCount, *shardIndex)
+	kingpin.FatalIfError(err, "Cannot parse shard")
+	lifecycle.SetShard(shard)
+	filter := controller.Filter{Enabled: *enabled, Disabled: *disabled}
+
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add AWS APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log, ratelimiter.NewGlobal(ratelimiter.DefaultGlobalRPS), *pollInterval), "Cannot setup AWS controllers")
+	kingpin.FatalIfError(controller.Setup(mgr, log, ratelimiter.NewGlobal(ratelimiter.DefaultGlobalRPS), *pollInterval, filter), "Cannot setup AWS controllers")
+	if *resyncQueueURL != "" {
+		kingpin.FatalIfError(resync.Setup(mgr, log, resync.Options{QueueURL: *resyncQueueURL, ProviderConfig: *resyncConfig, Enabled: filter.Allows}), "Cannot setup event-driven resync")
+	}
+	if *policyLint {
+		ns, name, err := cache.SplitMetaNamespaceKey(*policyRules)
+		kingpin.FatalIfError(err, "Cannot parse the policy linter rules ConfigMap")
+		kingpin.FatalIfError(policylint.Setup(mgr, log, policylint.Options{ConfigMap: types.NamespacedName{Namespace: ns, Name: name}}), "Cannot setup policy linter webhook")
+	}
+	if *immutableCheck {
+		kingpin.FatalIfError(immutable.Setup(mgr, log), "Cannot setup immutable fields webhook")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 
 }
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/pkg/errors"
+)
+
+const errParseARN = "cannot parse ARN %q"
+
+// An ARN is a parsed Amazon Resource Name.
+type ARN struct {
+	arn.ARN
+
+	// ResourceType is the type prefix of the resource part of the ARN, such
+	// as "role" in "role/path/name". It is empty for the ARNs that consist
+	// of a bare resource name, such as the ones of S3 buckets.
+	ResourceType string
+
+	// ResourceID is the resource part of the ARN without its type prefix,
+	// such as "path/name" in "role/path/name".
+	ResourceID string
+}
+
+// ParseARN parses the supplied Amazon Resource Name. The resource part of
+// the ARN is split into its type and ID at the first "/" or ":".
+func ParseARN(s string) (ARN, error) {
+	a, err := arn.Parse(s)
+	if err != nil {
+		return ARN{}, errors.Wrapf(err, errParseARN, s)
+	}
+	r := ARN{ARN: a, ResourceID: a.Resource}
+	if i := strings.IndexAny(a.Resource, "/:"); i > 0 {
+		r.ResourceType, r.ResourceID = a.Resource[:i], a.Resource[i+1:]
+	}
+	return r, nil
+}
+
+// ResourceName returns the last segment of the resource ID of the ARN, such
+// as "name" in "role/path/name".
+func (a ARN) ResourceName() string {
+	return a.ResourceID[strings.LastIndexAny(a.ResourceID, "/:")+1:]
+}
+
+// IsARN returns whether the supplied string is an Amazon Resource Name.
+func IsARN(s string) bool {
+	return arn.IsARN(s)
+}
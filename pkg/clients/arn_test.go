@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseARN(t *testing.T) {
+	type want struct {
+		resourceType string
+		resourceID   string
+		name         string
+		err          bool
+	}
+
+	cases := map[string]struct {
+		arn  string
+		want want
+	}{
+		"Bucket": {
+			arn:  "arn:aws:s3:::my-bucket",
+			want: want{resourceID: "my-bucket", name: "my-bucket"},
+		},
+		"RoleWithPath": {
+			arn:  "arn:aws:iam::123456789012:role/team/my-role",
+			want: want{resourceType: "role", resourceID: "team/my-role", name: "my-role"},
+		},
+		"ColonSeparated": {
+			arn:  "arn:aws:rds:us-east-1:123456789012:db:my-db",
+			want: want{resourceType: "db", resourceID: "my-db", name: "my-db"},
+		},
+		"Queue": {
+			arn:  "arn:aws:sqs:us-east-1:123456789012:my-queue",
+			want: want{resourceID: "my-queue", name: "my-queue"},
+		},
+		"NotAnARN": {
+			arn:  "my-bucket",
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := ParseARN(tc.arn)
+			got := want{resourceType: a.ResourceType, resourceID: a.ResourceID, err: err != nil}
+			if err == nil {
+				got.name = a.ResourceName()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("ParseARN(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	credentialsv1 "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+const (
+	// assumeRoleExpiryWindow is how long before their expiration the assumed
+	// role credentials are refreshed.
+	assumeRoleExpiryWindow = 5 * time.Minute
+
+	// stsFallbackRegion is the region used for STS calls when the config
+	// does not specify one.
+	stsFallbackRegion = "us-east-1"
+
+	errAssumeRole = "cannot assume role %s"
+)
+
+// AssumeRoleAPI is the subset of the STS API used to assume a role.
+type AssumeRoleAPI interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+}
+
+// NewAssumeRoleProvider returns a credentials provider that assumes the role
+// described by the supplied options using the supplied STS client.
+func NewAssumeRoleProvider(client AssumeRoleAPI, o v1beta1.AssumeRoleOptions) aws.CredentialsProvider {
+	return &assumeRoleProvider{client: client, options: o}
+}
+
+type assumeRoleProvider struct {
+	client  AssumeRoleAPI
+	options v1beta1.AssumeRoleOptions
+}
+
+// Retrieve assumes the role and returns the resulting credentials.
+func (p *assumeRoleProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	sessionName := aws.ToString(p.options.RoleSessionName)
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("crossplane-provider-aws-%d", time.Now().UnixNano())
+	}
+	input := &sts.AssumeRoleInput{
+		RoleArn:           aws.String(p.options.RoleARN),
+		RoleSessionName:   aws.String(sessionName),
+		ExternalId:        p.options.ExternalID,
+		TransitiveTagKeys: p.options.TransitiveTagKeys,
+	}
+	for _, t := range p.options.Tags {
+		input.Tags = append(input.Tags, ststypes.Tag{Key: aws.String(t.Key), Value: aws.String(t.Value)})
+	}
+	resp, err := p.client.AssumeRole(ctx, input)
+	if err != nil {
+		return aws.Credentials{}, Wrap(err, fmt.Sprintf(errAssumeRole, p.options.RoleARN))
+	}
+	if resp.Credentials == nil {
+		return aws.Credentials{}, errors.Errorf(errAssumeRole+": no credentials returned", p.options.RoleARN)
+	}
+	return aws.Credentials{
+		AccessKeyID:     aws.ToString(resp.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(resp.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(resp.Credentials.SessionToken),
+		Source:          "AssumeRoleProvider",
+		CanExpire:       true,
+		Expires:         aws.ToTime(resp.Credentials.Expiration),
+	}, nil
+}
+
+// AssumeRoleChainProvider returns a credentials provider that starts with the
+// credentials of the supplied config and assumes every role in the supplied
+// chain in order. The credentials of every step are cached and refreshed
+// before they expire.
+func AssumeRoleChainProvider(cfg aws.Config, chain []v1beta1.AssumeRoleOptions) aws.CredentialsProvider {
+	if cfg.Region == "" {
+		cfg.Region = stsFallbackRegion
+	}
+	provider := cfg.Credentials
+	for _, o := range chain {
+		cfg.Credentials = provider
+		provider = aws.NewCredentialsCache(NewAssumeRoleProvider(sts.NewFromConfig(cfg), o), func(co *aws.CredentialsCacheOptions) {
+			co.ExpiryWindow = assumeRoleExpiryWindow
+		})
+	}
+	return provider
+}
+
+// assumedRoles caches the assumed role credential providers of every
+// ProviderConfig so that the STS credentials survive across reconciles.
+var assumedRoles = &assumeRoleCache{providers: map[assumeRoleKey]assumeRoleCacheEntry{}}
+
+// An assumeRoleKey identifies the STS client the roles of a ProviderConfig
+// are assumed with.
+type assumeRoleKey struct {
+	providerConfig string
+	region         string
+	endpoint       string
+}
+
+// newAssumeRoleKey returns the key of the roles of the supplied
+// ProviderConfig that are assumed in the supplied region.
+func newAssumeRoleKey(pc *v1beta1.ProviderConfig, region string) assumeRoleKey {
+	k := assumeRoleKey{providerConfig: pc.GetName(), region: region}
+	if ec, ok := endpointConfigFor(pc.Spec.Endpoints, sts.ServiceID); ok {
+		k.endpoint = ec.URL
+	}
+	return k
+}
+
+type assumeRoleCacheEntry struct {
+	version  string
+	provider aws.CredentialsProvider
+}
+
+type assumeRoleCache struct {
+	mu        sync.Mutex
+	providers map[assumeRoleKey]assumeRoleCacheEntry
+}
+
+// get returns the provider cached under the supplied key and version, or
+// stores and returns the one built by newFn. Only the latest version of a
+// ProviderConfig is kept, so the providers built from any other version of
+// it are evicted.
+func (c *assumeRoleCache) get(k assumeRoleKey, version string, newFn func() aws.CredentialsProvider) aws.CredentialsProvider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.providers[k]; ok && e.version == version {
+		return e.provider
+	}
+	for ek, e := range c.providers {
+		if ek.providerConfig == k.providerConfig && e.version != version {
+			delete(c.providers, ek)
+		}
+	}
+	p := newFn()
+	c.providers[k] = assumeRoleCacheEntry{version: version, provider: p}
+	return p
+}
+
+// forget evicts the providers cached for the supplied ProviderConfig.
+func (c *assumeRoleCache) forget(pc string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.providers {
+		if k.providerConfig == pc {
+			delete(c.providers, k)
+		}
+	}
+}
+
+// SetAssumeRole replaces the credentials of the supplied config with the ones
+// obtained by assuming the roles configured in the supplied ProviderConfig,
+// if any. The supplied version must change whenever the ProviderConfig or
+// its credentials change, so that the credentials of the source identity are
+// never retrieved just to tell whether the cached roles are stale.
+func SetAssumeRole(pc *v1beta1.ProviderConfig, cfg *aws.Config, version string) *aws.Config {
+	if pc.Spec.AssumeRole == nil {
+		return cfg
+	}
+	chain := append(append([]v1beta1.AssumeRoleOptions{}, pc.Spec.AssumeRoleChain...), *pc.Spec.AssumeRole)
+	cfg.Credentials = assumedRoles.get(newAssumeRoleKey(pc, cfg.Region), version, func() aws.CredentialsProvider {
+		return AssumeRoleChainProvider(*cfg, chain)
+	})
+	return cfg
+}
+
+// NewCredentialsV1 returns SDK v1 credentials that are retrieved from the
+// supplied SDK v2 credentials provider.
+func NewCredentialsV1(p aws.CredentialsProvider) *credentialsv1.Credentials {
+	return credentialsv1.NewCredentials(&credentialsProviderV1{provider: p})
+}
+
+// credentialsProviderV1 adapts an SDK v2 credentials provider to be used by
+// SDK v1 clients.
+type credentialsProviderV1 struct {
+	provider aws.CredentialsProvider
+	creds    aws.Credentials
+}
+
+// Retrieve returns the credentials of the underlying SDK v2 provider.
+func (p *credentialsProviderV1) Retrieve() (credentialsv1.Value, error) {
+	c, err := p.provider.Retrieve(context.Background())
+	if err != nil {
+		return credentialsv1.Value{}, err
+	}
+	p.creds = c
+	return credentialsv1.Value{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		ProviderName:    c.Source,
+	}, nil
+}
+
+// IsExpired returns whether the last retrieved credentials are expired or
+// about to expire, so that they are refreshed in time.
+func (p *credentialsProviderV1) IsExpired() bool {
+	return p.creds.CanExpire && time.Now().Add(assumeRoleExpiryWindow).After(p.creds.Expires)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+var (
+	roleARN    = "arn:aws:iam::123456789012:role/crossplane"
+	externalID = "external-id"
+	expiration = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type mockAssumeRole func(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+
+func (m mockAssumeRole) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	return m(ctx, params, optFns...)
+}
+
+func TestAssumeRoleProviderRetrieve(t *testing.T) {
+	errBoom := errors.New("boom")
+	type args struct {
+		client  AssumeRoleAPI
+		options v1beta1.AssumeRoleOptions
+	}
+	type want struct {
+		creds aws.Credentials
+		err   error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Successful": {
+			args: args{
+				client: mockAssumeRole(func(_ context.Context, params *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+					want := &sts.AssumeRoleInput{
+						RoleArn:           aws.String(roleARN),
+						RoleSessionName:   aws.String("session"),
+						ExternalId:        aws.String(externalID),
+						Tags:              []ststypes.Tag{{Key: aws.String("team"), Value: aws.String("platform")}},
+						TransitiveTagKeys: []string{"team"},
+					}
+					if diff := cmp.Diff(want, params, cmpopts.IgnoreUnexported(sts.AssumeRoleInput{}, ststypes.Tag{})); diff != "" {
+						return nil, errors.Errorf("unexpected input: -want, +got:\n%s", diff)
+					}
+					return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+						AccessKeyId:     aws.String("id"),
+						SecretAccessKey: aws.String("secret"),
+						SessionToken:    aws.String("token"),
+						Expiration:      &expiration,
+					}}, nil
+				}),
+				options: v1beta1.AssumeRoleOptions{
+					RoleARN:           roleARN,
+					RoleSessionName:   aws.String("session"),
+					ExternalID:        aws.String(externalID),
+					Tags:              []v1beta1.Tag{{Key: "team", Value: "platform"}},
+					TransitiveTagKeys: []string{"team"},
+				},
+			},
+			want: want{
+				creds: aws.Credentials{
+					AccessKeyID:     "id",
+					SecretAccessKey: "secret",
+					SessionToken:    "token",
+					Source:          "AssumeRoleProvider",
+					CanExpire:       true,
+					Expires:         expiration,
+				},
+			},
+		},
+		"AssumeRoleFailed": {
+			args: args{
+				client: mockAssumeRole(func(_ context.Context, _ *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+					return nil, errBoom
+				}),
+				options: v1beta1.AssumeRoleOptions{RoleARN: roleARN},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, fmt.Sprintf(errAssumeRole, roleARN)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			creds, err := NewAssumeRoleProvider(tc.args.client, tc.args.options).Retrieve(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, creds); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// stsStub is a local STS endpoint that records the assumed roles and issues
+// credentials whose access key ID is the ARN of the assumed role.
+type stsStub struct {
+	mu     sync.Mutex
+	calls  []string
+	tokens []string
+}
+
+func (s *stsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	action, role := r.Form.Get("Action"), r.Form.Get("RoleArn")
+	s.mu.Lock()
+	s.calls = append(s.calls, role)
+	if t := r.Form.Get("WebIdentityToken"); t != "" {
+		s.tokens = append(s.tokens, t)
+	}
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <%[1]sResult>
+    <Credentials>
+      <AccessKeyId>%[2]s</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>%[3]s</Expiration>
+    </Credentials>
+  </%[1]sResult>
+</%[1]sResponse>`, action, role, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+}
+
+// countingProvider counts how often its credentials are retrieved.
+type countingProvider struct {
+	aws.CredentialsProvider
+	retrieved int
+}
+
+func (p *countingProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	p.retrieved++
+	return p.CredentialsProvider.Retrieve(ctx)
+}
+
+func TestSetAssumeRole(t *testing.T) {
+	stub := &stsStub{}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{UID: "pc-uid", ResourceVersion: "1"},
+		Spec: v1beta1.ProviderConfigSpec{
+			AssumeRoleChain: []v1beta1.AssumeRoleOptions{{RoleARN: "intermediate"}},
+			AssumeRole:      &v1beta1.AssumeRoleOptions{RoleARN: "target"},
+		},
+	}
+	source := &countingProvider{CredentialsProvider: credentials.NewStaticCredentialsProvider("source", "secret", "")}
+	newConfig := func(region string) *aws.Config {
+		return &aws.Config{
+			Region:      region,
+			Credentials: source,
+			EndpointResolver: aws.EndpointResolverFunc(func(_, _ string) (aws.Endpoint, error) {
+				return aws.Endpoint{URL: srv.URL}, nil
+			}),
+		}
+	}
+
+	for _, region := range []string{"us-east-1", "us-east-1", "eu-west-1"} {
+		cfg := SetAssumeRole(pc, newConfig(region), "1/secret")
+		creds, err := cfg.Credentials.Retrieve(context.Background())
+		if err != nil {
+			t.Fatalf("Retrieve(...): %s", err)
+		}
+		if diff := cmp.Diff("target", creds.AccessKeyID); diff != "" {
+			t.Errorf("r: -want, +got:\n%s", diff)
+		}
+		v1, err := NewCredentialsV1(cfg.Credentials).Get()
+		if err != nil {
+			t.Fatalf("Get(...): %s", err)
+		}
+		if diff := cmp.Diff("target", v1.AccessKeyID); diff != "" {
+			t.Errorf("r: -want, +got:\n%s", diff)
+		}
+	}
+
+	// The roles must be assumed in order and only once per region since the
+	// credentials are cached across configs, and the source credentials are
+	// only retrieved to assume them.
+	if diff := cmp.Diff([]string{"intermediate", "target", "intermediate", "target"}, stub.calls); diff != "" {
+		t.Errorf("calls: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(2, source.retrieved); diff != "" {
+		t.Errorf("Retrieve(...): -want calls, +got calls:\n%s", diff)
+	}
+}
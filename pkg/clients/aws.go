@@ -22,17 +22,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	ec2type "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	awsv1 "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	credentialsv1 "github.com/aws/aws-sdk-go/aws/credentials"
 	endpointsv1 "github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/smithy-go"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	jsonpatch "github.com/evanphx/json-patch"
@@ -72,44 +74,160 @@ const (
 // GetConfig constructs an *aws.Config that can be used to authenticate to AWS
 // API by the AWS clients.
 func GetConfig(ctx context.Context, c client.Client, mg resource.Managed, region string) (*aws.Config, error) {
+	var cfg *aws.Config
+	var err error
 	switch {
 	case mg.GetProviderConfigReference() != nil:
-		return UseProviderConfig(ctx, c, mg, region)
+		cfg, err = UseProviderConfig(ctx, c, mg, region)
 	case mg.GetProviderReference() != nil:
-		return UseProvider(ctx, c, mg, region)
+		cfg, err = UseProvider(ctx, c, mg, region)
 	default:
 		return nil, errors.New("neither providerConfigRef nor providerRef is given")
 	}
+	if err != nil {
+		return nil, err
+	}
+	AddMetricsToConfig(KindOf(mg), cfg)
+	return cfg, nil
 }
 
 // UseProviderConfig to produce a config that can be used to authenticate to AWS.
+// The configs are cached and shared across reconciles until the ProviderConfig
+// or its credentials change.
 func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed, region string) (*aws.Config, error) {
+	pc, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		region = pc.Spec.Region
+	}
+	data, err := credentialsData(ctx, c, pc)
+	if err != nil {
+		return nil, err
+	}
+	key, version := newConfigKey(pc, mg, region), configVersion(pc, data)
+	if cfg, ok := configs.getV2(key, version); ok {
+		return cfg, nil
+	}
+	cfg, err := useProviderConfigCredentials(ctx, c, pc, data, region)
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: the endpoints are set before assuming roles so that STS calls
+	// use them, too.
+	cfg = SetResolver(ctx, mg, SetAssumeRole(pc, SetEndpoints(pc, cfg), version))
+	if rateLimiter != nil {
+		// NOTE: the account ID is resolved before the rate limits are added
+		// so that resolving it is not limited by itself.
+		account, err := accountIDs.get(ctx, pc.GetName(), version, callerAccountID(*cfg))
+		if err != nil {
+			return nil, err
+		}
+		rateLimiter.AddToConfig(account, cfg)
+	}
+	return configs.setV2(key, version, cfg), nil
+}
+
+// GetConfigForProviderConfig constructs an *aws.Config that authenticates
+// with the credentials of the supplied ProviderConfig, including any assumed
+// role. Unlike the configs of managed resources, it is not cached.
+func GetConfigForProviderConfig(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+	if region == "" {
+		region = pc.Spec.Region
+	}
+	data, err := credentialsData(ctx, c, pc)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := useProviderConfigCredentials(ctx, c, pc, data, region)
+	if err != nil {
+		return nil, err
+	}
+	return SetAssumeRole(pc, SetEndpoints(pc, cfg), configVersion(pc, data)), nil
+}
+
+// getProviderConfig returns the ProviderConfig of the supplied managed
+// resource and tracks its usage.
+func getProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (*v1beta1.ProviderConfig, error) {
 	pc := &v1beta1.ProviderConfig{}
 	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, "cannot get referenced Provider")
+		return nil, errors.Wrap(err, "cannot get referenced ProviderConfig")
 	}
 
 	t := resource.NewProviderConfigUsageTracker(c, &v1beta1.ProviderConfigUsage{})
 	if err := t.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, "cannot track ProviderConfig usage")
 	}
+	return pc, nil
+}
+
+// credentialsData returns the credentials of the supplied ProviderConfig, or
+// nil if its credentials source does not have any.
+func credentialsData(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) ([]byte, error) {
+	switch s := pc.Spec.Credentials.Source; s { //nolint:exhaustive
+	case xpv1.CredentialsSourceInjectedIdentity, v1beta1.CredentialsSourceWebIdentity:
+		return nil, nil
+	default:
+		data, err := resource.CommonCredentialExtractor(ctx, s, c, pc.Spec.Credentials.CommonCredentialSelectors)
+		return data, errors.Wrap(err, "cannot get credentials")
+	}
+}
 
+// useProviderConfigCredentials produces a config with the supplied credentials
+// of the supplied ProviderConfig, without assuming any role.
+func useProviderConfigCredentials(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, data []byte, region string) (*aws.Config, error) {
 	switch s := pc.Spec.Credentials.Source; s { //nolint:exhaustive
 	case xpv1.CredentialsSourceInjectedIdentity:
-		cfg, err := UsePodServiceAccount(ctx, []byte{}, DefaultSection, region)
-		return SetResolver(ctx, mg, cfg), err
+		return UsePodServiceAccount(ctx, []byte{}, DefaultSection, region)
+	case v1beta1.CredentialsSourceWebIdentity:
+		return UseWebIdentity(ctx, c, pc, region)
 	default:
-		data, err := resource.CommonCredentialExtractor(ctx, s, c, pc.Spec.Credentials.CommonCredentialSelectors)
-		if err != nil {
-			return nil, errors.Wrap(err, "cannot get credentials")
+		return UseProviderSecret(ctx, data, profileName(pc), region)
+	}
+}
+
+// SetEndpoints configures the supplied config to use the endpoint overrides
+// of the supplied ProviderConfig, if any.
+func SetEndpoints(pc *v1beta1.ProviderConfig, cfg *aws.Config) *aws.Config {
+	if len(pc.Spec.Endpoints) == 0 {
+		return cfg
+	}
+	endpointResolver := func(service, region string) (aws.Endpoint, error) {
+		ec, ok := endpointConfigFor(pc.Spec.Endpoints, service)
+		if !ok {
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		}
+		endpoint := aws.Endpoint{
+			URL:               ec.URL,
+			SigningRegion:     region,
+			PartitionID:       StringValue(ec.PartitionID),
+			HostnameImmutable: BoolValue(ec.HostnameImmutable),
+		}
+		if ec.SigningRegion != nil {
+			endpoint.SigningRegion = *ec.SigningRegion
+		}
+		return endpoint, nil
+	}
+	cfg.EndpointResolver = aws.EndpointResolverFunc(endpointResolver)
+	return cfg
+}
+
+// endpointConfigFor returns the endpoint override of the supplied service,
+// falling back to the one that applies to all services.
+func endpointConfigFor(endpoints map[string]v1beta1.EndpointConfig, service string) (v1beta1.EndpointConfig, bool) {
+	for id, ec := range endpoints {
+		if strings.EqualFold(id, service) {
+			return ec, true
 		}
-		cfg, err := UseProviderSecret(ctx, data, DefaultSection, region)
-		return SetResolver(ctx, mg, cfg), err
 	}
+	ec, ok := endpoints["*"]
+	return ec, ok
 }
 
 // SetResolver parses annotations from the managed resource
-// and returns a configuration accordingly.
+// and returns a configuration accordingly. The annotations take precedence
+// over any endpoint resolver the configuration already has.
 func SetResolver(ctx context.Context, mg resource.Managed, cfg *aws.Config) *aws.Config {
 	if ServiceID, ok := mg.GetAnnotations()["aws.alpha.crossplane.io/endpointServiceID"]; ok {
 		if URL, ok := mg.GetAnnotations()["aws.alpha.crossplane.io/endpointURL"]; ok {
@@ -120,10 +238,14 @@ func SetResolver(ctx context.Context, mg resource.Managed, cfg *aws.Config) *aws
 				endpoint.SigningRegion = Region
 			}
 
+			fallback := cfg.EndpointResolver
 			endpointResolver := func(service, region string) (aws.Endpoint, error) {
 				if strings.Contains(ServiceID, service) {
 					return endpoint, nil
 				}
+				if fallback != nil {
+					return fallback.ResolveEndpoint(service, region)
+				}
 
 				return endpoint, &aws.EndpointNotFoundError{}
 			}
@@ -200,23 +322,17 @@ func CredentialsIDSecret(data []byte, profile string) (aws.Credentials, error) {
 // AuthMethod is a method of authenticating to the AWS API
 type AuthMethod func(context.Context, []byte, string, string) (*aws.Config, error)
 
-// UseProviderSecret - AWS configuration which can be used to issue requests against AWS API
+// UseProviderSecret - AWS configuration which can be used to issue requests against AWS API.
+// The supplied data is a shared config or credentials file, whose supplied
+// profile is used.
 func UseProviderSecret(ctx context.Context, data []byte, profile, region string) (*aws.Config, error) {
-	creds, err := CredentialsIDSecret(data, profile)
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot parse credentials secret")
-	}
-
-	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
-		Value: creds,
-	}))
-	return &config, err
+	return UseSharedConfig(ctx, data, profile, region)
 }
 
 // UsePodServiceAccount assumes an IAM role configured via a ServiceAccount.
 // https://docs.aws.amazon.com/eks/latest/userguide/iam-roles-for-service-accounts.html
 func UsePodServiceAccount(ctx context.Context, _ []byte, _, region string) (*aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load default AWS config")
 	}
@@ -227,38 +343,66 @@ func UsePodServiceAccount(ctx context.Context, _ []byte, _, region string) (*aws
 // aws/aws-sdk-go-v2. These functions are implemented to be used by those controllers.
 
 // GetConfigV1 constructs an *awsv1.Config that can be used to authenticate to AWS
-// API by the AWSv1 clients.
-func GetConfigV1(ctx context.Context, c client.Client, mg resource.Managed, region string) (*session.Session, error) { // nolint:gocyclo
+// API by the AWSv1 clients. The sessions are cached and shared across
+// reconciles until the ProviderConfig or its credentials change.
+func GetConfigV1(ctx context.Context, c client.Client, mg resource.Managed, region string) (*session.Session, error) {
+	sess, err := getConfigV1(ctx, c, mg, region)
+	if err != nil {
+		return nil, err
+	}
+	AddMetricsToSession(KindOf(mg), sess)
+	return sess, nil
+}
+
+func getConfigV1(ctx context.Context, c client.Client, mg resource.Managed, region string) (*session.Session, error) {
 	if mg.GetProviderConfigReference() == nil {
 		return nil, errors.New("providerConfigRef cannot be empty")
 	}
-	pc := &v1beta1.ProviderConfig{}
-	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, "cannot get referenced ProviderConfig")
+	pc, err := getProviderConfig(ctx, c, mg)
+	if err != nil {
+		return nil, err
 	}
-
-	t := resource.NewProviderConfigUsageTracker(c, &v1beta1.ProviderConfigUsage{})
-	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, "cannot track ProviderConfig usage")
+	if region == "" {
+		region = pc.Spec.Region
 	}
-	switch s := pc.Spec.Credentials.Source; s { //nolint:exhaustive
-	case xpv1.CredentialsSourceInjectedIdentity:
-		cfg, err := UsePodServiceAccountV1(ctx, []byte{}, mg, DefaultSection, region)
-		if err != nil {
-			return nil, errors.Wrap(err, "cannot use pod service account")
-		}
-		return session.NewSession(cfg)
-	default:
-		data, err := resource.CommonCredentialExtractor(ctx, s, c, pc.Spec.Credentials.CommonCredentialSelectors)
-		if err != nil {
-			return nil, errors.Wrap(err, "cannot get credentials")
-		}
-		cfg, err := UseProviderSecretV1(ctx, data, mg, DefaultSection, region)
+	data, err := credentialsData(ctx, c, pc)
+	if err != nil {
+		return nil, err
+	}
+	key, version := newConfigKey(pc, mg, region), configVersion(pc, data)
+	if sess, ok := configs.getV1(key, version); ok {
+		return sess, nil
+	}
+	cfg, err := useProviderConfigCredentialsV1(ctx, c, pc, data, region)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(SetResolverV1(ctx, mg, SetEndpointsV1(pc, cfg)))
+	if err != nil {
+		return nil, err
+	}
+	if rateLimiter != nil {
+		account, err := accountIDs.get(ctx, pc.GetName(), version, callerAccountIDV1(sess))
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot use secret")
+			return nil, err
 		}
-		return session.NewSession(cfg)
+		rateLimiter.AddToSession(account, sess)
 	}
+	return configs.setV1(key, version, sess), nil
+}
+
+// useProviderConfigCredentialsV1 produces a V1 config with the supplied
+// credentials of the supplied ProviderConfig, including any assumed role.
+func useProviderConfigCredentialsV1(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, data []byte, region string) (*awsv1.Config, error) {
+	// NOTE: credentials are built with SDK v2 and adapted for SDK v1 so that
+	// they are cached and refreshed the same way, including the ones that
+	// need STS for the profiles of credentials secrets.
+	cfg, err := useProviderConfigCredentials(ctx, c, pc, data, region)
+	if err != nil {
+		return nil, err
+	}
+	cfg = SetAssumeRole(pc, SetEndpoints(pc, cfg), configVersion(pc, data))
+	return awsv1.NewConfig().WithCredentials(NewCredentialsV1(cfg.Credentials)).WithRegion(cfg.Region), nil
 }
 
 // UseProviderSecretV1 retrieves AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY from
@@ -267,6 +411,7 @@ func GetConfigV1(ctx context.Context, c client.Client, mg resource.Managed, regi
 // [default]
 // aws_access_key_id = <YOUR_ACCESS_KEY_ID>
 // aws_secret_access_key = <YOUR_SECRET_ACCESS_KEY>
+// See UseSharedConfigV1 for the other settings of a shared config profile.
 func UseProviderSecretV1(ctx context.Context, data []byte, mg resource.Managed, profile, region string) (*awsv1.Config, error) {
 	config, err := ini.InsensitiveLoad(data)
 	if err != nil {
@@ -293,6 +438,17 @@ func UseProviderSecretV1(ctx context.Context, data []byte, mg resource.Managed,
 	return SetResolverV1(ctx, mg, awsv1.NewConfig().WithCredentials(creds).WithRegion(region)), nil
 }
 
+// UseSharedConfigV1 produces a *awsv1.Config with the credentials of the
+// profile with the supplied name in the supplied shared config, like
+// UseSharedConfig does for SDK v2.
+func UseSharedConfigV1(ctx context.Context, data []byte, profile, region string) (*awsv1.Config, error) {
+	cfg, err := UseSharedConfig(ctx, data, profile, region)
+	if err != nil {
+		return nil, err
+	}
+	return awsv1.NewConfig().WithCredentials(NewCredentialsV1(cfg.Credentials)).WithRegion(cfg.Region), nil
+}
+
 // UsePodServiceAccountV1 assumes an IAM role configured via a ServiceAccount.
 // https://docs.aws.amazon.com/eks/latest/userguide/iam-roles-for-service-accounts.html
 func UsePodServiceAccountV1(ctx context.Context, _ []byte, mg resource.Managed, _, region string) (*awsv1.Config, error) {
@@ -311,8 +467,34 @@ func UsePodServiceAccountV1(ctx context.Context, _ []byte, mg resource.Managed,
 	return SetResolverV1(ctx, mg, awsv1.NewConfig().WithCredentials(v1creds).WithRegion(region)), nil
 }
 
+// SetEndpointsV1 configures the supplied V1 config to use the endpoint
+// overrides of the supplied ProviderConfig, if any.
+func SetEndpointsV1(pc *v1beta1.ProviderConfig, cfg *awsv1.Config) *awsv1.Config {
+	if len(pc.Spec.Endpoints) == 0 {
+		return cfg
+	}
+	endpointResolver := func(service, region string, optFns ...func(*endpointsv1.Options)) (endpointsv1.ResolvedEndpoint, error) {
+		ec, ok := endpointConfigFor(pc.Spec.Endpoints, service)
+		if !ok {
+			return endpointsv1.DefaultResolver().EndpointFor(service, region, optFns...)
+		}
+		endpoint := endpointsv1.ResolvedEndpoint{
+			URL:           ec.URL,
+			SigningRegion: region,
+			PartitionID:   StringValue(ec.PartitionID),
+		}
+		if ec.SigningRegion != nil {
+			endpoint.SigningRegion = *ec.SigningRegion
+		}
+		return endpoint, nil
+	}
+	cfg.EndpointResolver = endpointsv1.ResolverFunc(endpointResolver)
+	return cfg
+}
+
 // SetResolverV1 parses annotations from the managed resource
-// and returns a V1 configuration accordingly.
+// and returns a V1 configuration accordingly. The annotations take precedence
+// over any endpoint resolver the configuration already has.
 func SetResolverV1(ctx context.Context, mg resource.Managed, cfg *awsv1.Config) *awsv1.Config {
 	if ServiceID, ok := mg.GetAnnotations()["aws.alpha.crossplane.io/endpointServiceID"]; ok {
 		if URL, ok := mg.GetAnnotations()["aws.alpha.crossplane.io/endpointURL"]; ok {
@@ -323,12 +505,16 @@ func SetResolverV1(ctx context.Context, mg resource.Managed, cfg *awsv1.Config)
 				endpoint.SigningRegion = Region
 			}
 
+			fallback := cfg.EndpointResolver
+			if fallback == nil {
+				fallback = endpointsv1.DefaultResolver()
+			}
 			endpointResolver := func(service, region string, optFns ...func(*endpointsv1.Options)) (endpointsv1.ResolvedEndpoint, error) {
 				if strings.Contains(ServiceID, service) {
 					return endpoint, nil
 				}
 
-				return endpointsv1.DefaultResolver().EndpointFor(service, region, optFns...)
+				return fallback.EndpointFor(service, region, optFns...)
 			}
 			cfg.EndpointResolver = endpointsv1.ResolverFunc(endpointResolver)
 		}
@@ -358,6 +544,19 @@ func CreateJSONPatch(source, destination interface{}) ([]byte, error) {
 	return patchJSON, nil
 }
 
+// DiffJSON returns the fields of desired that differ from observed as a JSON
+// merge patch, or an empty string if they do not differ.
+func DiffJSON(observed, desired interface{}) (string, error) {
+	patch, err := CreateJSONPatch(observed, desired)
+	if err != nil {
+		return "", err
+	}
+	if string(patch) == "{}" {
+		return "", nil
+	}
+	return string(patch), nil
+}
+
 // String converts the supplied string for use with the AWS Go SDK.
 func String(v string, o ...FieldOption) *string {
 	for _, fo := range o {
@@ -657,8 +856,19 @@ func DiffLabels(local, remote map[string]string) (addOrModify map[string]string,
 	return
 }
 
-// IsPolicyUpToDate Marshall policies to json for a compare to get around string ordering
+// IsPolicyUpToDate Marshall policies to json for a compare to get around string ordering.
+// IAM policy documents are compared in their canonical form, so that documents
+// that only differ in order or in the form of their values are equal.
 func IsPolicyUpToDate(local, remote *string) bool {
+	if local == nil || remote == nil {
+		return local == remote
+	}
+	if lp, err := ParsePolicy(*local); err == nil {
+		if rp, err := ParsePolicy(*remote); err == nil {
+			return ArePoliciesEqual(lp, rp)
+		}
+	}
+
 	var localUnmarshalled interface{}
 	var remoteUnmarshalled interface{}
 
@@ -687,21 +897,80 @@ func IsPolicyUpToDate(local, remote *string) bool {
 	return cmp.Equal(localUnmarshalled, remoteUnmarshalled, cmpopts.EquateEmpty(), sortSlicesOpt)
 }
 
-// CleanError Will remove the requestID from a awserr.Error and return a new awserr.
-// If not awserr it will return the original error
+// throttlingErrorCodes are the error codes AWS APIs return when a call is
+// throttled.
+var throttlingErrorCodes = map[string]struct{}{
+	"Throttling":                             {},
+	"ThrottlingException":                    {},
+	"ThrottledException":                     {},
+	"RequestThrottledException":              {},
+	"TooManyRequestsException":               {},
+	"ProvisionedThroughputExceededException": {},
+	"TransactionInProgressException":         {},
+	"RequestLimitExceeded":                   {},
+	"BandwidthLimitExceeded":                 {},
+	"RequestThrottled":                       {},
+	"SlowDown":                               {},
+	"PriorRequestNotComplete":                {},
+	"EC2ThrottledException":                  {},
+}
+
+// ErrorCode returns the AWS error code of the supplied error, which may be
+// returned by either AWS SDK v1 or v2 clients, or an empty string if it does
+// not have one.
+func ErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code()
+	}
+	return ""
+}
+
+// IsErrorThrottling returns whether the supplied error is returned because
+// the call is throttled.
+func IsErrorThrottling(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := throttlingErrorCodes[ErrorCode(err)]
+	return ok
+}
+
+// CleanError removes the request ID from the message of the supplied error,
+// so that the message does not change with every request, e.g. when it is
+// reported in a condition. The returned error wraps the supplied one.
 func CleanError(err error) error {
 	if err == nil {
 		return err
 	}
-	// TODO cvodak revisit this
-	// var re *awshttp.ResponseError
-	// if errors.As(err, &re) {
-	//	log.Printf("requestID: %s, error: %v", re.ServiceRequestID(), re.Unwrap());
-	//  }
-	// if awsErr, ok := err.(awserr.Error); ok {
-	//	return awserr.New(awsErr.Code(), awsErr.Message(), nil)
-	//}
-	return err
+	msg := requestIDPattern.ReplaceAllString(err.Error(), "")
+	if msg == err.Error() {
+		return err
+	}
+	return &cleanError{error: err, msg: msg}
+}
+
+// requestIDPattern matches the request IDs in the messages of the errors of
+// both AWS SDKs, i.e. ", RequestID: <id>" and ", request id: <id>".
+var requestIDPattern = regexp.MustCompile(`(?i),?\s*request ?id: [^\s,]*`)
+
+// A cleanError is an error whose message is stripped of its request ID. It
+// wraps the original error so that its code and type can still be inspected.
+type cleanError struct {
+	error
+	msg string
+}
+
+func (e *cleanError) Error() string {
+	return e.msg
+}
+
+func (e *cleanError) Unwrap() error {
+	return e.error
 }
 
 // Wrap Attempts to remove requestID from awserr before calling Wrap
@@ -19,14 +19,25 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awsv1 "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/document"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
 )
 
 const (
@@ -525,3 +536,177 @@ func TestIsPolicyUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestSetEndpoints(t *testing.T) {
+	localstack := "http://localstack:4566"
+	override := "http://override:4566"
+	pc := &v1beta1.ProviderConfig{
+		Spec: v1beta1.ProviderConfigSpec{
+			Endpoints: map[string]v1beta1.EndpointConfig{
+				"*":   {URL: localstack},
+				"EC2": {URL: "http://ec2:4566", SigningRegion: aws.String("us-gov-west-1"), PartitionID: aws.String("aws-us-gov")},
+			},
+		},
+	}
+	type args struct {
+		annotations map[string]string
+		service     string
+	}
+
+	cases := map[string]struct {
+		args
+		want aws.Endpoint
+	}{
+		"ServiceEndpoint": {
+			args: args{service: "ec2"},
+			want: aws.Endpoint{URL: "http://ec2:4566", SigningRegion: "us-gov-west-1", PartitionID: "aws-us-gov"},
+		},
+		"WildcardEndpoint": {
+			args: args{service: "S3"},
+			want: aws.Endpoint{URL: localstack, SigningRegion: "us-east-1"},
+		},
+		"AnnotationTakesPrecedence": {
+			args: args{
+				annotations: map[string]string{
+					"aws.alpha.crossplane.io/endpointServiceID": "S3",
+					"aws.alpha.crossplane.io/endpointURL":       override,
+				},
+				service: "S3",
+			},
+			want: aws.Endpoint{URL: override},
+		},
+		"AnnotationFallsBackToProviderConfig": {
+			args: args{
+				annotations: map[string]string{
+					"aws.alpha.crossplane.io/endpointServiceID": "S3",
+					"aws.alpha.crossplane.io/endpointURL":       override,
+				},
+				service: "SQS",
+			},
+			want: aws.Endpoint{URL: localstack, SigningRegion: "us-east-1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			meta.AddAnnotations(mg, tc.args.annotations)
+
+			cfg := SetResolver(context.TODO(), mg, SetEndpoints(pc, &aws.Config{}))
+			got, err := cfg.EndpointResolver.ResolveEndpoint(tc.args.service, "us-east-1")
+			if err != nil {
+				t.Fatalf("ResolveEndpoint(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+
+			cfgV1 := SetResolverV1(context.TODO(), mg, SetEndpointsV1(pc, awsv1.NewConfig()))
+			gotV1, err := cfgV1.EndpointResolver.EndpointFor(tc.args.service, "us-east-1")
+			if err != nil {
+				t.Fatalf("EndpointFor(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.URL, gotV1.URL); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCleanError(t *testing.T) {
+	apiErr := &smithy.GenericAPIError{Code: "InvalidClientTokenId", Message: "The security token included in the request is invalid."}
+	v2 := &smithy.OperationError{
+		ServiceID:     "STS",
+		OperationName: "GetCallerIdentity",
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+				Err:      apiErr,
+			},
+			RequestID: "1a2b3c",
+		},
+	}
+	v1 := awserr.NewRequestFailure(awserr.New("AccessDenied", "User is not authorized", nil), http.StatusForbidden, "1a2b3c")
+
+	type want struct {
+		msg  string
+		code string
+	}
+
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"SDKv2": {
+			err: v2,
+			want: want{
+				msg:  "operation error STS: GetCallerIdentity, https response error StatusCode: 403, api error InvalidClientTokenId: The security token included in the request is invalid.",
+				code: "InvalidClientTokenId",
+			},
+		},
+		"SDKv1": {
+			err: v1,
+			want: want{
+				msg:  "AccessDenied: User is not authorized\n\tstatus code: 403",
+				code: "AccessDenied",
+			},
+		},
+		"NoRequestID": {
+			err: errors.New("boom"),
+			want: want{
+				msg: "boom",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CleanError(tc.err)
+			if diff := cmp.Diff(tc.want.msg, err.Error()); diff != "" {
+				t.Errorf("CleanError(...).Error(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.code, ErrorCode(err)); diff != "" {
+				t.Errorf("ErrorCode(CleanError(...)): -want, +got:\n%s", diff)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("CleanError(...) does not wrap %v", tc.err)
+			}
+		})
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	type params struct {
+		Name *string           `json:"name,omitempty"`
+		Size *int64            `json:"size,omitempty"`
+		Tags map[string]string `json:"tags,omitempty"`
+	}
+
+	cases := map[string]struct {
+		observed params
+		desired  params
+		want     string
+	}{
+		"Identical": {
+			observed: params{Name: String("a"), Tags: map[string]string{"k": "v"}},
+			desired:  params{Name: String("a"), Tags: map[string]string{"k": "v"}},
+		},
+		"Different": {
+			observed: params{Name: String("a"), Size: Int64(1), Tags: map[string]string{"k": "v", "old": "v"}},
+			desired:  params{Name: String("b"), Tags: map[string]string{"k": "v"}},
+			want:     `{"name":"b","size":null,"tags":{"old":null}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := DiffJSON(tc.observed, tc.desired)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DiffJSON(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
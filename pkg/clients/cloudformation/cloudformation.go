@@ -18,70 +18,310 @@ package cloudformation
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	cf "github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// AnnotationKeyAppliedTemplateHash is the key of the annotation that records
+// that a change set from the current template and parameters of a stack to
+// its desired ones contained no changes, so that a stack whose templates only
+// differ textually is considered up to date.
+const AnnotationKeyAppliedTemplateHash = "aws.crossplane.io/applied-template-hash"
+
+const (
+	// MaxEvents is the maximum number of stack events that are reported in
+	// the observation of a stack.
+	MaxEvents = 10
+
+	// noEchoValue is the value AWS reports for the parameters that are
+	// declared with NoEcho.
+	noEchoValue = "****"
+
+	// changeSetPrefix is the prefix of the names of the change sets through
+	// which stacks are updated.
+	changeSetPrefix = "crossplane-"
+
+	errCodeValidation = "ValidationError"
 )
 
 // Client interface to perform CloudFormation operations
 type Client interface {
-	CreateStack(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error)
-	GetStack(stackID *string) (stack *cftypes.Stack, err error)
-	DeleteStack(stackID *string) error
+	CreateStack(ctx context.Context, input *cf.CreateStackInput, opts ...func(*cf.Options)) (*cf.CreateStackOutput, error)
+	DescribeStacks(ctx context.Context, input *cf.DescribeStacksInput, opts ...func(*cf.Options)) (*cf.DescribeStacksOutput, error)
+	DeleteStack(ctx context.Context, input *cf.DeleteStackInput, opts ...func(*cf.Options)) (*cf.DeleteStackOutput, error)
+	GetTemplate(ctx context.Context, input *cf.GetTemplateInput, opts ...func(*cf.Options)) (*cf.GetTemplateOutput, error)
+	DescribeStackEvents(ctx context.Context, input *cf.DescribeStackEventsInput, opts ...func(*cf.Options)) (*cf.DescribeStackEventsOutput, error)
+	DetectStackDrift(ctx context.Context, input *cf.DetectStackDriftInput, opts ...func(*cf.Options)) (*cf.DetectStackDriftOutput, error)
+	CreateChangeSet(ctx context.Context, input *cf.CreateChangeSetInput, opts ...func(*cf.Options)) (*cf.CreateChangeSetOutput, error)
+	DescribeChangeSet(ctx context.Context, input *cf.DescribeChangeSetInput, opts ...func(*cf.Options)) (*cf.DescribeChangeSetOutput, error)
+	ExecuteChangeSet(ctx context.Context, input *cf.ExecuteChangeSetInput, opts ...func(*cf.Options)) (*cf.ExecuteChangeSetOutput, error)
+	DeleteChangeSet(ctx context.Context, input *cf.DeleteChangeSetInput, opts ...func(*cf.Options)) (*cf.DeleteChangeSetOutput, error)
 }
 
-type cloudFormationClient struct {
-	cloudformation *cf.Client
+// NewClient return new instance of the crossplane client for a specific AWS configuration
+func NewClient(cfg aws.Config) Client {
+	return cf.NewFromConfig(cfg)
 }
 
-// NewClient return new instance of the crossplane client for a specific AWS configuration
-func NewClient(config *aws.Config) Client {
-	return &cloudFormationClient{cf.NewFromConfig(*config)}
+// GenerateCreateStackInput returns the input that creates the stack with the
+// supplied name and template.
+func GenerateCreateStackInput(name, template string, p v1alpha1.StackParameters) *cf.CreateStackInput {
+	return &cf.CreateStackInput{
+		StackName:    aws.String(name),
+		TemplateBody: aws.String(template),
+		Parameters:   generateParameters(p.Parameters),
+		Capabilities: generateCapabilities(p.Capabilities),
+		RoleARN:      p.RoleARN,
+		Tags:         generateTags(p.Tags),
+	}
+}
+
+// GenerateCreateChangeSetInput returns the input that creates the change set
+// that updates the stack with the supplied name to the supplied template.
+func GenerateCreateChangeSetInput(name, template string, p v1alpha1.StackParameters) *cf.CreateChangeSetInput {
+	return &cf.CreateChangeSetInput{
+		StackName:     aws.String(name),
+		ChangeSetName: aws.String(ChangeSetName(template, p)),
+		ChangeSetType: cftypes.ChangeSetTypeUpdate,
+		TemplateBody:  aws.String(template),
+		Parameters:    generateParameters(p.Parameters),
+		Capabilities:  generateCapabilities(p.Capabilities),
+		RoleARN:       p.RoleARN,
+		Tags:          generateTags(p.Tags),
+	}
+}
+
+// ChangeSetName returns the name of the change set that updates a stack to
+// the supplied template and parameters. The name is derived from them so
+// that the same change set is found again while it is being created.
+func ChangeSetName(template string, p v1alpha1.StackParameters) string {
+	h := sha256.New()
+	h.Write([]byte(template))
+	// Marshalling cannot fail since the parameters only consist of strings.
+	// Maps are marshalled with sorted keys, so the name is stable.
+	b, _ := json.Marshal(struct {
+		Parameters   map[string]string `json:"parameters"`
+		Capabilities []string          `json:"capabilities"`
+		RoleARN      *string           `json:"roleARN"`
+		Tags         map[string]string `json:"tags"`
+	}{p.Parameters, p.Capabilities, p.RoleARN, p.Tags})
+	h.Write(b)
+	return fmt.Sprintf("%s%x", changeSetPrefix, h.Sum(nil)[:8])
 }
 
-// CreateStack - Creates a stack
-func (c *cloudFormationClient) CreateStack(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error) {
-	cfParams := make([]cftypes.Parameter, 0)
-	for k, v := range parameters {
-		if v != "" {
-			cfParams = append(cfParams, cftypes.Parameter{ParameterKey: aws.String(k), ParameterValue: aws.String(v)})
+func generateParameters(params map[string]string) []cftypes.Parameter {
+	if len(params) == 0 {
+		return nil
+	}
+	res := make([]cftypes.Parameter, 0, len(params))
+	for k, v := range params {
+		res = append(res, cftypes.Parameter{ParameterKey: aws.String(k), ParameterValue: aws.String(v)})
+	}
+	sort.Slice(res, func(i, j int) bool { return aws.ToString(res[i].ParameterKey) < aws.ToString(res[j].ParameterKey) })
+	return res
+}
+
+func generateCapabilities(capabilities []string) []cftypes.Capability {
+	if len(capabilities) == 0 {
+		return nil
+	}
+	res := make([]cftypes.Capability, len(capabilities))
+	for i, c := range capabilities {
+		res[i] = cftypes.Capability(c)
+	}
+	return res
+}
+
+func generateTags(tags map[string]string) []cftypes.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	res := make([]cftypes.Tag, 0, len(tags))
+	for k, v := range tags {
+		res = append(res, cftypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	sort.Slice(res, func(i, j int) bool { return aws.ToString(res[i].Key) < aws.ToString(res[j].Key) })
+	return res
+}
+
+// GenerateObservation returns the observation of the supplied stack and its
+// supplied events, newest first.
+func GenerateObservation(stack cftypes.Stack, events []cftypes.StackEvent) v1alpha1.StackObservation {
+	o := v1alpha1.StackObservation{
+		StackID:           aws.ToString(stack.StackId),
+		StackStatus:       string(stack.StackStatus),
+		StackStatusReason: aws.ToString(stack.StackStatusReason),
+	}
+	if d := stack.DriftInformation; d != nil {
+		o.DriftStatus = string(d.StackDriftStatus)
+		if d.LastCheckTimestamp != nil {
+			t := metav1.NewTime(*d.LastCheckTimestamp)
+			o.LastDriftCheckTime = &t
+		}
+	}
+	if len(stack.Outputs) > 0 {
+		o.Outputs = make(map[string]string, len(stack.Outputs))
+		for _, out := range stack.Outputs {
+			o.Outputs[aws.ToString(out.OutputKey)] = aws.ToString(out.OutputValue)
 		}
 	}
+	if len(events) > MaxEvents {
+		events = events[:MaxEvents]
+	}
+	for _, e := range events {
+		se := v1alpha1.StackEvent{
+			LogicalResourceID:    aws.ToString(e.LogicalResourceId),
+			ResourceType:         aws.ToString(e.ResourceType),
+			ResourceStatus:       string(e.ResourceStatus),
+			ResourceStatusReason: aws.ToString(e.ResourceStatusReason),
+		}
+		if e.Timestamp != nil {
+			t := metav1.NewTime(*e.Timestamp)
+			se.Timestamp = &t
+		}
+		o.Events = append(o.Events, se)
+	}
+	return o
+}
 
-	createStackResponse, err := c.cloudformation.CreateStack(context.TODO(), &cf.CreateStackInput{Capabilities: []cftypes.Capability{cftypes.CapabilityCapabilityIam}, StackName: stackName, TemplateBody: templateBody, Parameters: cfParams})
-	if err != nil {
-		return nil, err
+// GetConnectionDetails returns the outputs of the supplied stack as
+// connection details.
+func GetConnectionDetails(stack cftypes.Stack) managed.ConnectionDetails {
+	if len(stack.Outputs) == 0 {
+		return nil
+	}
+	cd := make(managed.ConnectionDetails, len(stack.Outputs))
+	for _, out := range stack.Outputs {
+		cd[aws.ToString(out.OutputKey)] = []byte(aws.ToString(out.OutputValue))
 	}
-	return createStackResponse.StackId, nil
+	return cd
 }
 
-// GetStack info
-func (c *cloudFormationClient) GetStack(stackID *string) (stack *cftypes.Stack, err error) {
-	describeStackResponse, err := c.cloudformation.DescribeStacks(context.TODO(), &cf.DescribeStacksInput{StackName: stackID})
-	if err != nil {
-		return nil, err
+// IsUpToDate returns whether the supplied stack, whose current template is
+// the supplied current template, has the supplied desired template and
+// parameters. The templates are also considered equal if the supplied applied
+// hash records that a change set between them contained no changes. The values of the parameters that are declared with NoEcho
+// cannot be compared since AWS does not report them.
+func IsUpToDate(p v1alpha1.StackParameters, template string, stack cftypes.Stack, current, appliedHash string) bool {
+	if appliedHash != AppliedTemplateHash(template, p, current) && !EqualTemplates(template, current) {
+		return false
 	}
+	observed := make(map[string]string, len(stack.Parameters))
+	for _, param := range stack.Parameters {
+		observed[aws.ToString(param.ParameterKey)] = aws.ToString(param.ParameterValue)
+	}
+	for k, v := range p.Parameters {
+		o, ok := observed[k]
+		if !ok || (o != v && o != noEchoValue) {
+			return false
+		}
+	}
+	capabilities := make([]string, len(stack.Capabilities))
+	for i, c := range stack.Capabilities {
+		capabilities[i] = string(c)
+	}
+	if !equalSets(p.Capabilities, capabilities) {
+		return false
+	}
+	if aws.ToString(p.RoleARN) != aws.ToString(stack.RoleARN) {
+		return false
+	}
+	tags := make(map[string]string, len(stack.Tags))
+	for _, t := range stack.Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	add, remove := awsclient.DiffTags(p.Tags, tags)
+	return len(add) == 0 && len(remove) == 0
+}
 
-	// If fetching by name, then this might be a list.
-	// Since we're fetching by ID, it's either not found err above, or there's an item right here.
-	if len(describeStackResponse.Stacks) == 0 {
-		return nil, fmt.Errorf("stack unexpectedly not in response")
+// EqualTemplates returns whether the supplied templates are equal. JSON
+// templates are compared regardless of their whitespace and the order of
+// their keys, since AWS does not return them as they were submitted.
+func EqualTemplates(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var ja, jb interface{}
+	if json.Unmarshal([]byte(a), &ja) != nil || json.Unmarshal([]byte(b), &jb) != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
 	}
+	return reflect.DeepEqual(ja, jb)
+}
+
+// AppliedTemplateHash returns the hash that records that a change set from
+// the supplied current template to the supplied desired template and
+// parameters contained no changes. It changes with the current template, so
+// that a change of the stack outside of the provider is still detected.
+func AppliedTemplateHash(template string, p v1alpha1.StackParameters, current string) string {
+	h := sha256.New()
+	h.Write([]byte(ChangeSetName(template, p)))
+	h.Write([]byte(current))
+	return fmt.Sprintf("%x", h.Sum(nil)[:8])
+}
+
+func equalSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]struct{}, len(a))
+	for _, s := range a {
+		set[s] = struct{}{}
+	}
+	for _, s := range b {
+		if _, ok := set[s]; !ok {
+			return false
+		}
+	}
+	return true
+}
 
-	return &describeStackResponse.Stacks[0], nil
+// IsInProgress returns whether an operation on a stack with the supplied
+// status is in progress, in which case the stack cannot be updated.
+func IsInProgress(status cftypes.StackStatus) bool {
+	return strings.HasSuffix(string(status), "_IN_PROGRESS")
 }
 
-// DeleteStack deletes a stack
-func (c *cloudFormationClient) DeleteStack(stackID *string) error {
-	_, err := c.cloudformation.DeleteStack(context.TODO(), &cf.DeleteStackInput{StackName: stackID})
-	return err
+// IsAvailable returns whether the resources of a stack with the supplied
+// status are available.
+func IsAvailable(status cftypes.StackStatus) bool {
+	switch status { // nolint:exhaustive
+	case cftypes.StackStatusCreateComplete,
+		cftypes.StackStatusUpdateComplete,
+		cftypes.StackStatusUpdateRollbackComplete,
+		cftypes.StackStatusImportComplete,
+		cftypes.StackStatusImportRollbackComplete:
+		return true
+	}
+	return false
 }
 
-// IsErrorNotFound - not found error
+// IsErrorNotFound returns whether the supplied error is returned because the
+// stack does not exist.
 func IsErrorNotFound(err error) bool {
-	var inf *cftypes.StackInstanceNotFoundException
-	return errors.As(err, &inf)
+	return awsclient.ErrorCode(err) == errCodeValidation && strings.Contains(err.Error(), "does not exist")
+}
+
+// IsChangeSetNotFound returns whether the supplied error is returned because
+// the change set does not exist.
+func IsChangeSetNotFound(err error) bool {
+	return awsclient.ErrorCode(err) == (&cftypes.ChangeSetNotFoundException{}).ErrorCode()
+}
+
+// IsNoChanges returns whether a change set failed with the supplied reason
+// because it does not contain any changes.
+func IsNoChanges(reason string) bool {
+	return strings.Contains(reason, "didn't contain changes") || strings.Contains(reason, "No updates are to be performed")
 }
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
+)
+
+var template = "Resources: {}"
+
+func TestIsUpToDate(t *testing.T) {
+	observed := cftypes.Stack{
+		Parameters: []cftypes.Parameter{
+			{ParameterKey: aws.String("Env"), ParameterValue: aws.String("prod")},
+			{ParameterKey: aws.String("Password"), ParameterValue: aws.String(noEchoValue)},
+			{ParameterKey: aws.String("Size"), ParameterValue: aws.String("small")},
+		},
+		Capabilities: []cftypes.Capability{cftypes.CapabilityCapabilityIam},
+		Tags:         []cftypes.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+	}
+	desired := func(m ...func(*v1alpha1.StackParameters)) v1alpha1.StackParameters {
+		p := v1alpha1.StackParameters{
+			Parameters:   map[string]string{"Env": "prod", "Password": "secret"},
+			Capabilities: []string{"CAPABILITY_IAM"},
+			Tags:         map[string]string{"team": "a"},
+		}
+		for _, f := range m {
+			f(&p)
+		}
+		return p
+	}
+
+	cases := map[string]struct {
+		p       v1alpha1.StackParameters
+		current string
+		applied string
+		want    bool
+	}{
+		"UpToDate": {
+			p:       desired(),
+			current: template,
+			want:    true,
+		},
+		"TemplateChanged": {
+			p:       desired(),
+			current: "Resources: {Old: {}}",
+		},
+		"TemplateApplied": {
+			p:       desired(),
+			current: "Resources:\n  {}\n",
+			applied: AppliedTemplateHash(template, desired(), "Resources:\n  {}\n"),
+			want:    true,
+		},
+		"TemplateChangedAfterApplied": {
+			p:       desired(),
+			current: "Resources: {Old: {}}",
+			applied: AppliedTemplateHash(template, desired(), "Resources:\n  {}\n"),
+		},
+		"ParameterChanged": {
+			p:       desired(func(p *v1alpha1.StackParameters) { p.Parameters["Env"] = "dev" }),
+			current: template,
+		},
+		"ParameterAdded": {
+			p:       desired(func(p *v1alpha1.StackParameters) { p.Parameters["Region"] = "eu" }),
+			current: template,
+		},
+		"CapabilityChanged": {
+			p:       desired(func(p *v1alpha1.StackParameters) { p.Capabilities = []string{"CAPABILITY_NAMED_IAM"} }),
+			current: template,
+		},
+		"TagsChanged": {
+			p:       desired(func(p *v1alpha1.StackParameters) { p.Tags["team"] = "b" }),
+			current: template,
+		},
+		"RoleChanged": {
+			p:       desired(func(p *v1alpha1.StackParameters) { p.RoleARN = aws.String("arn:aws:iam::123456789012:role/cfn") }),
+			current: template,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsUpToDate(tc.p, template, observed, tc.current, tc.applied)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEqualTemplates(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"Identical": {
+			a:    template,
+			b:    template,
+			want: true,
+		},
+		"JSONWhitespaceAndKeyOrder": {
+			a:    `{"Resources": {"Bucket": {"Type": "AWS::S3::Bucket"}}, "Outputs": {}}`,
+			b:    "{\n  \"Outputs\": {},\n  \"Resources\": {\n    \"Bucket\": {\"Type\": \"AWS::S3::Bucket\"}\n  }\n}\n",
+			want: true,
+		},
+		"JSONChanged": {
+			a: `{"Resources": {"Bucket": {"Type": "AWS::S3::Bucket"}}}`,
+			b: `{"Resources": {"Queue": {"Type": "AWS::SQS::Queue"}}}`,
+		},
+		"YAMLTrailingNewline": {
+			a:    template,
+			b:    template + "\n",
+			want: true,
+		},
+		"YAMLChanged": {
+			a: template,
+			b: "Resources: {Old: {}}",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, EqualTemplates(tc.a, tc.b)); diff != "" {
+				t.Errorf("EqualTemplates(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangeSetName(t *testing.T) {
+	p := v1alpha1.StackParameters{Parameters: map[string]string{"a": "1", "b": "2"}}
+
+	name := ChangeSetName(template, p)
+	if !strings.HasPrefix(name, changeSetPrefix) {
+		t.Errorf("ChangeSetName(...): %q does not have prefix %q", name, changeSetPrefix)
+	}
+	if diff := cmp.Diff(name, ChangeSetName(template, *p.DeepCopy())); diff != "" {
+		t.Errorf("ChangeSetName(...): -want, +got:\n%s", diff)
+	}
+	p.Parameters["a"] = "3"
+	if ChangeSetName(template, p) == name {
+		t.Errorf("ChangeSetName(...): name did not change with the parameters")
+	}
+}
+
+func TestGenerateObservation(t *testing.T) {
+	events := make([]cftypes.StackEvent, MaxEvents+1)
+	want := make([]v1alpha1.StackEvent, MaxEvents)
+	for i := range events {
+		events[i] = cftypes.StackEvent{LogicalResourceId: aws.String("Bucket"), ResourceStatus: cftypes.ResourceStatusCreateComplete}
+	}
+	for i := range want {
+		want[i] = v1alpha1.StackEvent{LogicalResourceID: "Bucket", ResourceStatus: "CREATE_COMPLETE"}
+	}
+
+	got := GenerateObservation(cftypes.Stack{
+		StackId:     aws.String("id"),
+		StackStatus: cftypes.StackStatusCreateComplete,
+		Outputs:     []cftypes.Output{{OutputKey: aws.String("BucketName"), OutputValue: aws.String("bucket")}},
+	}, events)
+
+	if diff := cmp.Diff(v1alpha1.StackObservation{
+		StackID:     "id",
+		StackStatus: "CREATE_COMPLETE",
+		Outputs:     map[string]string{"BucketName": "bucket"},
+		Events:      want,
+	}, got); diff != "" {
+		t.Errorf("GenerateObservation(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestIsErrorNotFound(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"NotFound": {
+			err:  &smithy.GenericAPIError{Code: errCodeValidation, Message: "Stack with id some-stack does not exist"},
+			want: true,
+		},
+		"OtherValidationError": {
+			err: &smithy.GenericAPIError{Code: errCodeValidation, Message: "Template format error"},
+		},
+		"OtherError": {
+			err: errors.New("boom"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, IsErrorNotFound(tc.err)); diff != "" {
+				t.Errorf("IsErrorNotFound(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -17,27 +17,71 @@ limitations under the License.
 package fake
 
 import (
-	cloudformationtypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"context"
+
+	cf "github.com/aws/aws-sdk-go-v2/service/cloudformation"
 )
 
 // MockCloudFormationClient mock
 type MockCloudFormationClient struct {
-	MockCreateStack func(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error)
-	MockGetStack    func(stackID *string) (status *cloudformationtypes.Stack, err error)
-	MockDeleteStack func(stackID *string) error
+	MockCreateStack         func(ctx context.Context, input *cf.CreateStackInput, opts []func(*cf.Options)) (*cf.CreateStackOutput, error)
+	MockDescribeStacks      func(ctx context.Context, input *cf.DescribeStacksInput, opts []func(*cf.Options)) (*cf.DescribeStacksOutput, error)
+	MockDeleteStack         func(ctx context.Context, input *cf.DeleteStackInput, opts []func(*cf.Options)) (*cf.DeleteStackOutput, error)
+	MockGetTemplate         func(ctx context.Context, input *cf.GetTemplateInput, opts []func(*cf.Options)) (*cf.GetTemplateOutput, error)
+	MockDescribeStackEvents func(ctx context.Context, input *cf.DescribeStackEventsInput, opts []func(*cf.Options)) (*cf.DescribeStackEventsOutput, error)
+	MockDetectStackDrift    func(ctx context.Context, input *cf.DetectStackDriftInput, opts []func(*cf.Options)) (*cf.DetectStackDriftOutput, error)
+	MockCreateChangeSet     func(ctx context.Context, input *cf.CreateChangeSetInput, opts []func(*cf.Options)) (*cf.CreateChangeSetOutput, error)
+	MockDescribeChangeSet   func(ctx context.Context, input *cf.DescribeChangeSetInput, opts []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error)
+	MockExecuteChangeSet    func(ctx context.Context, input *cf.ExecuteChangeSetInput, opts []func(*cf.Options)) (*cf.ExecuteChangeSetOutput, error)
+	MockDeleteChangeSet     func(ctx context.Context, input *cf.DeleteChangeSetInput, opts []func(*cf.Options)) (*cf.DeleteChangeSetOutput, error)
 }
 
 // CreateStack mock
-func (m *MockCloudFormationClient) CreateStack(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error) {
-	return m.MockCreateStack(stackName, templateBody, parameters)
+func (m *MockCloudFormationClient) CreateStack(ctx context.Context, input *cf.CreateStackInput, opts ...func(*cf.Options)) (*cf.CreateStackOutput, error) {
+	return m.MockCreateStack(ctx, input, opts)
 }
 
-// GetStack mock
-func (m *MockCloudFormationClient) GetStack(stackID *string) (status *cloudformationtypes.Stack, err error) {
-	return m.MockGetStack(stackID)
+// DescribeStacks mock
+func (m *MockCloudFormationClient) DescribeStacks(ctx context.Context, input *cf.DescribeStacksInput, opts ...func(*cf.Options)) (*cf.DescribeStacksOutput, error) {
+	return m.MockDescribeStacks(ctx, input, opts)
 }
 
 // DeleteStack mock
-func (m *MockCloudFormationClient) DeleteStack(stackID *string) error {
-	return m.MockDeleteStack(stackID)
+func (m *MockCloudFormationClient) DeleteStack(ctx context.Context, input *cf.DeleteStackInput, opts ...func(*cf.Options)) (*cf.DeleteStackOutput, error) {
+	return m.MockDeleteStack(ctx, input, opts)
+}
+
+// GetTemplate mock
+func (m *MockCloudFormationClient) GetTemplate(ctx context.Context, input *cf.GetTemplateInput, opts ...func(*cf.Options)) (*cf.GetTemplateOutput, error) {
+	return m.MockGetTemplate(ctx, input, opts)
+}
+
+// DescribeStackEvents mock
+func (m *MockCloudFormationClient) DescribeStackEvents(ctx context.Context, input *cf.DescribeStackEventsInput, opts ...func(*cf.Options)) (*cf.DescribeStackEventsOutput, error) {
+	return m.MockDescribeStackEvents(ctx, input, opts)
+}
+
+// DetectStackDrift mock
+func (m *MockCloudFormationClient) DetectStackDrift(ctx context.Context, input *cf.DetectStackDriftInput, opts ...func(*cf.Options)) (*cf.DetectStackDriftOutput, error) {
+	return m.MockDetectStackDrift(ctx, input, opts)
+}
+
+// CreateChangeSet mock
+func (m *MockCloudFormationClient) CreateChangeSet(ctx context.Context, input *cf.CreateChangeSetInput, opts ...func(*cf.Options)) (*cf.CreateChangeSetOutput, error) {
+	return m.MockCreateChangeSet(ctx, input, opts)
+}
+
+// DescribeChangeSet mock
+func (m *MockCloudFormationClient) DescribeChangeSet(ctx context.Context, input *cf.DescribeChangeSetInput, opts ...func(*cf.Options)) (*cf.DescribeChangeSetOutput, error) {
+	return m.MockDescribeChangeSet(ctx, input, opts)
+}
+
+// ExecuteChangeSet mock
+func (m *MockCloudFormationClient) ExecuteChangeSet(ctx context.Context, input *cf.ExecuteChangeSetInput, opts ...func(*cf.Options)) (*cf.ExecuteChangeSetOutput, error) {
+	return m.MockExecuteChangeSet(ctx, input, opts)
+}
+
+// DeleteChangeSet mock
+func (m *MockCloudFormationClient) DeleteChangeSet(ctx context.Context, input *cf.DeleteChangeSetInput, opts ...func(*cf.Options)) (*cf.DeleteChangeSetOutput, error) {
+	return m.MockDeleteChangeSet(ctx, input, opts)
 }
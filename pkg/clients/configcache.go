@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+// Labels of the config cache metrics.
+const (
+	sdkV1 = "v1"
+	sdkV2 = "v2"
+
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+var configCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "crossplane_aws_config_cache_lookups_total",
+	Help: "Number of lookups of AWS client configs in the shared cache, by SDK and result.",
+}, []string{"sdk", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(configCacheLookups)
+}
+
+// configs is the process-wide cache of AWS client configs.
+var configs = newConfigCache()
+
+// A configKey identifies the configs built for a region and the endpoint
+// annotations of a resource from a ProviderConfig.
+type configKey struct {
+	providerConfig string
+	region         string
+	endpoint       string
+}
+
+// newConfigKey returns the key of the configs built for the supplied
+// managed resource from the supplied ProviderConfig.
+func newConfigKey(pc *v1beta1.ProviderConfig, mg resource.Managed, region string) configKey {
+	a := mg.GetAnnotations()
+	return configKey{
+		providerConfig: pc.GetName(),
+		region:         region,
+		endpoint: strings.Join([]string{
+			a["aws.alpha.crossplane.io/endpointServiceID"],
+			a["aws.alpha.crossplane.io/endpointURL"],
+			a["aws.alpha.crossplane.io/endpointSigningRegion"],
+		}, "/"),
+	}
+}
+
+// configVersion returns a version that changes whenever the supplied
+// ProviderConfig or credentials change.
+func configVersion(pc *v1beta1.ProviderConfig, data []byte) string {
+	sum := sha256.Sum256(data)
+	return pc.GetResourceVersion() + "/" + hex.EncodeToString(sum[:])
+}
+
+// A configCache caches the configs built from every ProviderConfig. Only the
+// configs of the latest version of a ProviderConfig are kept, so a change to
+// a ProviderConfig or its credentials evicts all the configs built from it.
+type configCache struct {
+	mu      sync.Mutex
+	configs map[string]*versionedConfigs
+}
+
+// versionedConfigs are the configs built from a version of a ProviderConfig.
+type versionedConfigs struct {
+	version string
+	v2      map[configKey]*aws.Config
+	v1      map[configKey]*session.Session
+}
+
+func newConfigCache() *configCache {
+	return &configCache{configs: map[string]*versionedConfigs{}}
+}
+
+// current returns the configs built from the supplied version of the
+// supplied ProviderConfig, evicting those built from any other version.
+func (c *configCache) current(pc, version string) *versionedConfigs {
+	e, ok := c.configs[pc]
+	if !ok || e.version != version {
+		e = &versionedConfigs{version: version, v2: map[configKey]*aws.Config{}, v1: map[configKey]*session.Session{}}
+		c.configs[pc] = e
+	}
+	return e
+}
+
+// forget evicts the configs built from the supplied ProviderConfig.
+func (c *configCache) forget(pc string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.configs, pc)
+}
+
+// getV2 returns a copy of the config cached for the supplied key and version.
+func (c *configCache) getV2(k configKey, version string) (*aws.Config, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg, ok := c.current(k.providerConfig, version).v2[k]
+	if !ok {
+		configCacheLookups.WithLabelValues(sdkV2, cacheMiss).Inc()
+		return nil, false
+	}
+	configCacheLookups.WithLabelValues(sdkV2, cacheHit).Inc()
+	return copyConfig(cfg), true
+}
+
+// setV2 caches the supplied config and returns a copy of it.
+func (c *configCache) setV2(k configKey, version string, cfg *aws.Config) *aws.Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current(k.providerConfig, version).v2[k] = cfg
+	return copyConfig(cfg)
+}
+
+// copyConfig returns a copy of the supplied config whose API options can be
+// appended to without affecting the original.
+func copyConfig(cfg *aws.Config) *aws.Config {
+	cp := cfg.Copy()
+	cp.APIOptions = append(make([]func(*middleware.Stack) error, 0, len(cfg.APIOptions)), cfg.APIOptions...)
+	return &cp
+}
+
+// getV1 returns a copy of the session cached for the supplied key and
+// version.
+func (c *configCache) getV1(k configKey, version string) (*session.Session, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sess, ok := c.current(k.providerConfig, version).v1[k]
+	if !ok {
+		configCacheLookups.WithLabelValues(sdkV1, cacheMiss).Inc()
+		return nil, false
+	}
+	configCacheLookups.WithLabelValues(sdkV1, cacheHit).Inc()
+	return sess.Copy(), true
+}
+
+// setV1 caches the supplied session and returns a copy of it.
+func (c *configCache) setV1(k configKey, version string, sess *session.Session) *session.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current(k.providerConfig, version).v1[k] = sess
+	return sess.Copy()
+}
+
+// ForgetProviderConfig evicts the configs, the assumed role credentials and
+// the account ID cached for the supplied ProviderConfig, e.g. because it was
+// deleted.
+func ForgetProviderConfig(name string) {
+	configs.forget(name)
+	accountIDs.forget(name)
+	assumedRoles.forget(name)
+	webIdentityRoles.forget(name)
+}
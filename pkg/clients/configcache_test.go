@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+func TestUseProviderConfigCache(t *testing.T) {
+	pc := v1beta1.ProviderConfig{}
+	pc.SetName("cache-test")
+	pc.SetResourceVersion("1")
+	pc.Spec.Credentials = v1beta1.ProviderCredentials{
+		Source: xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+			SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "creds"},
+				Key:             "credentials",
+			},
+		},
+	}
+	accessKeyID := "first"
+	kube := test.NewMockClient()
+	kube.MockGet = func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1beta1.ProviderConfig:
+			pc.DeepCopyInto(o)
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"credentials": []byte(fmt.Sprintf(awsCredentialsFileFormat, "default", accessKeyID, "secret"))}
+		}
+		return nil
+	}
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}}
+
+	lookups := func(result string) float64 {
+		return testutil.ToFloat64(configCacheLookups.WithLabelValues(sdkV2, result))
+	}
+	retrieve := func() string {
+		cfg, err := UseProviderConfig(context.TODO(), kube, mg, "us-east-1")
+		if err != nil {
+			t.Fatalf("UseProviderConfig(...): %s", err)
+		}
+		creds, err := cfg.Credentials.Retrieve(context.TODO())
+		if err != nil {
+			t.Fatalf("Retrieve(...): %s", err)
+		}
+		return creds.AccessKeyID
+	}
+
+	hits, misses := lookups(cacheHit), lookups(cacheMiss)
+	for _, want := range []string{"first", "first"} {
+		if diff := cmp.Diff(want, retrieve()); diff != "" {
+			t.Errorf("r: -want, +got:\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff([]float64{hits + 1, misses + 1}, []float64{lookups(cacheHit), lookups(cacheMiss)}); diff != "" {
+		t.Errorf("lookups: -want, +got:\n%s", diff)
+	}
+
+	// A change to the credentials must invalidate the cached config.
+	accessKeyID = "second"
+	if diff := cmp.Diff("second", retrieve()); diff != "" {
+		t.Errorf("r: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(misses+2, lookups(cacheMiss)); diff != "" {
+		t.Errorf("misses: -want, +got:\n%s", diff)
+	}
+}
+
+func TestConfigCacheEviction(t *testing.T) {
+	key := func(pc, region string) configKey {
+		return configKey{providerConfig: pc, region: region}
+	}
+	cached := func(c *configCache) map[string]int {
+		n := map[string]int{}
+		for pc, e := range c.configs {
+			n[pc] = len(e.v2)
+		}
+		return n
+	}
+
+	c := newConfigCache()
+	c.setV2(key("a", "us-east-1"), "1", &aws.Config{})
+	c.setV2(key("a", "eu-west-1"), "1", &aws.Config{})
+	c.setV2(key("b", "us-east-1"), "1", &aws.Config{})
+	if diff := cmp.Diff(map[string]int{"a": 2, "b": 1}, cached(c)); diff != "" {
+		t.Errorf("cached: -want, +got:\n%s", diff)
+	}
+
+	// A new version of a ProviderConfig evicts the configs of all regions
+	// built from its previous version.
+	if _, ok := c.getV2(key("a", "us-east-1"), "2"); ok {
+		t.Errorf("getV2(...): config of a previous version was returned")
+	}
+	if diff := cmp.Diff(map[string]int{"a": 0, "b": 1}, cached(c)); diff != "" {
+		t.Errorf("cached: -want, +got:\n%s", diff)
+	}
+
+	// A deleted ProviderConfig evicts all its configs.
+	c.forget("b")
+	if diff := cmp.Diff(map[string]int{"a": 0}, cached(c)); diff != "" {
+		t.Errorf("cached: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package connection publishes the connection details of managed resources
+// to AWS Secrets Manager and SSM Parameter Store.
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+const (
+	// AnnotationKeySecretsManagerSecret is the key of the annotation that
+	// holds the name of the Secrets Manager secret to which the connection
+	// details of a managed resource are published as a JSON object.
+	AnnotationKeySecretsManagerSecret = "aws.crossplane.io/connection-secretsmanager-secret"
+
+	// AnnotationKeySSMPath is the key of the annotation that holds the path
+	// under which the connection details of a managed resource are published
+	// as SSM SecureString parameters, one per key.
+	AnnotationKeySSMPath = "aws.crossplane.io/connection-ssm-path"
+
+	// TagKeyOwner is the key of the tag that marks the Secrets Manager
+	// secrets and SSM parameters created for a managed resource. Its value is
+	// the UID of the managed resource. Only tagged secrets and parameters are
+	// deleted when the connection details are unpublished.
+	TagKeyOwner = "crossplane-connection-owner"
+)
+
+const (
+	errGetConfig          = "cannot get AWS config"
+	errDescribeSecret     = "cannot describe Secrets Manager secret"
+	errRestoreSecret      = "cannot restore Secrets Manager secret"
+	errGetSecretValue     = "cannot get Secrets Manager secret value"
+	errParseSecretValue   = "cannot parse Secrets Manager secret value as a JSON object"
+	errMarshalSecretValue = "cannot marshal connection details"
+	errCreateSecret       = "cannot create Secrets Manager secret"
+	errPutSecretValue     = "cannot put Secrets Manager secret value"
+	errDeleteSecret       = "cannot delete Secrets Manager secret"
+	errGetParameters      = "cannot get SSM parameters"
+	errDescribeParameters = "cannot describe SSM parameters"
+	errPutParameter       = "cannot put SSM parameter"
+	errDeleteParameters   = "cannot delete SSM parameters"
+
+	// maxDeleteParameters is the maximum number of parameters that can be
+	// deleted in a single call.
+	maxDeleteParameters = 10
+
+	// recoveryWindowInDays is the number of days during which a deleted
+	// Secrets Manager secret can still be restored. It is the minimum that
+	// Secrets Manager allows.
+	recoveryWindowInDays = 7
+)
+
+// SecretsManagerClient is the Secrets Manager API used to publish connection
+// details.
+type SecretsManagerClient interface {
+	DescribeSecretWithContext(ctx aws.Context, input *secretsmanager.DescribeSecretInput, opts ...request.Option) (*secretsmanager.DescribeSecretOutput, error)
+	RestoreSecretWithContext(ctx aws.Context, input *secretsmanager.RestoreSecretInput, opts ...request.Option) (*secretsmanager.RestoreSecretOutput, error)
+	GetSecretValueWithContext(ctx aws.Context, input *secretsmanager.GetSecretValueInput, opts ...request.Option) (*secretsmanager.GetSecretValueOutput, error)
+	CreateSecretWithContext(ctx aws.Context, input *secretsmanager.CreateSecretInput, opts ...request.Option) (*secretsmanager.CreateSecretOutput, error)
+	PutSecretValueWithContext(ctx aws.Context, input *secretsmanager.PutSecretValueInput, opts ...request.Option) (*secretsmanager.PutSecretValueOutput, error)
+	DeleteSecretWithContext(ctx aws.Context, input *secretsmanager.DeleteSecretInput, opts ...request.Option) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+// SSMClient is the SSM API used to publish connection details.
+type SSMClient interface {
+	GetParametersByPathPagesWithContext(ctx aws.Context, input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool, opts ...request.Option) error
+	DescribeParametersPagesWithContext(ctx aws.Context, input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool, opts ...request.Option) error
+	PutParameterWithContext(ctx aws.Context, input *ssm.PutParameterInput, opts ...request.Option) (*ssm.PutParameterOutput, error)
+	DeleteParametersWithContext(ctx aws.Context, input *ssm.DeleteParametersInput, opts ...request.Option) (*ssm.DeleteParametersOutput, error)
+}
+
+// A Publisher publishes the connection details of managed resources to the
+// Secrets Manager secret and the SSM parameter path named by their
+// annotations, using the credentials of their ProviderConfig. It does nothing
+// for managed resources without these annotations, so it is meant to be
+// chained with the Kubernetes secret publisher.
+type Publisher struct {
+	kube              client.Client
+	getConfig         func(ctx context.Context, c client.Client, mg resource.Managed, region string) (*session.Session, error)
+	newSecretsManager func(*session.Session) SecretsManagerClient
+	newSSM            func(*session.Session) SSMClient
+}
+
+// NewPublisher returns a Publisher that reads the ProviderConfigs of managed
+// resources with the supplied client.
+func NewPublisher(kube client.Client) *Publisher {
+	return &Publisher{
+		kube:              kube,
+		getConfig:         awsclient.GetConfigV1,
+		newSecretsManager: func(s *session.Session) SecretsManagerClient { return secretsmanager.New(s) },
+		newSSM:            func(s *session.Session) SSMClient { return ssm.New(s) },
+	}
+}
+
+// PublishConnection publishes the supplied connection details of the supplied
+// managed resource. Publishing is additive; keys that are published but not
+// supplied are left alone.
+func (p *Publisher) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	secret, path := destinations(mg)
+	if len(c) == 0 || (secret == "" && path == "") {
+		return nil
+	}
+	sess, err := p.getConfig(ctx, p.kube, mg, region(mg))
+	if err != nil {
+		return errors.Wrap(err, errGetConfig)
+	}
+	owner := string(mg.GetUID())
+	if secret != "" {
+		if err := publishSecret(ctx, p.newSecretsManager(sess), secret, owner, c); err != nil {
+			return err
+		}
+	}
+	if path != "" {
+		return publishParameters(ctx, p.newSSM(sess), path, owner, c)
+	}
+	return nil
+}
+
+// UnpublishConnection deletes the Secrets Manager secret and the SSM
+// parameters that were created for the supplied managed resource. The
+// supplied connection details are removed from a secret that was not
+// created for it, and their parameters are deleted.
+func (p *Publisher) UnpublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	secret, path := destinations(mg)
+	if secret == "" && path == "" {
+		return nil
+	}
+	sess, err := p.getConfig(ctx, p.kube, mg, region(mg))
+	if err != nil {
+		return errors.Wrap(err, errGetConfig)
+	}
+	owner := string(mg.GetUID())
+	if secret != "" {
+		if err := unpublishSecret(ctx, p.newSecretsManager(sess), secret, owner, c); err != nil {
+			return err
+		}
+	}
+	if path != "" {
+		return unpublishParameters(ctx, p.newSSM(sess), path, owner, c)
+	}
+	return nil
+}
+
+// destinations returns the Secrets Manager secret and the SSM parameter path
+// to which the connection details of the supplied managed resource are
+// published.
+func destinations(mg resource.Managed) (secret, path string) {
+	a := mg.GetAnnotations()
+	return a[AnnotationKeySecretsManagerSecret], strings.TrimSuffix(a[AnnotationKeySSMPath], "/")
+}
+
+// region returns the region of the external resource of the supplied managed
+// resource, or an empty string to use the region of its ProviderConfig.
+func region(mg resource.Managed) string {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return ""
+	}
+	r, _ := fieldpath.Pave(u).GetString("spec.forProvider.region")
+	return r
+}
+
+// describeSecret returns the description of the supplied secret, or nil if
+// it does not exist.
+func describeSecret(ctx context.Context, sm SecretsManagerClient, name string) (*secretsmanager.DescribeSecretOutput, error) {
+	out, err := sm.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(name)})
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return out, awsclient.Wrap(err, errDescribeSecret)
+}
+
+// getSecretValues returns the JSON object stored in the supplied secret.
+func getSecretValues(ctx context.Context, sm SecretsManagerClient, name string) (map[string]string, error) {
+	values := map[string]string{}
+	out, err := sm.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		return nil, awsclient.Wrap(err, errGetSecretValue)
+	}
+	if aws.StringValue(out.SecretString) == "" {
+		return values, nil
+	}
+	return values, errors.Wrap(json.Unmarshal([]byte(aws.StringValue(out.SecretString)), &values), errParseSecretValue)
+}
+
+func putSecretValues(ctx context.Context, sm SecretsManagerClient, name string, values map[string]string) error {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, errMarshalSecretValue)
+	}
+	_, err = sm.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{SecretId: aws.String(name), SecretString: aws.String(string(b))})
+	return awsclient.Wrap(err, errPutSecretValue)
+}
+
+func publishSecret(ctx context.Context, sm SecretsManagerClient, name, owner string, c managed.ConnectionDetails) error {
+	desc, err := describeSecret(ctx, sm, name)
+	if err != nil {
+		return err
+	}
+	if desc == nil {
+		values := make(map[string]string, len(c))
+		for k, v := range c {
+			values[k] = string(v)
+		}
+		b, err := json.Marshal(values)
+		if err != nil {
+			return errors.Wrap(err, errMarshalSecretValue)
+		}
+		_, err = sm.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+			Name:         aws.String(name),
+			SecretString: aws.String(string(b)),
+			Tags:         []*secretsmanager.Tag{{Key: aws.String(TagKeyOwner), Value: aws.String(owner)}},
+		})
+		return awsclient.Wrap(err, errCreateSecret)
+	}
+	// A secret that we deleted is only scheduled for deletion, and has to be
+	// restored before it can be written to again.
+	if desc.DeletedDate != nil && ownedSecret(desc, owner) {
+		if _, err := sm.RestoreSecretWithContext(ctx, &secretsmanager.RestoreSecretInput{SecretId: aws.String(name)}); err != nil {
+			return awsclient.Wrap(err, errRestoreSecret)
+		}
+	}
+	values, err := getSecretValues(ctx, sm, name)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for k, v := range c {
+		if cur, ok := values[k]; !ok || cur != string(v) {
+			values[k] = string(v)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return putSecretValues(ctx, sm, name, values)
+}
+
+func unpublishSecret(ctx context.Context, sm SecretsManagerClient, name, owner string, c managed.ConnectionDetails) error {
+	desc, err := describeSecret(ctx, sm, name)
+	if err != nil || desc == nil || desc.DeletedDate != nil {
+		return err
+	}
+	if ownedSecret(desc, owner) {
+		_, err := sm.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{
+			SecretId:             aws.String(name),
+			RecoveryWindowInDays: aws.Int64(recoveryWindowInDays),
+		})
+		return awsclient.Wrap(resource.Ignore(isNotFound, err), errDeleteSecret)
+	}
+	// We did not create this secret, so we only remove the keys that we
+	// published to it.
+	if len(c) == 0 {
+		return nil
+	}
+	values, err := getSecretValues(ctx, sm, name)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for k := range c {
+		if _, ok := values[k]; ok {
+			delete(values, k)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return putSecretValues(ctx, sm, name, values)
+}
+
+// ownedSecret returns true if the supplied secret was created for the managed
+// resource with the supplied UID.
+func ownedSecret(desc *secretsmanager.DescribeSecretOutput, owner string) bool {
+	for _, t := range desc.Tags {
+		if aws.StringValue(t.Key) == TagKeyOwner {
+			return owner != "" && aws.StringValue(t.Value) == owner
+		}
+	}
+	return false
+}
+
+// getParameters returns the decrypted values of the parameters directly
+// under the supplied path by their name.
+func getParameters(ctx context.Context, s SSMClient, path string) (map[string]string, error) {
+	params := map[string]string{}
+	err := s.GetParametersByPathPagesWithContext(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		WithDecryption: aws.Bool(true),
+	}, func(out *ssm.GetParametersByPathOutput, _ bool) bool {
+		for _, p := range out.Parameters {
+			params[aws.StringValue(p.Name)] = aws.StringValue(p.Value)
+		}
+		return true
+	})
+	return params, awsclient.Wrap(err, errGetParameters)
+}
+
+func publishParameters(ctx context.Context, s SSMClient, path, owner string, c managed.ConnectionDetails) error {
+	params, err := getParameters(ctx, s, path)
+	if err != nil {
+		return err
+	}
+	for k, v := range c {
+		name := path + "/" + k
+		cur, exists := params[name]
+		if exists && cur == string(v) {
+			continue
+		}
+		in := &ssm.PutParameterInput{
+			Name:  aws.String(name),
+			Value: aws.String(string(v)),
+			Type:  aws.String(ssm.ParameterTypeSecureString),
+		}
+		// SSM only accepts tags when a parameter is created.
+		if exists {
+			in.Overwrite = aws.Bool(true)
+		} else {
+			in.Tags = []*ssm.Tag{{Key: aws.String(TagKeyOwner), Value: aws.String(owner)}}
+		}
+		if _, err := s.PutParameterWithContext(ctx, in); err != nil {
+			return awsclient.Wrap(err, errPutParameter)
+		}
+	}
+	return nil
+}
+
+// ownedParameters returns the names of the parameters directly under the
+// supplied path that were created for the managed resource with the supplied
+// UID.
+func ownedParameters(ctx context.Context, s SSMClient, path, owner string) ([]string, error) {
+	var names []string
+	if owner == "" {
+		return names, nil
+	}
+	err := s.DescribeParametersPagesWithContext(ctx, &ssm.DescribeParametersInput{
+		ParameterFilters: []*ssm.ParameterStringFilter{
+			{Key: aws.String("Path"), Option: aws.String("OneLevel"), Values: []*string{aws.String(path)}},
+			{Key: aws.String("tag:" + TagKeyOwner), Values: []*string{aws.String(owner)}},
+		},
+	}, func(out *ssm.DescribeParametersOutput, _ bool) bool {
+		for _, p := range out.Parameters {
+			names = append(names, aws.StringValue(p.Name))
+		}
+		return true
+	})
+	return names, awsclient.Wrap(err, errDescribeParameters)
+}
+
+func unpublishParameters(ctx context.Context, s SSMClient, path, owner string, c managed.ConnectionDetails) error {
+	owned, err := ownedParameters(ctx, s, path, owner)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	names := make([]*string, 0, len(owned)+len(c))
+	for _, n := range owned {
+		seen[n] = true
+		names = append(names, aws.String(n))
+	}
+	for k := range c {
+		if n := path + "/" + k; !seen[n] {
+			seen[n] = true
+			names = append(names, aws.String(n))
+		}
+	}
+	for len(names) > 0 {
+		n := len(names)
+		if n > maxDeleteParameters {
+			n = maxDeleteParameters
+		}
+		if _, err := s.DeleteParametersWithContext(ctx, &ssm.DeleteParametersInput{Names: names[:n]}); err != nil {
+			return awsclient.Wrap(err, errDeleteParameters)
+		}
+		names = names[n:]
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return awsclient.ErrorCode(err) == secretsmanager.ErrCodeResourceNotFoundException
+}
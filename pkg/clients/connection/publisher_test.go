@@ -0,0 +1,343 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const owner = "some-uid"
+
+type mockSecretsManager struct {
+	value   *string
+	owner   string
+	deleted bool
+	err     error
+	calls   []string
+}
+
+func (m *mockSecretsManager) DescribeSecretWithContext(_ aws.Context, _ *secretsmanager.DescribeSecretInput, _ ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.value == nil {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "", nil)
+	}
+	out := &secretsmanager.DescribeSecretOutput{}
+	if m.owner != "" {
+		out.Tags = []*secretsmanager.Tag{{Key: aws.String(TagKeyOwner), Value: aws.String(m.owner)}}
+	}
+	if m.deleted {
+		out.DeletedDate = &time.Time{}
+	}
+	return out, nil
+}
+
+func (m *mockSecretsManager) RestoreSecretWithContext(_ aws.Context, in *secretsmanager.RestoreSecretInput, _ ...request.Option) (*secretsmanager.RestoreSecretOutput, error) {
+	m.calls = append(m.calls, "restore "+aws.StringValue(in.SecretId))
+	return &secretsmanager.RestoreSecretOutput{}, nil
+}
+
+func (m *mockSecretsManager) GetSecretValueWithContext(_ aws.Context, _ *secretsmanager.GetSecretValueInput, _ ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	return &secretsmanager.GetSecretValueOutput{SecretString: m.value}, nil
+}
+
+func (m *mockSecretsManager) CreateSecretWithContext(_ aws.Context, in *secretsmanager.CreateSecretInput, _ ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	tags := make([]string, 0, len(in.Tags))
+	for _, t := range in.Tags {
+		tags = append(tags, aws.StringValue(t.Key)+"="+aws.StringValue(t.Value))
+	}
+	m.calls = append(m.calls, "create "+aws.StringValue(in.Name)+" "+aws.StringValue(in.SecretString)+" "+strings.Join(tags, ","))
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func (m *mockSecretsManager) PutSecretValueWithContext(_ aws.Context, in *secretsmanager.PutSecretValueInput, _ ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+	m.calls = append(m.calls, "put "+aws.StringValue(in.SecretId)+" "+aws.StringValue(in.SecretString))
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (m *mockSecretsManager) DeleteSecretWithContext(_ aws.Context, in *secretsmanager.DeleteSecretInput, _ ...request.Option) (*secretsmanager.DeleteSecretOutput, error) {
+	m.calls = append(m.calls, fmt.Sprintf("delete %s force=%t recovery=%d", aws.StringValue(in.SecretId), aws.BoolValue(in.ForceDeleteWithoutRecovery), aws.Int64Value(in.RecoveryWindowInDays)))
+	return &secretsmanager.DeleteSecretOutput{}, nil
+}
+
+type mockSSM struct {
+	params map[string]string
+	owned  []string
+	calls  []string
+}
+
+func (m *mockSSM) GetParametersByPathPagesWithContext(_ aws.Context, _ *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool, _ ...request.Option) error {
+	out := &ssm.GetParametersByPathOutput{}
+	for n, v := range m.params {
+		out.Parameters = append(out.Parameters, &ssm.Parameter{Name: aws.String(n), Value: aws.String(v)})
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockSSM) DescribeParametersPagesWithContext(_ aws.Context, _ *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool, _ ...request.Option) error {
+	out := &ssm.DescribeParametersOutput{}
+	for _, n := range m.owned {
+		out.Parameters = append(out.Parameters, &ssm.ParameterMetadata{Name: aws.String(n)})
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockSSM) PutParameterWithContext(_ aws.Context, in *ssm.PutParameterInput, _ ...request.Option) (*ssm.PutParameterOutput, error) {
+	call := "put " + aws.StringValue(in.Name) + " " + aws.StringValue(in.Value)
+	if aws.BoolValue(in.Overwrite) {
+		call += " overwrite"
+	}
+	for _, t := range in.Tags {
+		call += " " + aws.StringValue(t.Key) + "=" + aws.StringValue(t.Value)
+	}
+	m.calls = append(m.calls, call)
+	return &ssm.PutParameterOutput{}, nil
+}
+
+func (m *mockSSM) DeleteParametersWithContext(_ aws.Context, in *ssm.DeleteParametersInput, _ ...request.Option) (*ssm.DeleteParametersOutput, error) {
+	names := aws.StringValueSlice(in.Names)
+	sort.Strings(names)
+	for _, n := range names {
+		m.calls = append(m.calls, "delete "+n)
+	}
+	return &ssm.DeleteParametersOutput{}, nil
+}
+
+func newPublisher(sm *mockSecretsManager, s *mockSSM) *Publisher {
+	return &Publisher{
+		getConfig: func(_ context.Context, _ client.Client, _ resource.Managed, _ string) (*session.Session, error) {
+			return nil, nil
+		},
+		newSecretsManager: func(_ *session.Session) SecretsManagerClient { return sm },
+		newSSM:            func(_ *session.Session) SSMClient { return s },
+	}
+}
+
+func withAnnotations(a map[string]string) *fake.Managed {
+	mg := &fake.Managed{}
+	mg.SetUID(types.UID(owner))
+	meta.AddAnnotations(mg, a)
+	return mg
+}
+
+func TestPublishConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+	details := managed.ConnectionDetails{"password": []byte("secret")}
+
+	type want struct {
+		err      error
+		smCalls  []string
+		ssmCalls []string
+	}
+
+	cases := map[string]struct {
+		mg   resource.Managed
+		c    managed.ConnectionDetails
+		sm   *mockSecretsManager
+		ssm  *mockSSM
+		want want
+	}{
+		"NoAnnotations": {
+			mg:  withAnnotations(nil),
+			c:   details,
+			sm:  &mockSecretsManager{},
+			ssm: &mockSSM{},
+		},
+		"CreateSecret": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  details,
+			sm: &mockSecretsManager{},
+			want: want{
+				smCalls: []string{`create db {"password":"secret"} ` + TagKeyOwner + "=" + owner},
+			},
+		},
+		"MergeSecret": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  details,
+			sm: &mockSecretsManager{value: aws.String(`{"username":"admin"}`)},
+			want: want{
+				smCalls: []string{`put db {"password":"secret","username":"admin"}`},
+			},
+		},
+		"RestoreDeletedSecret": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  details,
+			sm: &mockSecretsManager{value: aws.String(`{"password":"old"}`), owner: owner, deleted: true},
+			want: want{
+				smCalls: []string{"restore db", `put db {"password":"secret"}`},
+			},
+		},
+		"SecretUpToDate": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  details,
+			sm: &mockSecretsManager{value: aws.String(`{"password":"secret"}`)},
+		},
+		"DescribeSecretError": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  details,
+			sm: &mockSecretsManager{err: errBoom},
+			want: want{
+				err: errors.Wrap(errBoom, errDescribeSecret),
+			},
+		},
+		"PutParameters": {
+			mg: withAnnotations(map[string]string{AnnotationKeySSMPath: "/db/"}),
+			c: managed.ConnectionDetails{
+				"password": []byte("secret"),
+				"port":     []byte("5432"),
+				"username": []byte("admin"),
+			},
+			ssm: &mockSSM{params: map[string]string{
+				"/db/port":     "3306",
+				"/db/username": "admin",
+			}},
+			want: want{
+				ssmCalls: []string{
+					"put /db/password secret " + TagKeyOwner + "=" + owner,
+					"put /db/port 5432 overwrite",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := newPublisher(tc.sm, tc.ssm).PublishConnection(context.Background(), tc.mg, tc.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("PublishConnection(...): -want error, +got error:\n%s", diff)
+			}
+			if tc.sm != nil {
+				if diff := cmp.Diff(tc.want.smCalls, tc.sm.calls); diff != "" {
+					t.Errorf("Secrets Manager calls: -want, +got:\n%s", diff)
+				}
+			}
+			if tc.ssm != nil {
+				sort.Strings(tc.ssm.calls)
+				if diff := cmp.Diff(tc.want.ssmCalls, tc.ssm.calls); diff != "" {
+					t.Errorf("SSM calls: -want, +got:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestUnpublishConnection(t *testing.T) {
+	type want struct {
+		err      error
+		smCalls  []string
+		ssmCalls []string
+	}
+
+	cases := map[string]struct {
+		mg   resource.Managed
+		c    managed.ConnectionDetails
+		sm   *mockSecretsManager
+		ssm  *mockSSM
+		want want
+	}{
+		"NoAnnotations": {
+			mg:  withAnnotations(nil),
+			sm:  &mockSecretsManager{},
+			ssm: &mockSSM{},
+		},
+		"DeleteOwnedSecret": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			sm: &mockSecretsManager{value: aws.String(`{"password":"secret"}`), owner: owner},
+			want: want{
+				smCalls: []string{"delete db force=false recovery=7"},
+			},
+		},
+		"SecretOwnedByOther": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			sm: &mockSecretsManager{value: aws.String(`{"password":"secret"}`), owner: "other-uid"},
+		},
+		"RemovePublishedKeys": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			c:  managed.ConnectionDetails{"password": []byte("secret")},
+			sm: &mockSecretsManager{value: aws.String(`{"password":"secret","username":"admin"}`)},
+			want: want{
+				smCalls: []string{`put db {"username":"admin"}`},
+			},
+		},
+		"SecretAlreadyDeleted": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			sm: &mockSecretsManager{value: aws.String(`{"password":"secret"}`), owner: owner, deleted: true},
+		},
+		"SecretNotFound": {
+			mg: withAnnotations(map[string]string{AnnotationKeySecretsManagerSecret: "db"}),
+			sm: &mockSecretsManager{},
+		},
+		"DeleteParameters": {
+			mg: withAnnotations(map[string]string{AnnotationKeySSMPath: "/db"}),
+			c:  managed.ConnectionDetails{"username": []byte("admin")},
+			ssm: &mockSSM{
+				params: map[string]string{
+					"/db/other":    "unrelated",
+					"/db/password": "secret",
+					"/db/username": "admin",
+				},
+				owned: []string{"/db/password"},
+			},
+			want: want{
+				ssmCalls: []string{"delete /db/password", "delete /db/username"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := newPublisher(tc.sm, tc.ssm).UnpublishConnection(context.Background(), tc.mg, tc.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("UnpublishConnection(...): -want error, +got error:\n%s", diff)
+			}
+			if tc.sm != nil {
+				if diff := cmp.Diff(tc.want.smCalls, tc.sm.calls); diff != "" {
+					t.Errorf("Secrets Manager calls: -want, +got:\n%s", diff)
+				}
+			}
+			if tc.ssm != nil {
+				if diff := cmp.Diff(tc.want.ssmCalls, tc.ssm.calls); diff != "" {
+					t.Errorf("SSM calls: -want, +got:\n%s", diff)
+				}
+			}
+		})
+	}
+}
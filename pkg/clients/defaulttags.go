@@ -0,0 +1,270 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+// tagFieldPaths are the JSON paths, relative to spec.forProvider, of the
+// fields that hold the tags of a managed resource, in order of preference.
+var tagFieldPaths = [][]string{
+	{"tags"},
+	{"tagList"},
+	{"tagSet"},
+	{"tagging", "tagSet"},
+}
+
+// tagFieldNames are the JSON names of the key and value fields of the tag
+// structs, such as key and value, or tagKey and tagValue for KMS keys.
+var tagFieldNames = [][2]string{
+	{"key", "value"},
+	{"tagKey", "tagValue"},
+}
+
+// GetDefaultTags returns the default tags of the ProviderConfig of the
+// supplied managed resource.
+func GetDefaultTags(ctx context.Context, c client.Client, mg resource.Managed) (map[string]string, error) {
+	if mg.GetProviderConfigReference() == nil {
+		return nil, nil
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, "cannot get referenced ProviderConfig")
+	}
+	return pc.Spec.DefaultTags, nil
+}
+
+// AddDefaultTags adds the supplied tags to the tag field of the supplied
+// managed resource unless it already has a tag with the same key. Both maps
+// and lists of key and value pairs are supported. It returns whether any tag
+// was added, which is never the case if the managed resource has no tag field.
+func AddDefaultTags(mg resource.Managed, tags map[string]string) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	v := reflect.ValueOf(mg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	spec := v.Elem().FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return false
+	}
+	fp := spec.FieldByName("ForProvider")
+	if !fp.IsValid() || fp.Kind() != reflect.Struct {
+		return false
+	}
+	for _, p := range tagFieldPaths {
+		if f, ok := fieldByPath(fp, p); ok {
+			return addTags(f, tags)
+		}
+	}
+	return false
+}
+
+// fieldByPath returns the field at the supplied JSON path of the supplied
+// struct, allocating the nil struct pointers on the way. Inline structs are
+// searched, too.
+func fieldByPath(v reflect.Value, path []string) (reflect.Value, bool) {
+	f, ok := fieldByJSONName(v, path[0])
+	if !ok || len(path) == 1 {
+		return f, ok
+	}
+	t := f.Type()
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	if _, ok := fieldByJSONName(reflect.New(t.Elem()).Elem(), path[1]); !ok {
+		return reflect.Value{}, false
+	}
+	if f.IsNil() {
+		f.Set(reflect.New(t.Elem()))
+	}
+	return fieldByPath(f.Elem(), path[1:])
+}
+
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		jn := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if sf.Anonymous && jn == "" && sf.Type.Kind() == reflect.Struct {
+			if f, ok := fieldByJSONName(v.Field(i), name); ok {
+				return f, true
+			}
+			continue
+		}
+		if jn == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// addTags adds the supplied tags to the supplied map or list of key and value
+// pairs.
+func addTags(f reflect.Value, tags map[string]string) bool { // nolint:gocyclo
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	added := false
+	switch f.Kind() { // nolint:exhaustive
+	case reflect.Map:
+		if f.Type().Key().Kind() != reflect.String {
+			return false
+		}
+		for _, k := range keys {
+			if !f.IsNil() && f.MapIndex(reflect.ValueOf(k)).IsValid() {
+				continue
+			}
+			val, ok := stringValue(f.Type().Elem(), tags[k])
+			if !ok {
+				return false
+			}
+			if f.IsNil() {
+				f.Set(reflect.MakeMap(f.Type()))
+			}
+			f.SetMapIndex(reflect.ValueOf(k), val)
+			added = true
+		}
+	case reflect.Slice:
+		existing := map[string]bool{}
+		for i := 0; i < f.Len(); i++ {
+			if k, ok := tagKey(f.Index(i)); ok {
+				existing[k] = true
+			}
+		}
+		for _, k := range keys {
+			if existing[k] {
+				continue
+			}
+			t, ok := newTag(f.Type().Elem(), k, tags[k])
+			if !ok {
+				return false
+			}
+			f.Set(reflect.Append(f, t))
+			added = true
+		}
+	}
+	return added
+}
+
+// tagFields returns the JSON names of the key and value fields of the
+// supplied tag struct type.
+func tagFields(t reflect.Type) ([2]string, bool) {
+	v := reflect.New(t).Elem()
+	for _, n := range tagFieldNames {
+		_, kok := fieldByJSONName(v, n[0])
+		_, vok := fieldByJSONName(v, n[1])
+		if kok && vok {
+			return n, true
+		}
+	}
+	return [2]string{}, false
+}
+
+// tagKey returns the key of the supplied tag, which is a struct or a pointer
+// to a struct with key and value fields.
+func tagKey(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	names, ok := tagFields(v.Type())
+	if !ok {
+		return "", false
+	}
+	f, ok := fieldByJSONName(v, names[0])
+	if !ok {
+		return "", false
+	}
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return "", false
+		}
+		f = f.Elem()
+	}
+	if f.Kind() != reflect.String {
+		return "", false
+	}
+	return f.String(), true
+}
+
+// newTag returns a tag of the supplied type, which is a struct or a pointer to
+// a struct with key and value fields.
+func newTag(t reflect.Type, key, value string) (reflect.Value, bool) {
+	st := t
+	if t.Kind() == reflect.Ptr {
+		st = t.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	names, ok := tagFields(st)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	tag := reflect.New(st)
+	for n, s := range map[string]string{names[0]: key, names[1]: value} {
+		f, ok := fieldByJSONName(tag.Elem(), n)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		val, ok := stringValue(f.Type(), s)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		f.Set(val)
+	}
+	if t.Kind() == reflect.Ptr {
+		return tag, true
+	}
+	return tag.Elem(), true
+}
+
+// stringValue returns the supplied string as a value of the supplied type,
+// which is a string or a pointer to a string.
+func stringValue(t reflect.Type, s string) (reflect.Value, bool) {
+	switch {
+	case t.Kind() == reflect.String:
+		return reflect.ValueOf(s).Convert(t), true
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.String:
+		v := reflect.New(t.Elem())
+		v.Elem().Set(reflect.ValueOf(s).Convert(t.Elem()))
+		return v, true
+	}
+	return reflect.Value{}, false
+}
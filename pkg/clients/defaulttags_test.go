@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	kmsv1alpha1 "github.com/crossplane/provider-aws/apis/kms/v1alpha1"
+)
+
+type tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ptrTag struct {
+	Key   *string `json:"key,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+type tagging struct {
+	TagSet []tag `json:"tagSet"`
+}
+
+// CustomParameters mimics the inline custom parameters of the generated
+// managed resources.
+type CustomParameters struct {
+	Tags map[string]*string `json:"tags,omitempty"`
+}
+
+type tagsManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			Tags map[string]string `json:"tags,omitempty"`
+		}
+	}
+}
+
+type tagListManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			TagList []tag `json:"tagList,omitempty"`
+		}
+	}
+}
+
+type tagSetManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			TagSet []*ptrTag `json:"tagSet,omitempty"`
+		}
+	}
+}
+
+type taggingManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			Tagging *tagging `json:"tagging,omitempty"`
+		}
+	}
+}
+
+type inlineManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			CustomParameters `json:",inline"`
+		}
+	}
+}
+
+type untaggableManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			Untaggable string `json:"untaggable,omitempty"`
+		}
+	}
+}
+
+func TestAddDefaultTags(t *testing.T) {
+	defaults := map[string]string{"owner": "platform", "cost-center": "42"}
+
+	tagsExisting := &tagsManaged{}
+	tagsExisting.Spec.ForProvider.Tags = map[string]string{"owner": "team-a"}
+	tagsWant := &tagsManaged{}
+	tagsWant.Spec.ForProvider.Tags = map[string]string{"owner": "team-a", "cost-center": "42"}
+
+	tagListWant := &tagListManaged{}
+	tagListWant.Spec.ForProvider.TagList = []tag{{Key: "cost-center", Value: "42"}, {Key: "owner", Value: "platform"}}
+
+	tagSetExisting := &tagSetManaged{}
+	tagSetExisting.Spec.ForProvider.TagSet = []*ptrTag{{Key: String("owner"), Value: String("team-a")}}
+	tagSetWant := &tagSetManaged{}
+	tagSetWant.Spec.ForProvider.TagSet = []*ptrTag{{Key: String("owner"), Value: String("team-a")}, {Key: String("cost-center"), Value: String("42")}}
+
+	taggingWant := &taggingManaged{}
+	taggingWant.Spec.ForProvider.Tagging = &tagging{TagSet: []tag{{Key: "cost-center", Value: "42"}, {Key: "owner", Value: "platform"}}}
+
+	inlineWant := &inlineManaged{}
+	inlineWant.Spec.ForProvider.Tags = map[string]*string{"owner": String("platform"), "cost-center": String("42")}
+
+	keyExisting := &kmsv1alpha1.Key{}
+	keyExisting.Spec.ForProvider.Tags = []*kmsv1alpha1.Tag{{TagKey: String("owner"), TagValue: String("team-a")}}
+	keyWant := &kmsv1alpha1.Key{}
+	keyWant.Spec.ForProvider.Tags = []*kmsv1alpha1.Tag{{TagKey: String("owner"), TagValue: String("team-a")}, {TagKey: String("cost-center"), TagValue: String("42")}}
+
+	allTagged := &tagsManaged{}
+	allTagged.Spec.ForProvider.Tags = map[string]string{"owner": "team-a", "cost-center": "1"}
+
+	type want struct {
+		added bool
+		mg    resource.Managed
+	}
+
+	cases := map[string]struct {
+		mg       resource.Managed
+		defaults map[string]string
+		want     want
+	}{
+		"NoDefaultTags": {
+			mg:   &tagsManaged{},
+			want: want{mg: &tagsManaged{}},
+		},
+		"Map": {
+			mg:       tagsExisting,
+			defaults: defaults,
+			want:     want{added: true, mg: tagsWant},
+		},
+		"List": {
+			mg:       &tagListManaged{},
+			defaults: defaults,
+			want:     want{added: true, mg: tagListWant},
+		},
+		"PointerList": {
+			mg:       tagSetExisting,
+			defaults: defaults,
+			want:     want{added: true, mg: tagSetWant},
+		},
+		"Nested": {
+			mg:       &taggingManaged{},
+			defaults: defaults,
+			want:     want{added: true, mg: taggingWant},
+		},
+		"Inline": {
+			mg:       &inlineManaged{},
+			defaults: defaults,
+			want:     want{added: true, mg: inlineWant},
+		},
+		"Key": {
+			mg:       keyExisting,
+			defaults: defaults,
+			want:     want{added: true, mg: keyWant},
+		},
+		"AlreadyTagged": {
+			mg:       allTagged,
+			defaults: defaults,
+			want:     want{mg: allTagged},
+		},
+		"Untaggable": {
+			mg:       &untaggableManaged{},
+			defaults: defaults,
+			want:     want{mg: &untaggableManaged{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			added := AddDefaultTags(tc.mg, tc.defaults)
+			if diff := cmp.Diff(tc.want.added, added); diff != "" {
+				t.Errorf("AddDefaultTags(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, cmpopts.IgnoreTypes(fake.Managed{})); diff != "" {
+				t.Errorf("AddDefaultTags(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
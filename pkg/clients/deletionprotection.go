@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// deletionProtectionFieldPaths are the JSON paths, relative to
+// spec.forProvider, of the native deletion protection flags of the managed
+// resources, e.g. the deletion protection of RDS instances and the API
+// termination protection of EC2 instances.
+var deletionProtectionFieldPaths = [][]string{
+	{"deletionProtection"},
+	{"disableAPITermination"},
+}
+
+// SetDeletionProtection sets the native deletion protection flag of the
+// supplied managed resource to the supplied value. It returns whether the
+// flag was changed, which is never the case if AWS does not support deletion
+// protection for the kind of the managed resource.
+func SetDeletionProtection(mg resource.Managed, enabled bool) bool {
+	fp, ok := forProvider(mg)
+	if !ok {
+		return false
+	}
+	for _, p := range deletionProtectionFieldPaths {
+		f, ok := fieldByPath(fp, p)
+		if !ok {
+			continue
+		}
+		switch {
+		case f.Kind() == reflect.Bool:
+			if f.Bool() == enabled {
+				return false
+			}
+			f.SetBool(enabled)
+			return true
+		case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.Bool:
+			if !f.IsNil() && f.Elem().Bool() == enabled {
+				return false
+			}
+			f.Set(reflect.ValueOf(&enabled))
+			return true
+		}
+		return false
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type deletionProtectionManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			DeletionProtection *bool `json:"deletionProtection,omitempty"`
+		}
+	}
+}
+
+type terminationProtectionManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			DisableAPITermination bool `json:"disableAPITermination,omitempty"`
+		}
+	}
+}
+
+func TestSetDeletionProtection(t *testing.T) {
+	protected := &deletionProtectionManaged{}
+	protected.Spec.ForProvider.DeletionProtection = Bool(true)
+
+	terminationProtected := &terminationProtectionManaged{}
+	terminationProtected.Spec.ForProvider.DisableAPITermination = true
+
+	type want struct {
+		changed bool
+		mg      resource.Managed
+	}
+
+	cases := map[string]struct {
+		mg      resource.Managed
+		enabled bool
+		want    want
+	}{
+		"Pointer": {
+			mg:      &deletionProtectionManaged{},
+			enabled: true,
+			want:    want{changed: true, mg: protected},
+		},
+		"Value": {
+			mg:      &terminationProtectionManaged{},
+			enabled: true,
+			want:    want{changed: true, mg: terminationProtected},
+		},
+		"Unchanged": {
+			mg:      &terminationProtectionManaged{},
+			enabled: false,
+			want:    want{mg: &terminationProtectionManaged{}},
+		},
+		"Unsupported": {
+			mg:      &untaggableManaged{},
+			enabled: true,
+			want:    want{mg: &untaggableManaged{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := SetDeletionProtection(tc.mg, tc.enabled)
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("SetDeletionProtection(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, cmpopts.IgnoreTypes(fake.Managed{})); diff != "" {
+				t.Errorf("SetDeletionProtection(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
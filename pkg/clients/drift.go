@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// IgnoreDiffPaths removes the fields at the supplied JSON paths, such as
+// "region" or "tags.owner", from the supplied JSON merge patch, such as the
+// one returned by DiffJSON. It returns an empty string if no field is left.
+func IgnoreDiffPaths(diff string, paths ...string) (string, error) {
+	if diff == "" {
+		return "", nil
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(diff), &m); err != nil {
+		return "", err
+	}
+	for _, p := range paths {
+		deletePath(m, strings.Split(p, "."))
+	}
+	if len(m) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+// DiffParameters returns the fields of the desired parameters that differ
+// from the observed ones as a JSON merge patch, or an empty string if they do
+// not differ or cannot be compared. The observed parameters are usually
+// late-initialized from the external resource into empty parameters, which
+// may not fill every field, so only the top-level fields set on both sides
+// are compared. Empty strings, zero numbers and empty lists are not
+// considered set, as required fields are serialized even if AWS does not
+// report them. The region, references and selectors are ignored.
+func DiffParameters(observed, desired interface{}) string {
+	diff, err := DiffJSON(observed, desired)
+	if err != nil || diff == "" {
+		return ""
+	}
+	obs := map[string]interface{}{}
+	m := map[string]interface{}{}
+	if err := unmarshalParameters(observed, &obs); err != nil {
+		return ""
+	}
+	if err := json.Unmarshal([]byte(diff), &m); err != nil {
+		return ""
+	}
+	for k, v := range m {
+		if isZero(obs[k]) || v == nil || isReference(k) || k == "region" {
+			delete(m, k)
+		}
+	}
+	if len(m) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// LateInitializeDiff late-initializes the supplied desired parameters from
+// an external resource with the supplied function, and returns the fields of
+// the desired parameters that differ from the external resource as returned
+// by DiffParameters. The observed parameters are late-initialized with the
+// same function into empty parameters of the same type.
+func LateInitializeDiff(desired interface{}, lateInitialize func(params interface{})) string {
+	lateInitialize(desired)
+	observed := reflect.New(reflect.TypeOf(desired).Elem()).Interface()
+	lateInitialize(observed)
+	return DiffParameters(observed, desired)
+}
+
+func unmarshalParameters(p interface{}, m *map[string]interface{}) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, m)
+}
+
+func isZero(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case float64:
+		return x == 0
+	case []interface{}:
+		return len(x) == 0
+	case map[string]interface{}:
+		return len(x) == 0
+	}
+	return false
+}
+
+func isReference(field string) bool {
+	return strings.HasSuffix(field, "Ref") || strings.HasSuffix(field, "Refs") || strings.HasSuffix(field, "Selector")
+}
+
+func deletePath(m map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	child, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deletePath(child, path[1:])
+	if len(child) == 0 {
+		delete(m, path[0])
+	}
+}
+
+// DiffPaths returns the sorted JSON paths of the fields that differ according
+// to the supplied JSON merge patch, such as the one returned by DiffJSON. The
+// fields of nested objects are joined with ".", for example "tags.owner". It
+// returns nil if the supplied diff is not a JSON merge patch.
+func DiffPaths(diff string) []string {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(diff), &m); err != nil {
+		return nil
+	}
+	var paths []string
+	appendPaths(&paths, "", m)
+	sort.Strings(paths)
+	return paths
+}
+
+func appendPaths(paths *[]string, prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		p := prefix + k
+		if child, ok := v.(map[string]interface{}); ok && len(child) != 0 {
+			appendPaths(paths, p+".", child)
+			continue
+		}
+		*paths = append(*paths, p)
+	}
+}
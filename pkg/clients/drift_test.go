@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type driftParams struct {
+	Region  string            `json:"region,omitempty"`
+	Name    *string           `json:"name,omitempty"`
+	Size    *int64            `json:"size,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	RoleRef *string           `json:"roleRef,omitempty"`
+}
+
+func TestIgnoreDiffPaths(t *testing.T) {
+	cases := map[string]struct {
+		diff   string
+		ignore []string
+		want   string
+	}{
+		"NoDiff": {
+			ignore: []string{"region"},
+		},
+		"Ignored": {
+			diff:   `{"region":"us-east-1","tags":{"owner":"b"}}`,
+			ignore: []string{"region", "tags.owner"},
+		},
+		"NotIgnored": {
+			diff:   `{"name":"b","tags":{"owner":"b","team":"a"}}`,
+			ignore: []string{"region", "tags.owner"},
+			want:   `{"name":"b","tags":{"team":"a"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := IgnoreDiffPaths(tc.diff, tc.ignore...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IgnoreDiffPaths(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffParameters(t *testing.T) {
+	cases := map[string]struct {
+		observed driftParams
+		desired  driftParams
+		want     string
+	}{
+		"Identical": {
+			observed: driftParams{Name: String("a"), Tags: map[string]string{"k": "v"}},
+			desired:  driftParams{Region: "us-east-1", Name: String("a"), Tags: map[string]string{"k": "v"}, RoleRef: String("r")},
+		},
+		"Different": {
+			observed: driftParams{Name: String("a"), Tags: map[string]string{"k": "v", "old": "v"}},
+			desired:  driftParams{Name: String("b"), Tags: map[string]string{"k": "v"}},
+			want:     `{"name":"b","tags":{"old":null}}`,
+		},
+		"NotObserved": {
+			observed: driftParams{Name: String("a")},
+			desired:  driftParams{Name: String("a"), Size: Int64(1)},
+		},
+		"RequiredNotObserved": {
+			observed: driftParams{Region: "", Name: String("")},
+			desired:  driftParams{Name: String("a")},
+		},
+		"NotDesired": {
+			observed: driftParams{Name: String("a"), Size: Int64(1)},
+			desired:  driftParams{Name: String("a")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, DiffParameters(tc.observed, tc.desired)); diff != "" {
+				t.Errorf("DiffParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeDiff(t *testing.T) {
+	observed := driftParams{Name: String("a"), Size: Int64(2)}
+	lateInitialize := func(p interface{}) {
+		params := p.(*driftParams)
+		params.Name = LateInitializeStringPtr(params.Name, observed.Name)
+		params.Size = LateInitializeInt64Ptr(params.Size, observed.Size)
+	}
+	desired := &driftParams{Size: Int64(1)}
+
+	got := LateInitializeDiff(desired, lateInitialize)
+	if diff := cmp.Diff(&driftParams{Name: String("a"), Size: Int64(1)}, desired); diff != "" {
+		t.Errorf("desired: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(`{"size":1}`, got); diff != "" {
+		t.Errorf("LateInitializeDiff(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDiffPaths(t *testing.T) {
+	cases := map[string]struct {
+		diff string
+		want []string
+	}{
+		"Empty": {
+			diff: "",
+		},
+		"NotJSON": {
+			diff: "-name: a\n+name: b",
+		},
+		"Nested": {
+			diff: `{"tags":{"old":null,"team":"a"},"name":"b","vpcConfig":{}}`,
+			want: []string{"name", "tags.old", "tags.team", "vpcConfig"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, DiffPaths(tc.diff)); diff != "" {
+				t.Errorf("DiffPaths(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -15,7 +15,7 @@ import (
 )
 
 const (
-	errNotSpecified = "failed to format Repository Policy, no rawPolicy or policy specified"
+	errNotSpecified = "failed to format Repository Policy, no rawPolicy, policy or policyDocument specified"
 )
 
 // RepositoryPolicyClient is the external client used for Repository Policy Resource
@@ -205,6 +205,9 @@ func RawPolicyData(original *v1alpha1.RepositoryPolicy) (string, error) {
 		return "", errors.New(errNotSpecified)
 	}
 	switch {
+	case original.Spec.ForProvider.PolicyDocument != nil:
+		policy, err := awsclient.PolicyDocumentString(original.Spec.ForProvider.PolicyDocument, nil)
+		return awsclient.StringValue(policy), err
 	case original.Spec.ForProvider.RawPolicy != nil:
 		return *original.Spec.ForProvider.RawPolicy, nil
 	case original.Spec.ForProvider.Policy != nil:
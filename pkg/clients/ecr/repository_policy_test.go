@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/crossplane/provider-aws/apis/ecr/v1alpha1"
+	"github.com/crossplane/provider-aws/apis/v1beta1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
 )
 
@@ -236,6 +237,24 @@ func TestFormat(t *testing.T) {
 				str: policy,
 			},
 		},
+		"PolicyDocument": {
+			args: formatarg{
+				cr: repositoryPolicy(withPolicy(&v1alpha1.RepositoryPolicyParameters{
+					RawPolicy: aws.String(`{"Statement":[]}`),
+					PolicyDocument: &v1beta1.PolicyDocument{
+						Version: "2012-10-17",
+						Statements: []v1beta1.PolicyStatement{{
+							Effect:    "Allow",
+							Principal: &v1beta1.PolicyPrincipal{AllowAnon: &boolCheck},
+							Action:    []string{"ecr:ListImages"},
+						}},
+					},
+				})),
+			},
+			want: want{
+				str: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"ecr:ListImages"}]}`,
+			},
+		},
 		"NoPolicy": {
 			args: formatarg{
 				cr: repositoryPolicy(withPolicy(&v1alpha1.RepositoryPolicyParameters{})),
@@ -333,6 +333,23 @@ func IsUpToDate(p *v1beta1.ClusterParameters, cluster *ekstypes.Cluster) (bool,
 	return res, nil
 }
 
+// Diff returns the fields of the supplied ClusterParameters that differ from
+// the observed cluster as a JSON merge patch. The fields that IsUpToDate does
+// not compare are omitted.
+func Diff(p *v1beta1.ClusterParameters, cluster *ekstypes.Cluster) (string, error) {
+	currentParams := &v1beta1.ClusterParameters{}
+	LateInitialize(currentParams, cluster)
+	diff, err := awsclients.DiffJSON(currentParams, p)
+	if err != nil {
+		return "", err
+	}
+	return awsclients.IgnoreDiffPaths(diff,
+		"region", "roleArnRef", "roleArnSelector",
+		"resourcesVpcConfig.publicAccessCidrs",
+		"resourcesVpcConfig.securityGroupIds", "resourcesVpcConfig.securityGroupIdRefs", "resourcesVpcConfig.securityGroupIdSelector",
+		"resourcesVpcConfig.subnetIds", "resourcesVpcConfig.subnetIdRefs", "resourcesVpcConfig.subnetIdSelector")
+}
+
 // GetConnectionDetails extracts managed.ConnectionDetails out of ekstypes.Cluster.
 func GetConnectionDetails(ctx context.Context, cluster *ekstypes.Cluster, stsClient STSClient) managed.ConnectionDetails {
 	if cluster == nil || cluster.Name == nil || cluster.Endpoint == nil || cluster.CertificateAuthority == nil || cluster.CertificateAuthority.Data == nil {
@@ -821,3 +821,67 @@ func TestIsUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestDiff(t *testing.T) {
+	otherVersion := "1.15"
+	region := "us-east-1"
+
+	type args struct {
+		cluster *ekstypes.Cluster
+		p       *v1beta1.ClusterParameters
+	}
+
+	cases := map[string]struct {
+		args args
+		want string
+	}{
+		"SameFields": {
+			args: args{
+				p: &v1beta1.ClusterParameters{
+					Region: &region,
+					ResourcesVpcConfig: v1beta1.VpcConfigRequest{
+						EndpointPrivateAccess: &trueVal,
+						EndpointPublicAccess:  &falseVal,
+						SubnetIDs:             []string{"cool-subnet"},
+					},
+					RoleArn: roleArn,
+					Version: &version,
+				},
+				cluster: &ekstypes.Cluster{
+					ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+						EndpointPrivateAccess: trueVal,
+						SubnetIds:             []string{"other-subnet"},
+					},
+					RoleArn: &roleArn,
+					Version: &version,
+				},
+			},
+		},
+		"DifferentFields": {
+			args: args{
+				p: &v1beta1.ClusterParameters{
+					RoleArn: roleArn,
+					Tags:    map[string]string{"key": "val"},
+					Version: &otherVersion,
+				},
+				cluster: &ekstypes.Cluster{
+					RoleArn: &roleArn,
+					Version: &version,
+				},
+			},
+			want: `{"tags":{"key":"val"},"version":"1.15"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Diff(tc.args.p, tc.args.cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
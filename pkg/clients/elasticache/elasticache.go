@@ -50,6 +50,9 @@ type Client interface {
 	ModifyReplicationGroup(context.Context, *elasticache.ModifyReplicationGroupInput, ...func(*elasticache.Options)) (*elasticache.ModifyReplicationGroupOutput, error)
 	DeleteReplicationGroup(context.Context, *elasticache.DeleteReplicationGroupInput, ...func(*elasticache.Options)) (*elasticache.DeleteReplicationGroupOutput, error)
 
+	DescribeSnapshots(context.Context, *elasticache.DescribeSnapshotsInput, ...func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error)
+	CreateSnapshot(context.Context, *elasticache.CreateSnapshotInput, ...func(*elasticache.Options)) (*elasticache.CreateSnapshotOutput, error)
+
 	DescribeCacheSubnetGroups(context.Context, *elasticache.DescribeCacheSubnetGroupsInput, ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error)
 	CreateCacheSubnetGroup(context.Context, *elasticache.CreateCacheSubnetGroupInput, ...func(*elasticache.Options)) (*elasticache.CreateCacheSubnetGroupOutput, error)
 	ModifyCacheSubnetGroup(context.Context, *elasticache.ModifyCacheSubnetGroupInput, ...func(*elasticache.Options)) (*elasticache.ModifyCacheSubnetGroupOutput, error)
@@ -408,6 +411,16 @@ func ConnectionEndpoint(rg elasticachetypes.ReplicationGroup) managed.Connection
 	return nil
 }
 
+// SnapshotStatusAvailable is the status of a completed snapshot.
+const SnapshotStatusAvailable = "available"
+
+// IsSnapshotNotFound returns true if the supplied error indicates a Snapshot
+// was not found.
+func IsSnapshotNotFound(err error) bool {
+	var snf *elasticachetypes.SnapshotNotFoundFault
+	return errors.As(err, &snf)
+}
+
 // IsNotFound returns true if the supplied error indicates a Replication Group
 // was not found.
 func IsNotFound(err error) bool {
@@ -31,6 +31,9 @@ type MockClient struct {
 	MockModifyReplicationGroup    func(context.Context, *elasticache.ModifyReplicationGroupInput, []func(*elasticache.Options)) (*elasticache.ModifyReplicationGroupOutput, error)
 	MockDeleteReplicationGroup    func(context.Context, *elasticache.DeleteReplicationGroupInput, []func(*elasticache.Options)) (*elasticache.DeleteReplicationGroupOutput, error)
 
+	MockDescribeSnapshots func(context.Context, *elasticache.DescribeSnapshotsInput, []func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error)
+	MockCreateSnapshot    func(context.Context, *elasticache.CreateSnapshotInput, []func(*elasticache.Options)) (*elasticache.CreateSnapshotOutput, error)
+
 	MockDescribeCacheSubnetGroups func(context.Context, *elasticache.DescribeCacheSubnetGroupsInput, []func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error)
 	MockCreateCacheSubnetGroup    func(context.Context, *elasticache.CreateCacheSubnetGroupInput, []func(*elasticache.Options)) (*elasticache.CreateCacheSubnetGroupOutput, error)
 	MockModifyCacheSubnetGroup    func(context.Context, *elasticache.ModifyCacheSubnetGroupInput, []func(*elasticache.Options)) (*elasticache.ModifyCacheSubnetGroupOutput, error)
@@ -66,6 +69,18 @@ func (c *MockClient) DeleteReplicationGroup(ctx context.Context, i *elasticache.
 	return c.MockDeleteReplicationGroup(ctx, i, opts)
 }
 
+// DescribeSnapshots calls the underlying
+// MockDescribeSnapshots method.
+func (c *MockClient) DescribeSnapshots(ctx context.Context, i *elasticache.DescribeSnapshotsInput, opts ...func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error) {
+	return c.MockDescribeSnapshots(ctx, i, opts)
+}
+
+// CreateSnapshot calls the underlying
+// MockCreateSnapshot method.
+func (c *MockClient) CreateSnapshot(ctx context.Context, i *elasticache.CreateSnapshotInput, opts ...func(*elasticache.Options)) (*elasticache.CreateSnapshotOutput, error) {
+	return c.MockCreateSnapshot(ctx, i, opts)
+}
+
 // DescribeCacheClusters calls the underlying
 // MockDescribeCacheClusters method.
 func (c *MockClient) DescribeCacheClusters(ctx context.Context, i *elasticache.DescribeCacheClustersInput, opts ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+)
+
+// An ErrorClass groups the errors returned by AWS APIs by how they should be
+// retried.
+type ErrorClass string
+
+// Classes of errors.
+const (
+	// ErrorClassUnknown is the class of errors that are not returned by an
+	// AWS API or whose code is not classified.
+	ErrorClassUnknown ErrorClass = "Unknown"
+
+	// ErrorClassThrottling is the class of errors returned because a call
+	// was throttled. They are retried with a backoff.
+	ErrorClassThrottling ErrorClass = "Throttling"
+
+	// ErrorClassTransient is the class of errors returned because of a
+	// temporary failure of AWS or of the network. They are retried with a
+	// backoff.
+	ErrorClassTransient ErrorClass = "Transient"
+
+	// ErrorClassAuthorization is the class of errors returned because the
+	// credentials are invalid or not allowed to make a call. Retrying does
+	// not fix them until the credentials or their permissions change.
+	ErrorClassAuthorization ErrorClass = "Authorization"
+
+	// ErrorClassValidation is the class of errors returned because the
+	// parameters of a call are invalid. Retrying does not fix them until the
+	// spec of the managed resource changes.
+	ErrorClassValidation ErrorClass = "Validation"
+
+	// ErrorClassQuotaExceeded is the class of errors returned because a
+	// service quota of the account was reached. Retrying does not fix them
+	// until other resources are deleted or the quota is raised.
+	ErrorClassQuotaExceeded ErrorClass = "QuotaExceeded"
+
+	// ErrorClassDependencyViolation is the class of errors returned because
+	// the resource is still used by, or still depends on, another resource.
+	// They are retried with a backoff, as the other resource is usually
+	// being deleted or created.
+	ErrorClassDependencyViolation ErrorClass = "DependencyViolation"
+)
+
+// IsTerminal returns whether errors of the class are not fixed by retrying
+// until the spec of the managed resource or the credentials change.
+func (c ErrorClass) IsTerminal() bool {
+	return c == ErrorClassAuthorization || c == ErrorClassValidation
+}
+
+// errorClasses are the classes of the error codes AWS APIs return, other
+// than the throttling ones, which are matched first.
+var errorClasses = map[string]ErrorClass{
+	"RequestTimeout":              ErrorClassTransient,
+	"RequestTimeoutException":     ErrorClassTransient,
+	"InternalError":               ErrorClassTransient,
+	"InternalFailure":             ErrorClassTransient,
+	"InternalServerError":         ErrorClassTransient,
+	"InternalServiceError":        ErrorClassTransient,
+	"InternalServiceException":    ErrorClassTransient,
+	"InternalServerException":     ErrorClassTransient,
+	"ServiceUnavailable":          ErrorClassTransient,
+	"ServiceUnavailableException": ErrorClassTransient,
+	"Unavailable":                 ErrorClassTransient,
+	"EC2InternalError":            ErrorClassTransient,
+	"IDPCommunicationError":       ErrorClassTransient,
+	// NOTE: expired credentials are refreshed when the call is retried.
+	"ExpiredToken":          ErrorClassTransient,
+	"ExpiredTokenException": ErrorClassTransient,
+	"RequestExpired":        ErrorClassTransient,
+
+	"AccessDenied":                ErrorClassAuthorization,
+	"AccessDeniedException":       ErrorClassAuthorization,
+	"UnauthorizedOperation":       ErrorClassAuthorization,
+	"UnauthorizedAccess":          ErrorClassAuthorization,
+	"AuthFailure":                 ErrorClassAuthorization,
+	"AuthorizationError":          ErrorClassAuthorization,
+	"NotAuthorized":               ErrorClassAuthorization,
+	"InvalidClientTokenId":        ErrorClassAuthorization,
+	"InvalidAccessKeyId":          ErrorClassAuthorization,
+	"SignatureDoesNotMatch":       ErrorClassAuthorization,
+	"IncompleteSignature":         ErrorClassAuthorization,
+	"MissingAuthenticationToken":  ErrorClassAuthorization,
+	"UnrecognizedClientException": ErrorClassAuthorization,
+	"OptInRequired":               ErrorClassAuthorization,
+
+	"ValidationError":                      ErrorClassValidation,
+	"ValidationException":                  ErrorClassValidation,
+	"InvalidParameter":                     ErrorClassValidation,
+	"InvalidParameterException":            ErrorClassValidation,
+	"InvalidParameterValue":                ErrorClassValidation,
+	"InvalidParameterValueException":       ErrorClassValidation,
+	"InvalidParameterCombination":          ErrorClassValidation,
+	"InvalidParameterCombinationException": ErrorClassValidation,
+	"MissingParameter":                     ErrorClassValidation,
+	"MissingRequiredParameter":             ErrorClassValidation,
+	"InvalidInput":                         ErrorClassValidation,
+	"InvalidInputException":                ErrorClassValidation,
+	"InvalidArgument":                      ErrorClassValidation,
+	"MalformedPolicyDocument":              ErrorClassValidation,
+	"MalformedPolicyDocumentException":     ErrorClassValidation,
+	"MalformedPolicy":                      ErrorClassValidation,
+	"SerializationException":               ErrorClassValidation,
+
+	"DependencyViolation":    ErrorClassDependencyViolation,
+	"DeleteConflict":         ErrorClassDependencyViolation,
+	"ResourceInUse":          ErrorClassDependencyViolation,
+	"ResourceInUseException": ErrorClassDependencyViolation,
+}
+
+// ClassifyError returns the class of the supplied error, which may be
+// returned by either AWS SDK v1 or v2 clients and wrapped. Errors whose code
+// is not known are classified by its suffix, e.g. VpcLimitExceeded or
+// InvalidVpcID.Malformed, and then by their HTTP status code.
+func ClassifyError(err error) ErrorClass { // nolint:gocyclo
+	if err == nil {
+		return ErrorClassUnknown
+	}
+	code := ErrorCode(err)
+	if _, ok := throttlingErrorCodes[code]; ok {
+		return ErrorClassThrottling
+	}
+	if c, ok := errorClasses[code]; ok {
+		return c
+	}
+	switch {
+	case code == "":
+	case strings.Contains(code, "LimitExceeded"), strings.Contains(code, "QuotaExceeded"):
+		return ErrorClassQuotaExceeded
+	case strings.HasSuffix(code, ".Malformed"), strings.HasPrefix(code, "Malformed"):
+		return ErrorClassValidation
+	case strings.HasSuffix(code, ".InUse"), strings.HasSuffix(code, "InUseFault"):
+		return ErrorClassDependencyViolation
+	}
+
+	switch s := httpStatusCode(err); {
+	case s == http.StatusTooManyRequests:
+		return ErrorClassThrottling
+	case s == http.StatusUnauthorized, s == http.StatusForbidden:
+		return ErrorClassAuthorization
+	case s >= http.StatusInternalServerError:
+		return ErrorClassTransient
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTransient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTransient
+	}
+	return ErrorClassUnknown
+}
+
+// httpStatusCode returns the HTTP status code of the response the supplied
+// error was returned with, or zero if it was not returned with a response.
+func httpStatusCode(err error) int {
+	var v2 interface{ HTTPStatusCode() int }
+	if errors.As(err, &v2) {
+		return v2.HTTPStatusCode()
+	}
+	var v1 awserr.RequestFailure
+	if errors.As(err, &v1) {
+		return v1.StatusCode()
+	}
+	return 0
+}
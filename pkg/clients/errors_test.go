@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want ErrorClass
+	}{
+		"Nil": {
+			want: ErrorClassUnknown,
+		},
+		"NotAWS": {
+			err:  errors.New("boom"),
+			want: ErrorClassUnknown,
+		},
+		"UnknownCode": {
+			err:  awserr.New("NoSuchEntity", "not found", nil),
+			want: ErrorClassUnknown,
+		},
+		"ThrottlingV1": {
+			err:  awserr.New("Throttling", "Rate exceeded", nil),
+			want: ErrorClassThrottling,
+		},
+		"ThrottlingV2": {
+			err:  &smithy.GenericAPIError{Code: "RequestLimitExceeded"},
+			want: ErrorClassThrottling,
+		},
+		"Transient": {
+			err:  &smithy.GenericAPIError{Code: "ServiceUnavailable"},
+			want: ErrorClassTransient,
+		},
+		"AccessDenied": {
+			err:  errors.Wrap(&smithy.GenericAPIError{Code: "AccessDenied"}, "cannot create"),
+			want: ErrorClassAuthorization,
+		},
+		"InvalidParameterValue": {
+			err:  Wrap(awserr.New("InvalidParameterValue", "invalid", nil), "cannot create"),
+			want: ErrorClassValidation,
+		},
+		"MalformedSuffix": {
+			err:  &smithy.GenericAPIError{Code: "InvalidVpcID.Malformed"},
+			want: ErrorClassValidation,
+		},
+		"QuotaExceededSuffix": {
+			err:  &smithy.GenericAPIError{Code: "VpcLimitExceeded"},
+			want: ErrorClassQuotaExceeded,
+		},
+		"QuotaExceededFault": {
+			err:  &smithy.GenericAPIError{Code: "DBInstanceQuotaExceededFault"},
+			want: ErrorClassQuotaExceeded,
+		},
+		"DependencyViolation": {
+			err:  awserr.New("DependencyViolation", "has dependencies", nil),
+			want: ErrorClassDependencyViolation,
+		},
+		"InUseSuffix": {
+			err:  &smithy.GenericAPIError{Code: "InvalidGroup.InUse"},
+			want: ErrorClassDependencyViolation,
+		},
+		"ServerErrorStatusV1": {
+			err:  awserr.NewRequestFailure(awserr.New("SerializationError", "bad response", nil), http.StatusBadGateway, "id"),
+			want: ErrorClassTransient,
+		},
+		"ForbiddenStatusV2": {
+			err: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+				Err:      errors.New("forbidden"),
+			},
+			want: ErrorClassAuthorization,
+		},
+		"DeadlineExceeded": {
+			err:  errors.Wrap(context.DeadlineExceeded, "cannot describe"),
+			want: ErrorClassTransient,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ClassifyError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ClassifyError(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorClassIsTerminal(t *testing.T) {
+	cases := map[ErrorClass]bool{
+		ErrorClassUnknown:             false,
+		ErrorClassThrottling:          false,
+		ErrorClassTransient:           false,
+		ErrorClassAuthorization:       true,
+		ErrorClassValidation:          true,
+		ErrorClassQuotaExceeded:       false,
+		ErrorClassDependencyViolation: false,
+	}
+
+	for c, want := range cases {
+		t.Run(string(c), func(t *testing.T) {
+			if diff := cmp.Diff(want, c.IsTerminal()); diff != "" {
+				t.Errorf("IsTerminal(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -29,6 +29,13 @@ func NewPolicyClient(cfg aws.Config) PolicyClient {
 	return iam.NewFromConfig(cfg)
 }
 
+// IAMPolicyDocument returns the JSON policy document of the supplied policy,
+// which may be given in either form.
+func IAMPolicyDocument(in v1alpha1.IAMPolicyParameters) (string, error) {
+	doc, err := awsclients.PolicyDocumentString(in.PolicyDocument, &in.Document)
+	return aws.ToString(doc), err
+}
+
 // IsPolicyUpToDate checks whether there is a change in any of the modifiable fields in policy.
 func IsPolicyUpToDate(in v1alpha1.IAMPolicyParameters, policy iamtypes.PolicyVersion) (bool, error) {
 	// The AWS API reutrns Policy Document as an escaped string.
@@ -36,7 +43,11 @@ func IsPolicyUpToDate(in v1alpha1.IAMPolicyParameters, policy iamtypes.PolicyVer
 	// the spec.Document and policy.Document can sometimes be false negative (due to spaces, line feeds).
 	// Escaping with a common method and then comparing is a safe way.
 
-	if *policy.Document == "" || in.Document == "" {
+	doc, err := IAMPolicyDocument(in)
+	if err != nil {
+		return false, err
+	}
+	if aws.ToString(policy.Document) == "" || doc == "" {
 		return false, nil
 	}
 
@@ -44,12 +55,15 @@ func IsPolicyUpToDate(in v1alpha1.IAMPolicyParameters, policy iamtypes.PolicyVer
 	if err != nil {
 		return false, nil
 	}
+	if awsclients.IsPolicyUpToDate(&doc, &unescapedPolicy) {
+		return true, nil
+	}
 
 	compactIAMPolicy, err := awsclients.CompactAndEscapeJSON(unescapedPolicy)
 	if err != nil {
 		return false, err
 	}
-	compactSpecPolicy, err := awsclients.CompactAndEscapeJSON(in.Document)
+	compactSpecPolicy, err := awsclients.CompactAndEscapeJSON(doc)
 	if err != nil {
 		return false, err
 	}
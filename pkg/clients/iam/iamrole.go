@@ -43,11 +43,22 @@ func NewRoleClient(conf aws.Config) RoleClient {
 	return iam.NewFromConfig(conf)
 }
 
+// AssumeRolePolicyDocument returns the JSON trust relationship policy
+// document of the supplied role, which may be given in either form.
+func AssumeRolePolicyDocument(p v1beta1.IAMRoleParameters) (string, error) {
+	doc, err := awsclients.PolicyDocumentString(p.AssumeRolePolicy, &p.AssumeRolePolicyDocument)
+	return aws.ToString(doc), err
+}
+
 // GenerateCreateRoleInput from IAMRoleSpec
-func GenerateCreateRoleInput(name string, p *v1beta1.IAMRoleParameters) *iam.CreateRoleInput {
+func GenerateCreateRoleInput(name string, p *v1beta1.IAMRoleParameters) (*iam.CreateRoleInput, error) {
+	doc, err := AssumeRolePolicyDocument(*p)
+	if err != nil {
+		return nil, err
+	}
 	m := &iam.CreateRoleInput{
 		RoleName:                 aws.String(name),
-		AssumeRolePolicyDocument: aws.String(p.AssumeRolePolicyDocument),
+		AssumeRolePolicyDocument: aws.String(doc),
 		Description:              p.Description,
 		MaxSessionDuration:       p.MaxSessionDuration,
 		Path:                     p.Path,
@@ -64,7 +75,7 @@ func GenerateCreateRoleInput(name string, p *v1beta1.IAMRoleParameters) *iam.Cre
 		}
 	}
 
-	return m
+	return m, nil
 }
 
 // GenerateRoleObservation is used to produce IAMRoleExternalStatus from iamtypes.Role
@@ -78,8 +89,12 @@ func GenerateRoleObservation(role iamtypes.Role) v1beta1.IAMRoleExternalStatus {
 // GenerateIAMRole assigns the in IAMRoleParamters to role.
 func GenerateIAMRole(in v1beta1.IAMRoleParameters, role *iamtypes.Role) error {
 
-	if in.AssumeRolePolicyDocument != "" {
-		s, err := awsclients.CompactAndEscapeJSON(in.AssumeRolePolicyDocument)
+	doc, err := AssumeRolePolicyDocument(in)
+	if err != nil {
+		return err
+	}
+	if doc != "" {
+		s, err := awsclients.CompactAndEscapeJSON(doc)
 		if err != nil {
 			return errors.Wrap(err, errPolicyJSONEscape)
 		}
@@ -108,7 +123,9 @@ func LateInitializeRole(in *v1beta1.IAMRoleParameters, role *iamtypes.Role) {
 	if role == nil {
 		return
 	}
-	in.AssumeRolePolicyDocument = awsclients.LateInitializeString(in.AssumeRolePolicyDocument, role.AssumeRolePolicyDocument)
+	if in.AssumeRolePolicy == nil {
+		in.AssumeRolePolicyDocument = awsclients.LateInitializeString(in.AssumeRolePolicyDocument, role.AssumeRolePolicyDocument)
+	}
 	in.Description = awsclients.LateInitializeStringPtr(in.Description, role.Description)
 	in.MaxSessionDuration = awsclients.LateInitializeInt32Ptr(in.MaxSessionDuration, role.MaxSessionDuration)
 	in.Path = awsclients.LateInitializeStringPtr(in.Path, role.Path)
@@ -142,7 +159,10 @@ func CreatePatch(in *iamtypes.Role, target *v1beta1.IAMRoleParameters) (*v1beta1
 	return patch, nil
 }
 
-func isAssumeRolePolicyUpToDate(a, b *string) (bool, error) {
+// IsAssumeRolePolicyUpToDate returns true if the supplied trust relationship
+// policy documents are semantically identical. Either may be URL-encoded, as
+// returned by AWS.
+func IsAssumeRolePolicyUpToDate(a, b *string) (bool, error) {
 	if a == nil || b == nil {
 		return a == b, nil
 	}
@@ -175,7 +195,7 @@ func IsRoleUpToDate(in v1beta1.IAMRoleParameters, observed iamtypes.Role) (bool,
 		return false, "", err
 	}
 
-	policyUpToDate, err := isAssumeRolePolicyUpToDate(desired.AssumeRolePolicyDocument, observed.AssumeRolePolicyDocument)
+	policyUpToDate, err := IsAssumeRolePolicyUpToDate(desired.AssumeRolePolicyDocument, observed.AssumeRolePolicyDocument)
 	if err != nil {
 		return false, "", err
 	}
@@ -125,7 +125,10 @@ func TestGenerateCreateRoleInput(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := GenerateCreateRoleInput(roleName, &tc.in)
+			r, err := GenerateCreateRoleInput(roleName, &tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if diff := cmp.Diff(r, &tc.out, cmpopts.IgnoreTypes(document.NoSerde{})); diff != "" {
 				t.Errorf("GenerateNetworkObservation(...): -want, +got:\n%s", diff)
 			}
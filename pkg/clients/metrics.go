@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsHandlerName = "crossplane.Metrics"
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_aws_api_requests_total",
+		Help: "Number of requests made to AWS APIs, including retries.",
+	}, []string{"service", "operation", "kind"})
+
+	apiRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_aws_api_request_errors_total",
+		Help: "Number of requests made to AWS APIs that failed, by error code.",
+	}, []string{"service", "operation", "kind", "code"})
+
+	apiRequestThrottles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_aws_api_request_throttles_total",
+		Help: "Number of requests made to AWS APIs that were throttled.",
+	}, []string{"service", "operation", "kind"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crossplane_aws_api_request_duration_seconds",
+		Help:    "Duration of the requests made to AWS APIs.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "operation", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestErrors, apiRequestThrottles, apiRequestDuration)
+}
+
+// KindOf returns the kind of the supplied managed resource, which is used to
+// attribute the AWS API requests to it.
+func KindOf(mg resource.Managed) string {
+	if k := mg.GetObjectKind().GroupVersionKind().Kind; k != "" {
+		return k
+	}
+	t := reflect.TypeOf(mg)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// observeRequest records the metrics of a request that took the supplied
+// duration and returned the supplied error.
+func observeRequest(service, operation, kind string, d time.Duration, err error) {
+	apiRequests.WithLabelValues(service, operation, kind).Inc()
+	apiRequestDuration.WithLabelValues(service, operation, kind).Observe(d.Seconds())
+	if err == nil {
+		return
+	}
+	code := ErrorCode(err)
+	if code == "" {
+		code = "Unknown"
+	}
+	apiRequestErrors.WithLabelValues(service, operation, kind, code).Inc()
+	if IsErrorThrottling(err) {
+		apiRequestThrottles.WithLabelValues(service, operation, kind).Inc()
+	}
+}
+
+// AddMetricsToConfig records the metrics of the requests made by the clients
+// built from the supplied config on behalf of the supplied kind.
+func AddMetricsToConfig(kind string, cfg *aws.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(s *middleware.Stack) error {
+		// NOTE: the middleware is added last so that every attempt is
+		// recorded, excluding the time spent waiting for the rate limiter.
+		return s.Finalize.Add(&metricsMiddleware{kind: kind}, middleware.After)
+	})
+}
+
+type metricsMiddleware struct {
+	kind string
+}
+
+func (*metricsMiddleware) ID() string {
+	return metricsHandlerName
+}
+
+func (m *metricsMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	start := time.Now()
+	out, md, err := next.HandleFinalize(ctx, in)
+	observeRequest(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), m.kind, time.Since(start), err)
+	return out, md, err
+}
+
+// AddMetricsToSession records the metrics of the requests made by the
+// clients built from the supplied session on behalf of the supplied kind.
+func AddMetricsToSession(kind string, sess *session.Session) {
+	starts := &sync.Map{}
+	sess.Handlers.Send.PushFrontNamed(request.NamedHandler{Name: metricsHandlerName, Fn: func(req *request.Request) {
+		starts.Store(req, time.Now())
+	}})
+	sess.Handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{Name: metricsHandlerName, Fn: func(req *request.Request) {
+		start, ok := starts.LoadAndDelete(req)
+		if !ok {
+			start = req.AttemptTime
+		}
+		observeRequest(req.ClientInfo.ServiceID, req.Operation.Name, kind, time.Since(start.(time.Time)), req.Error)
+	}})
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	awsv1 "github.com/aws/aws-sdk-go/aws"
+	credentialsv1 "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	type want struct {
+		requests  float64
+		errors    float64
+		throttles float64
+	}
+
+	cases := map[string]struct {
+		kind string
+		call func(kind string, tr http.RoundTripper)
+		want want
+	}{
+		"SDKv2": {
+			kind: "MetricsV2",
+			call: func(kind string, tr http.RoundTripper) {
+				cfg := aws.Config{
+					Region:      "us-east-1",
+					Credentials: credentials.NewStaticCredentialsProvider("id", "secret", ""),
+					HTTPClient:  &http.Client{Transport: tr},
+					Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+				}
+				AddMetricsToConfig(kind, &cfg)
+				_, _ = sts.NewFromConfig(cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+			},
+			want: want{requests: 2, errors: 1, throttles: 1},
+		},
+		"SDKv1": {
+			kind: "MetricsV1",
+			call: func(kind string, tr http.RoundTripper) {
+				sess, err := session.NewSession(awsv1.NewConfig().
+					WithRegion("us-east-1").
+					WithCredentials(credentialsv1.NewStaticCredentials("id", "secret", "")).
+					WithMaxRetries(0))
+				if err != nil {
+					t.Fatal(err)
+				}
+				AddMetricsToSession(kind, sess)
+				_, _ = stsv1.New(sess, awsv1.NewConfig().WithHTTPClient(&http.Client{Transport: tr})).GetCallerIdentity(&stsv1.GetCallerIdentityInput{})
+			},
+			want: want{requests: 2, errors: 1, throttles: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.call(tc.kind, &fakeTransport{status: http.StatusBadRequest, body: throttlingResponse})
+			tc.call(tc.kind, &fakeTransport{status: http.StatusOK, body: callerIdentityResponse})
+
+			got := want{
+				requests:  testutil.ToFloat64(apiRequests.WithLabelValues("STS", "GetCallerIdentity", tc.kind)),
+				errors:    testutil.ToFloat64(apiRequestErrors.WithLabelValues("STS", "GetCallerIdentity", tc.kind, "Throttling")),
+				throttles: testutil.ToFloat64(apiRequestThrottles.WithLabelValues("STS", "GetCallerIdentity", tc.kind)),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("metrics: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if diff := cmp.Diff("Managed", KindOf(&fake.Managed{})); diff != "" {
+		t.Errorf("r: -want, +got:\n%s", diff)
+	}
+}
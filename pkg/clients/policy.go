@@ -0,0 +1,351 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+const (
+	errParsePolicy     = "cannot parse policy document"
+	errMarshalPolicy   = "cannot marshal policy document"
+	errPolicyPrincipal = "principal must be \"*\" or an object"
+)
+
+// rootARN matches the ARN of the root user of an account, which AWS returns
+// for principals given as a bare account ID.
+var rootARN = regexp.MustCompile(`^arn:[a-z-]+:iam::(\d{12}):root$`)
+
+// A Policy is an IAM policy document in the JSON form sent to and returned by
+// AWS.
+type Policy struct {
+	Version    string           `json:"Version,omitempty"`
+	ID         string           `json:"Id,omitempty"`
+	Statements PolicyStatements `json:"Statement"`
+}
+
+// PolicyStatements are the statements of a Policy. A single statement may be
+// given as an object rather than an array.
+type PolicyStatements []PolicyStatement
+
+// UnmarshalJSON unmarshals a statement or an array of statements.
+func (s *PolicyStatements) UnmarshalJSON(b []byte) error {
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '{' {
+		st := PolicyStatement{}
+		if err := strictUnmarshal(b, &st); err != nil {
+			return err
+		}
+		*s = PolicyStatements{st}
+		return nil
+	}
+	var sts []PolicyStatement
+	if err := json.Unmarshal(b, &sts); err != nil {
+		return err
+	}
+	*s = sts
+	return nil
+}
+
+// A PolicyStatement is a statement of a Policy.
+type PolicyStatement struct {
+	SID          string           `json:"Sid,omitempty"`
+	Effect       string           `json:"Effect"`
+	Principal    *PolicyPrincipal `json:"Principal,omitempty"`
+	NotPrincipal *PolicyPrincipal `json:"NotPrincipal,omitempty"`
+	Action       PolicyValues     `json:"Action,omitempty"`
+	NotAction    PolicyValues     `json:"NotAction,omitempty"`
+	Resource     PolicyValues     `json:"Resource,omitempty"`
+	NotResource  PolicyValues     `json:"NotResource,omitempty"`
+
+	// Condition values keyed by condition operator and condition key.
+	Condition map[string]map[string]PolicyValues `json:"Condition,omitempty"`
+}
+
+// UnmarshalJSON rejects unknown fields, so that JSON documents that are not
+// policies are not mistaken for empty ones.
+func (s *PolicyStatement) UnmarshalJSON(b []byte) error {
+	type statement PolicyStatement
+	st := statement{}
+	if err := strictUnmarshal(b, &st); err != nil {
+		return err
+	}
+	*s = PolicyStatement(st)
+	return nil
+}
+
+// A PolicyPrincipal is the principal of a PolicyStatement.
+type PolicyPrincipal struct {
+	// AllowAnon is the principal "*", i.e. everyone.
+	AllowAnon bool `json:"-"`
+
+	AWS           PolicyValues `json:"AWS,omitempty"`
+	Service       PolicyValues `json:"Service,omitempty"`
+	Federated     PolicyValues `json:"Federated,omitempty"`
+	CanonicalUser PolicyValues `json:"CanonicalUser,omitempty"`
+}
+
+// MarshalJSON marshals the principal "*" as a string.
+func (p PolicyPrincipal) MarshalJSON() ([]byte, error) {
+	if p.AllowAnon {
+		return []byte(`"*"`), nil
+	}
+	type principal PolicyPrincipal
+	return json.Marshal(principal(p))
+}
+
+// UnmarshalJSON unmarshals the principal "*" or a principal object.
+func (p *PolicyPrincipal) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if s != "*" {
+			return errors.New(errPolicyPrincipal)
+		}
+		*p = PolicyPrincipal{AllowAnon: true}
+		return nil
+	}
+	type principal PolicyPrincipal
+	pr := principal{}
+	if err := strictUnmarshal(b, &pr); err != nil {
+		return err
+	}
+	*p = PolicyPrincipal(pr)
+	return nil
+}
+
+// PolicyValues are the values of a policy element that may be given either
+// as a single value or as an array of values.
+type PolicyValues []string
+
+// MarshalJSON marshals a single value as a string.
+func (v PolicyValues) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+// UnmarshalJSON unmarshals a value or an array of values. Boolean and
+// numeric values, which may be used in conditions, are kept as strings.
+func (v *PolicyValues) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		raw = []json.RawMessage{b}
+	}
+	res := make(PolicyValues, len(raw))
+	for i, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			res[i] = s
+			continue
+		}
+		var x interface{}
+		if err := json.Unmarshal(r, &x); err != nil {
+			return err
+		}
+		switch x.(type) {
+		case bool, float64:
+			res[i] = string(bytes.TrimSpace(r))
+		default:
+			return errors.Errorf("unexpected policy value %s", string(r))
+		}
+	}
+	*v = res
+	return nil
+}
+
+func strictUnmarshal(b []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+// ParsePolicy parses the supplied JSON policy document.
+func ParsePolicy(s string) (*Policy, error) {
+	p := &Policy{}
+	if err := strictUnmarshal([]byte(s), p); err != nil {
+		return nil, errors.Wrap(err, errParsePolicy)
+	}
+	return p, nil
+}
+
+// String returns the JSON form of the policy.
+func (p *Policy) String() (string, error) {
+	b, err := json.Marshal(p)
+	return string(b), errors.Wrap(err, errMarshalPolicy)
+}
+
+// NormalizePolicy returns a copy of the supplied policy in a canonical form,
+// in which the order of statements and of values does not matter, duplicate
+// values are removed and account IDs are used for the root users of AWS
+// principals, so that semantically identical policies are equal.
+func NormalizePolicy(p *Policy) *Policy {
+	if p == nil {
+		return nil
+	}
+	res := &Policy{Version: p.Version, ID: p.ID, Statements: make(PolicyStatements, len(p.Statements))}
+	keys := make(map[int]string, len(p.Statements))
+	for i, s := range p.Statements {
+		res.Statements[i] = normalizeStatement(s)
+		b, _ := json.Marshal(res.Statements[i])
+		keys[i] = string(b)
+	}
+	sorted := make([]int, len(res.Statements))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(a, b int) bool { return keys[sorted[a]] < keys[sorted[b]] })
+	sts := make(PolicyStatements, len(sorted))
+	for i, j := range sorted {
+		sts[i] = res.Statements[j]
+	}
+	res.Statements = sts
+	return res
+}
+
+func normalizeStatement(s PolicyStatement) PolicyStatement {
+	res := PolicyStatement{
+		SID:          s.SID,
+		Effect:       s.Effect,
+		Principal:    normalizePrincipal(s.Principal),
+		NotPrincipal: normalizePrincipal(s.NotPrincipal),
+		Action:       normalizeValues(s.Action),
+		NotAction:    normalizeValues(s.NotAction),
+		Resource:     normalizeValues(s.Resource),
+		NotResource:  normalizeValues(s.NotResource),
+	}
+	for op, keys := range s.Condition {
+		for k, v := range keys {
+			if res.Condition == nil {
+				res.Condition = map[string]map[string]PolicyValues{}
+			}
+			if res.Condition[op] == nil {
+				res.Condition[op] = map[string]PolicyValues{}
+			}
+			res.Condition[op][k] = normalizeValues(v)
+		}
+	}
+	return res
+}
+
+func normalizePrincipal(p *PolicyPrincipal) *PolicyPrincipal {
+	if p == nil {
+		return nil
+	}
+	aws := make(PolicyValues, len(p.AWS))
+	for i, v := range p.AWS {
+		if m := rootARN.FindStringSubmatch(v); m != nil {
+			v = m[1]
+		}
+		aws[i] = v
+	}
+	return &PolicyPrincipal{
+		AllowAnon:     p.AllowAnon,
+		AWS:           normalizeValues(aws),
+		Service:       normalizeValues(p.Service),
+		Federated:     normalizeValues(p.Federated),
+		CanonicalUser: normalizeValues(p.CanonicalUser),
+	}
+}
+
+func normalizeValues(v PolicyValues) PolicyValues {
+	if len(v) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(v))
+	res := make(PolicyValues, 0, len(v))
+	for _, s := range v {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		res = append(res, s)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ArePoliciesEqual returns true if the supplied policies are semantically
+// identical, i.e. equal in their canonical form.
+func ArePoliciesEqual(a, b *Policy) bool {
+	return cmp.Equal(NormalizePolicy(a), NormalizePolicy(b), cmpopts.EquateEmpty())
+}
+
+// PolicyFromDocument returns the JSON form of the supplied policy document.
+func PolicyFromDocument(d *v1beta1.PolicyDocument) *Policy {
+	if d == nil {
+		return nil
+	}
+	p := &Policy{Version: d.Version, ID: StringValue(d.ID), Statements: make(PolicyStatements, len(d.Statements))}
+	for i, s := range d.Statements {
+		st := PolicyStatement{
+			SID:          StringValue(s.SID),
+			Effect:       s.Effect,
+			Principal:    principalFromDocument(s.Principal),
+			NotPrincipal: principalFromDocument(s.NotPrincipal),
+			Action:       s.Action,
+			NotAction:    s.NotAction,
+			Resource:     s.Resource,
+			NotResource:  s.NotResource,
+		}
+		for _, c := range s.Condition {
+			if st.Condition == nil {
+				st.Condition = map[string]map[string]PolicyValues{}
+			}
+			if st.Condition[c.Operator] == nil {
+				st.Condition[c.Operator] = map[string]PolicyValues{}
+			}
+			st.Condition[c.Operator][c.Key] = append(st.Condition[c.Operator][c.Key], c.Values...)
+		}
+		p.Statements[i] = st
+	}
+	return p
+}
+
+func principalFromDocument(p *v1beta1.PolicyPrincipal) *PolicyPrincipal {
+	if p == nil {
+		return nil
+	}
+	return &PolicyPrincipal{
+		AllowAnon:     BoolValue(p.AllowAnon),
+		AWS:           p.AWS,
+		Service:       p.Service,
+		Federated:     p.Federated,
+		CanonicalUser: p.CanonicalUser,
+	}
+}
+
+// PolicyDocumentString returns the JSON policy document given either as the
+// supplied typed document or, if it is nil, as the supplied string.
+func PolicyDocumentString(d *v1beta1.PolicyDocument, raw *string) (*string, error) {
+	if d == nil {
+		return raw, nil
+	}
+	s, err := PolicyFromDocument(d).String()
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
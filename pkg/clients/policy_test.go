@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+const queuePolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"Service": "sns.amazonaws.com"},
+		"Action": "sqs:SendMessage",
+		"Resource": "arn:aws:sqs:us-east-1:123456789012:orders",
+		"Condition": {"ArnEquals": {"aws:SourceArn": "arn:aws:sns:us-east-1:123456789012:events"}}
+	}]
+}`
+
+func TestParsePolicy(t *testing.T) {
+	cases := map[string]struct {
+		policy string
+		want   *Policy
+		err    bool
+	}{
+		"SingleStatement": {
+			policy: `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject"], "Condition": {"Bool": {"aws:SecureTransport": true}}}}`,
+			want: &Policy{Statements: PolicyStatements{{
+				Effect:    "Allow",
+				Principal: &PolicyPrincipal{AllowAnon: true},
+				Action:    PolicyValues{"s3:GetObject"},
+				Condition: map[string]map[string]PolicyValues{"Bool": {"aws:SecureTransport": {"true"}}},
+			}}},
+		},
+		"NotAPolicy": {
+			policy: `{"testone": "one"}`,
+			err:    true,
+		},
+		"InvalidPrincipal": {
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": "everyone"}]}`,
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePolicy(tc.policy)
+			if (err != nil) != tc.err {
+				t.Errorf("ParsePolicy(...): unexpected error %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParsePolicy(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPolicyUpToDateCanonical(t *testing.T) {
+	cases := map[string]struct {
+		local  string
+		remote string
+		want   bool
+	}{
+		"SingleValueAndArray": {
+			local:  `{"Statement": [{"Effect": "Allow", "Action": ["sqs:SendMessage"], "Resource": ["*"]}]}`,
+			remote: `{"Statement": {"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "*"}}`,
+			want:   true,
+		},
+		"StatementOrder": {
+			local:  `{"Statement": [{"Effect": "Allow", "Action": "a:A"}, {"Effect": "Deny", "Action": "b:B"}]}`,
+			remote: `{"Statement": [{"Effect": "Deny", "Action": "b:B"}, {"Effect": "Allow", "Action": "a:A"}]}`,
+			want:   true,
+		},
+		"AccountPrincipal": {
+			local:  `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "123456789012"}, "Action": "a:A"}]}`,
+			remote: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "a:A"}]}`,
+			want:   true,
+		},
+		"ConditionValues": {
+			local:  `{"Statement": [{"Effect": "Allow", "Action": "a:A", "Condition": {"NumericLessThan": {"s3:max-keys": 10}}}]}`,
+			remote: `{"Statement": [{"Effect": "Allow", "Action": "a:A", "Condition": {"NumericLessThan": {"s3:max-keys": ["10"]}}}]}`,
+			want:   true,
+		},
+		"DifferentEffect": {
+			local:  `{"Statement": [{"Effect": "Allow", "Action": "a:A"}]}`,
+			remote: `{"Statement": [{"Effect": "Deny", "Action": "a:A"}]}`,
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsPolicyUpToDate(&tc.local, &tc.remote)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsPolicyUpToDate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyDocumentString(t *testing.T) {
+	doc := &v1beta1.PolicyDocument{
+		Version: "2012-10-17",
+		Statements: []v1beta1.PolicyStatement{{
+			Effect:    "Allow",
+			Principal: &v1beta1.PolicyPrincipal{Service: []string{"sns.amazonaws.com"}},
+			Action:    []string{"sqs:SendMessage"},
+			Resource:  []string{"arn:aws:sqs:us-east-1:123456789012:orders"},
+			Condition: []v1beta1.PolicyCondition{{
+				Operator: "ArnEquals",
+				Key:      "aws:SourceArn",
+				Values:   []string{"arn:aws:sns:us-east-1:123456789012:events"},
+			}},
+		}},
+	}
+
+	cases := map[string]struct {
+		doc  *v1beta1.PolicyDocument
+		raw  *string
+		want *string
+	}{
+		"Raw": {
+			raw:  aws.String(queuePolicy),
+			want: aws.String(queuePolicy),
+		},
+		"Typed": {
+			doc:  doc,
+			raw:  aws.String(`{"Statement": []}`),
+			want: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"sns.amazonaws.com"},"Action":"sqs:SendMessage","Resource":"arn:aws:sqs:us-east-1:123456789012:orders","Condition":{"ArnEquals":{"aws:SourceArn":"arn:aws:sns:us-east-1:123456789012:events"}}}]}`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PolicyDocumentString(tc.doc, tc.raw)
+			if err != nil {
+				t.Fatalf("PolicyDocumentString(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PolicyDocumentString(...): -want, +got:\n%s", diff)
+			}
+			if !IsPolicyUpToDate(got, aws.String(queuePolicy)) {
+				t.Errorf("IsPolicyUpToDate(...): %s is not equivalent to %s", *got, queuePolicy)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitDecrease is the factor the rate of a bucket is multiplied by
+	// when a call is throttled.
+	rateLimitDecrease = 0.5
+
+	// rateLimitIncrease is the fraction of the maximum rate that is added to
+	// the rate of a bucket after every successful call.
+	rateLimitIncrease = 0.05
+
+	rateLimitHandlerName = "crossplane.RateLimit"
+
+	errGetAccountID = "cannot get the account ID of the credentials"
+)
+
+// RateLimitOptions configure the client-side rate limits of AWS API calls.
+type RateLimitOptions struct {
+	// RPS is the maximum number of requests per second to a service in a
+	// region of an account. Zero disables rate limiting.
+	RPS float64
+
+	// Burst is the maximum number of requests that can be made at once.
+	Burst int
+
+	// MinRPS is the lowest rate the limiter slows down to while the calls
+	// are throttled.
+	MinRPS float64
+}
+
+// A rateLimitKey identifies a token bucket.
+type rateLimitKey struct {
+	account string
+	region  string
+	service string
+}
+
+// An adaptiveBucket is a token bucket whose rate is halved whenever a call is
+// throttled and is slowly restored as calls succeed.
+type adaptiveBucket struct {
+	limiter *rate.Limiter
+	max     float64
+	min     float64
+}
+
+func (b *adaptiveBucket) observe(throttled bool) {
+	cur := float64(b.limiter.Limit())
+	next := math.Min(b.max, cur+b.max*rateLimitIncrease)
+	if throttled {
+		next = math.Max(b.min, cur*rateLimitDecrease)
+	}
+	if next != cur {
+		b.limiter.SetLimit(rate.Limit(next))
+	}
+}
+
+// A RateLimiter limits the AWS API calls made to every service in every
+// region of every account with a token bucket, and slows down adaptively
+// when the calls are throttled.
+type RateLimiter struct {
+	opts RateLimitOptions
+
+	mu      sync.Mutex
+	buckets map[rateLimitKey]*adaptiveBucket
+}
+
+// NewRateLimiter returns a RateLimiter with the supplied options.
+func NewRateLimiter(o RateLimitOptions) *RateLimiter {
+	if o.Burst < 1 {
+		o.Burst = 1
+	}
+	if o.MinRPS <= 0 || o.MinRPS > o.RPS {
+		o.MinRPS = o.RPS
+	}
+	return &RateLimiter{opts: o, buckets: map[rateLimitKey]*adaptiveBucket{}}
+}
+
+func (r *RateLimiter) bucket(k rateLimitKey) *adaptiveBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buckets[k]
+	if !ok {
+		b = &adaptiveBucket{
+			limiter: rate.NewLimiter(rate.Limit(r.opts.RPS), r.opts.Burst),
+			max:     r.opts.RPS,
+			min:     r.opts.MinRPS,
+		}
+		r.buckets[k] = b
+	}
+	return b
+}
+
+// Limit returns the current rate of the supplied account, region and
+// service.
+func (r *RateLimiter) Limit(account, region, service string) float64 {
+	return float64(r.bucket(rateLimitKey{account: account, region: region, service: service}).limiter.Limit())
+}
+
+// AddToConfig limits the calls made by the clients built from the supplied
+// config on behalf of the supplied account.
+func (r *RateLimiter) AddToConfig(account string, cfg *aws.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(s *middleware.Stack) error {
+		// NOTE: the middleware is added after the retry middleware so that
+		// every attempt is limited.
+		return s.Finalize.Add(&rateLimitMiddleware{limiter: r, account: account}, middleware.After)
+	})
+}
+
+// AddToSession limits the calls made by the clients built from the supplied
+// session on behalf of the supplied account.
+func (r *RateLimiter) AddToSession(account string, sess *session.Session) {
+	key := func(req *request.Request) rateLimitKey {
+		return rateLimitKey{account: account, region: aws.ToString(req.Config.Region), service: req.ClientInfo.ServiceID}
+	}
+	sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{Name: rateLimitHandlerName, Fn: func(req *request.Request) {
+		if err := r.bucket(key(req)).limiter.Wait(req.Context()); err != nil {
+			req.Error = err
+		}
+	}})
+	sess.Handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{Name: rateLimitHandlerName, Fn: func(req *request.Request) {
+		r.bucket(key(req)).observe(IsErrorThrottling(req.Error))
+	}})
+}
+
+type rateLimitMiddleware struct {
+	limiter *RateLimiter
+	account string
+}
+
+func (*rateLimitMiddleware) ID() string {
+	return rateLimitHandlerName
+}
+
+func (m *rateLimitMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	b := m.limiter.bucket(rateLimitKey{account: m.account, region: awsmiddleware.GetRegion(ctx), service: awsmiddleware.GetServiceID(ctx)})
+	if err := b.limiter.Wait(ctx); err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	}
+	out, md, err := next.HandleFinalize(ctx, in)
+	b.observe(IsErrorThrottling(err))
+	return out, md, err
+}
+
+// rateLimiter limits the calls of the clients built by GetConfig and
+// GetConfigV1. It is nil if rate limiting is disabled.
+var rateLimiter *RateLimiter
+
+// SetRateLimits configures the client-side rate limits of the AWS clients. It
+// should be called before any client is built.
+func SetRateLimits(o RateLimitOptions) {
+	if o.RPS <= 0 {
+		rateLimiter = nil
+		return
+	}
+	rateLimiter = NewRateLimiter(o)
+}
+
+// An accountIDCache caches the account IDs of the credentials of
+// ProviderConfigs, so that the calls made on behalf of all the
+// ProviderConfigs of an account share its rate limits. Only the account ID of
+// the latest version of a ProviderConfig is kept.
+type accountIDCache struct {
+	mu  sync.Mutex
+	ids map[string]versionedAccountID
+}
+
+type versionedAccountID struct {
+	version string
+	id      string
+}
+
+// accountIDs caches the account IDs of the credentials of ProviderConfigs.
+var accountIDs = &accountIDCache{ids: map[string]versionedAccountID{}}
+
+// get returns the account ID cached for the supplied version of the supplied
+// ProviderConfig, or caches and returns the one resolved by fn.
+func (c *accountIDCache) get(ctx context.Context, pc, version string, fn func(ctx context.Context) (string, error)) (string, error) {
+	c.mu.Lock()
+	e, ok := c.ids[pc]
+	c.mu.Unlock()
+	if ok && e.version == version {
+		return e.id, nil
+	}
+	id, err := fn(ctx)
+	if err != nil {
+		return "", errors.Wrap(CleanError(err), errGetAccountID)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[pc] = versionedAccountID{version: version, id: id}
+	return id, nil
+}
+
+// forget evicts the account ID cached for the supplied ProviderConfig.
+func (c *accountIDCache) forget(pc string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, pc)
+}
+
+// callerAccountID returns the account ID of the credentials of the supplied
+// config.
+func callerAccountID(cfg aws.Config) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(out.Account), nil
+	}
+}
+
+// callerAccountIDV1 returns the account ID of the credentials of the
+// supplied session.
+func callerAccountIDV1(sess *session.Session) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		out, err := stsv1.New(sess).GetCallerIdentityWithContext(ctx, &stsv1.GetCallerIdentityInput{})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(out.Account), nil
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	awsv1 "github.com/aws/aws-sdk-go/aws"
+	credentialsv1 "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/google/go-cmp/cmp"
+)
+
+const throttlingResponse = `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>Throttling</Code>
+    <Message>Rate exceeded</Message>
+  </Error>
+  <RequestId>request-id</RequestId>
+</ErrorResponse>`
+
+const callerIdentityResponse = `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/crossplane</Arn>
+    <UserId>AIDAEXAMPLE</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata>
+    <RequestId>request-id</RequestId>
+  </ResponseMetadata>
+</GetCallerIdentityResponse>`
+
+// fakeTransport responds to every request with the supplied status and body.
+type fakeTransport struct {
+	status int
+	body   string
+}
+
+func (t *fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: t.status,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       ioutil.NopCloser(strings.NewReader(t.body)),
+		Request:    r,
+	}, nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	type want struct {
+		limitAfterThrottle float64
+		limitAfterSuccess  float64
+	}
+
+	cases := map[string]struct {
+		call func(rl *RateLimiter, tr http.RoundTripper) error
+		want want
+	}{
+		"SDKv2": {
+			call: func(rl *RateLimiter, tr http.RoundTripper) error {
+				cfg := aws.Config{
+					Region:      "us-east-1",
+					Credentials: credentials.NewStaticCredentialsProvider("id", "secret", ""),
+					HTTPClient:  &http.Client{Transport: tr},
+					Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+				}
+				rl.AddToConfig("account", &cfg)
+				_, err := sts.NewFromConfig(cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+				return err
+			},
+			want: want{limitAfterThrottle: 5, limitAfterSuccess: 5.5},
+		},
+		"SDKv1": {
+			call: func(rl *RateLimiter, tr http.RoundTripper) error {
+				sess, err := session.NewSession(awsv1.NewConfig().
+					WithRegion("us-east-1").
+					WithCredentials(credentialsv1.NewStaticCredentials("id", "secret", "")).
+					WithMaxRetries(0))
+				if err != nil {
+					return err
+				}
+				rl.AddToSession("account", sess)
+				_, err = stsv1.New(sess, awsv1.NewConfig().WithHTTPClient(&http.Client{Transport: tr})).GetCallerIdentity(&stsv1.GetCallerIdentityInput{})
+				return err
+			},
+			want: want{limitAfterThrottle: 5, limitAfterSuccess: 5.5},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rl := NewRateLimiter(RateLimitOptions{RPS: 10, Burst: 10, MinRPS: 1})
+
+			err := tc.call(rl, &fakeTransport{status: http.StatusBadRequest, body: throttlingResponse})
+			if !IsErrorThrottling(err) {
+				t.Fatalf("expected a throttling error, got: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.limitAfterThrottle, rl.Limit("account", "us-east-1", "STS")); diff != "" {
+				t.Errorf("limit after throttle: -want, +got:\n%s", diff)
+			}
+
+			if err := tc.call(rl, &fakeTransport{status: http.StatusOK, body: callerIdentityResponse}); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.limitAfterSuccess, rl.Limit("account", "us-east-1", "STS")); diff != "" {
+				t.Errorf("limit after success: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAdaptiveBucketFloor(t *testing.T) {
+	rl := NewRateLimiter(RateLimitOptions{RPS: 4, MinRPS: 1})
+	b := rl.bucket(rateLimitKey{})
+	for i := 0; i < 10; i++ {
+		b.observe(true)
+	}
+	if diff := cmp.Diff(1.0, rl.Limit("", "", "")); diff != "" {
+		t.Errorf("limit: -want, +got:\n%s", diff)
+	}
+}
+
+func TestAccountIDCache(t *testing.T) {
+	tr := &fakeTransport{status: http.StatusOK, body: callerIdentityResponse}
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("id", "secret", ""),
+		HTTPClient:  &http.Client{Transport: tr},
+	}
+	calls := 0
+	resolve := func(ctx context.Context) (string, error) {
+		calls++
+		return callerAccountID(cfg)(ctx)
+	}
+
+	c := &accountIDCache{ids: map[string]versionedAccountID{}}
+	for _, version := range []string{"1", "1", "2"} {
+		id, err := c.get(context.TODO(), "default", version, resolve)
+		if err != nil {
+			t.Fatalf("get(...): %s", err)
+		}
+		if diff := cmp.Diff("123456789012", id); diff != "" {
+			t.Errorf("id: -want, +got:\n%s", diff)
+		}
+	}
+
+	// The account ID is resolved once for every version of the
+	// ProviderConfig.
+	if diff := cmp.Diff(2, calls); diff != "" {
+		t.Errorf("calls: -want, +got:\n%s", diff)
+	}
+}
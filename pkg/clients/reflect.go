@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// forProvider returns the spec.forProvider struct of the supplied managed
+// resource.
+func forProvider(mg resource.Managed) (reflect.Value, bool) {
+	v := reflect.ValueOf(mg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	spec := v.Elem().FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	fp := spec.FieldByName("ForProvider")
+	if !fp.IsValid() || fp.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return fp, true
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/go-ini/ini"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+const (
+	errParseSharedConfig      = "cannot parse credentials secret"
+	errProfileNotFound        = "cannot get %s profile in credentials secret"
+	errProfileNoCredentials   = "profile %s does not have any credentials"
+	errProfileCycle           = "source_profile of profile %s forms a cycle"
+	errProfileDuration        = "cannot parse duration_seconds of profile %s"
+	errProfileNoSourceProfile = "role_arn of profile %s requires a source_profile"
+	errCredentialProcess      = "credential_process of profile %s is disabled; start the provider with --enable-credential-process to allow it"
+
+	// configProfilePrefix is the prefix of the sections of the named profiles
+	// in a shared config file, as opposed to a shared credentials file.
+	configProfilePrefix = "profile "
+)
+
+// credentialProcess is whether the credential_process of the profiles of
+// credentials secrets may be run. It is disabled by default since it runs a
+// command taken from a secret in the container of the provider.
+var credentialProcess bool
+
+// SetCredentialProcess allows or forbids running the credential_process of
+// the profiles of credentials secrets.
+func SetCredentialProcess(enabled bool) {
+	credentialProcess = enabled
+}
+
+// A SharedProfile is a profile of a shared config or credentials file, i.e.
+// ~/.aws/config or ~/.aws/credentials.
+type SharedProfile struct {
+	Name string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	RoleARN         string
+	SourceProfile   string
+	ExternalID      string
+	RoleSessionName string
+	Duration        time.Duration
+
+	CredentialProcess string
+	Region            string
+}
+
+// A SharedConfig is the content of a credentials secret that holds a shared
+// config file, a shared credentials file, or both concatenated.
+type SharedConfig struct {
+	file *ini.File
+}
+
+// ParseSharedConfig parses the supplied shared config.
+func ParseSharedConfig(data []byte) (*SharedConfig, error) {
+	f, err := ini.InsensitiveLoad(data)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSharedConfig)
+	}
+	return &SharedConfig{file: f}, nil
+}
+
+// Profile returns the profile with the supplied name. The keys of the
+// "[name]" section, as written in a credentials file, take precedence over
+// the ones of the "[profile name]" section, as written in a config file.
+func (c *SharedConfig) Profile(name string) (SharedProfile, error) {
+	var sections []*ini.Section
+	for _, n := range []string{name, configProfilePrefix + name} {
+		if s, err := c.file.GetSection(n); err == nil {
+			sections = append(sections, s)
+		}
+	}
+	if len(sections) == 0 {
+		return SharedProfile{}, errors.Errorf(errProfileNotFound, name)
+	}
+	value := func(key string) string {
+		for _, s := range sections {
+			if s.HasKey(key) {
+				return strings.TrimSpace(s.Key(key).Value())
+			}
+		}
+		return ""
+	}
+	p := SharedProfile{
+		Name:              name,
+		AccessKeyID:       value("aws_access_key_id"),
+		SecretAccessKey:   value("aws_secret_access_key"),
+		SessionToken:      value("aws_session_token"),
+		RoleARN:           value("role_arn"),
+		SourceProfile:     value("source_profile"),
+		ExternalID:        value("external_id"),
+		RoleSessionName:   value("role_session_name"),
+		CredentialProcess: value("credential_process"),
+		Region:            value("region"),
+	}
+	if d := value("duration_seconds"); d != "" {
+		s, err := time.ParseDuration(d + "s")
+		if err != nil {
+			return SharedProfile{}, errors.Wrapf(err, errProfileDuration, name)
+		}
+		p.Duration = s
+	}
+	return p, nil
+}
+
+// CredentialsProvider returns a provider of the credentials of the profile
+// with the supplied name. Roles are assumed through STS with the credentials
+// of their source profile, using the supplied config. The credentials that
+// expire are cached and refreshed before they do.
+func (c *SharedConfig) CredentialsProvider(cfg aws.Config, name string) (aws.CredentialsProvider, error) {
+	return c.credentialsProvider(cfg, name, map[string]bool{})
+}
+
+func (c *SharedConfig) credentialsProvider(cfg aws.Config, name string, visited map[string]bool) (aws.CredentialsProvider, error) {
+	if visited[name] {
+		return nil, errors.Errorf(errProfileCycle, name)
+	}
+	visited[name] = true
+	p, err := c.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.RoleARN != "":
+		var src aws.CredentialsProvider
+		switch p.SourceProfile {
+		case "":
+			return nil, errors.Errorf(errProfileNoSourceProfile, name)
+		case name:
+			// A profile may assume a role with its own static credentials.
+			src, err = staticCredentialsProvider(p)
+		default:
+			src, err = c.credentialsProvider(cfg, p.SourceProfile, visited)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return assumeProfileRole(cfg, src, p), nil
+	case p.CredentialProcess != "":
+		if !credentialProcess {
+			return nil, errors.Errorf(errCredentialProcess, name)
+		}
+		return aws.NewCredentialsCache(processcreds.NewProvider(p.CredentialProcess), func(co *aws.CredentialsCacheOptions) {
+			co.ExpiryWindow = assumeRoleExpiryWindow
+		}), nil
+	default:
+		return staticCredentialsProvider(p)
+	}
+}
+
+func staticCredentialsProvider(p SharedProfile) (aws.CredentialsProvider, error) {
+	if p.AccessKeyID == "" || p.SecretAccessKey == "" {
+		return nil, errors.Errorf(errProfileNoCredentials, p.Name)
+	}
+	return credentials.NewStaticCredentialsProvider(p.AccessKeyID, p.SecretAccessKey, p.SessionToken), nil
+}
+
+func assumeProfileRole(cfg aws.Config, src aws.CredentialsProvider, p SharedProfile) aws.CredentialsProvider {
+	if cfg.Region == "" {
+		cfg.Region = stsFallbackRegion
+	}
+	cfg.Credentials = src
+	rp := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), p.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		if p.ExternalID != "" {
+			o.ExternalID = aws.String(p.ExternalID)
+		}
+		o.RoleSessionName = p.RoleSessionName
+		o.Duration = p.Duration
+	})
+	return aws.NewCredentialsCache(rp, func(co *aws.CredentialsCacheOptions) {
+		co.ExpiryWindow = assumeRoleExpiryWindow
+	})
+}
+
+// UseSharedConfig produces a config with the credentials of the profile with
+// the supplied name in the supplied shared config. The region of the profile
+// is used if the supplied region is empty.
+func UseSharedConfig(ctx context.Context, data []byte, profile, region string) (*aws.Config, error) {
+	sc, err := ParseSharedConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	p, err := sc.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		region = p.Region
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load default AWS config")
+	}
+	cfg.Credentials, err = sc.CredentialsProvider(cfg, profile)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// profileName returns the name of the profile of the credentials secret of
+// the supplied ProviderConfig.
+func profileName(pc *v1beta1.ProviderConfig) string {
+	if p := pc.Spec.Credentials.Profile; p != nil && *p != "" {
+		return *p
+	}
+	return DefaultSection
+}
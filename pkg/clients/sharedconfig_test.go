@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awsv1 "github.com/aws/aws-sdk-go/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+const sharedConfig = `
+[default]
+aws_access_key_id = defaultID
+aws_secret_access_key = defaultSecret
+
+[base]
+aws_access_key_id = baseID
+aws_secret_access_key = baseSecret
+aws_session_token = baseToken
+
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = base
+external_id = ext
+duration_seconds = 900
+region = eu-west-1
+
+[profile self]
+aws_access_key_id = selfID
+aws_secret_access_key = selfSecret
+role_arn = arn:aws:iam::123456789012:role/self
+source_profile = self
+
+[profile process]
+credential_process = echo '{"Version": 1, "AccessKeyId": "processID", "SecretAccessKey": "processSecret"}'
+
+[profile loop]
+role_arn = arn:aws:iam::123456789012:role/loop
+source_profile = other
+
+[profile other]
+role_arn = arn:aws:iam::123456789012:role/other
+source_profile = loop
+
+[profile nosource]
+role_arn = arn:aws:iam::123456789012:role/nosource
+
+[profile empty]
+region = us-east-2
+
+[profile override]
+region = us-east-2
+
+[override]
+region = us-west-1
+`
+
+func TestSharedConfigProfile(t *testing.T) {
+	type want struct {
+		p   SharedProfile
+		err error
+	}
+
+	cases := map[string]struct {
+		name string
+		want want
+	}{
+		"CredentialsFileSection": {
+			name: "base",
+			want: want{p: SharedProfile{Name: "base", AccessKeyID: "baseID", SecretAccessKey: "baseSecret", SessionToken: "baseToken"}},
+		},
+		"ConfigFileSection": {
+			name: "admin",
+			want: want{p: SharedProfile{
+				Name:          "admin",
+				RoleARN:       "arn:aws:iam::123456789012:role/admin",
+				SourceProfile: "base",
+				ExternalID:    "ext",
+				Duration:      15 * time.Minute,
+				Region:        "eu-west-1",
+			}},
+		},
+		"CredentialsFileTakesPrecedence": {
+			name: "override",
+			want: want{p: SharedProfile{Name: "override", Region: "us-west-1"}},
+		},
+		"DefaultSection": {
+			name: DefaultSection,
+			want: want{p: SharedProfile{Name: DefaultSection, AccessKeyID: "defaultID", SecretAccessKey: "defaultSecret"}},
+		},
+		"NotFound": {
+			name: "foo",
+			want: want{err: errors.Errorf(errProfileNotFound, "foo")},
+		},
+	}
+
+	sc, err := ParseSharedConfig([]byte(sharedConfig))
+	if err != nil {
+		t.Fatalf("ParseSharedConfig(...): %s", err)
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := sc.Profile(tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Profile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("Profile(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSharedConfigCredentialsProvider(t *testing.T) {
+	type want struct {
+		creds aws.Credentials
+		err   error
+	}
+
+	cases := map[string]struct {
+		name              string
+		credentialProcess bool
+		want              want
+	}{
+		"Static": {
+			name: "base",
+			want: want{creds: aws.Credentials{AccessKeyID: "baseID", SecretAccessKey: "baseSecret", SessionToken: "baseToken", Source: credentials.StaticCredentialsName}},
+		},
+		"CredentialProcess": {
+			name:              "process",
+			credentialProcess: true,
+			want:              want{creds: aws.Credentials{AccessKeyID: "processID", SecretAccessKey: "processSecret", Source: "ProcessProvider"}},
+		},
+		"CredentialProcessDisabled": {
+			name: "process",
+			want: want{err: errors.Errorf(errCredentialProcess, "process")},
+		},
+		"NoCredentials": {
+			name: "empty",
+			want: want{err: errors.Errorf(errProfileNoCredentials, "empty")},
+		},
+		"SourceProfileCycle": {
+			name: "loop",
+			want: want{err: errors.Errorf(errProfileCycle, "loop")},
+		},
+		"NoSourceProfile": {
+			name: "nosource",
+			want: want{err: errors.Errorf(errProfileNoSourceProfile, "nosource")},
+		},
+		"SourceProfileNotFound": {
+			name: "missing",
+			want: want{err: errors.Errorf(errProfileNotFound, "missing")},
+		},
+	}
+
+	sc, err := ParseSharedConfig([]byte(sharedConfig))
+	if err != nil {
+		t.Fatalf("ParseSharedConfig(...): %s", err)
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetCredentialProcess(tc.credentialProcess)
+			defer SetCredentialProcess(false)
+			p, err := sc.CredentialsProvider(aws.Config{}, tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("CredentialsProvider(...): -want error, +got error:\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			creds, err := p.Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("Retrieve(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.creds, creds); diff != "" {
+				t.Errorf("Retrieve(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUseSharedConfigRegion(t *testing.T) {
+	cases := map[string]struct {
+		profile string
+		region  string
+		want    string
+	}{
+		"ProfileRegion": {
+			profile: "admin",
+			want:    "eu-west-1",
+		},
+		"SuppliedRegionTakesPrecedence": {
+			profile: "admin",
+			region:  "us-west-2",
+			want:    "us-west-2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := UseSharedConfig(context.Background(), []byte(sharedConfig), tc.profile, tc.region)
+			if err != nil {
+				t.Fatalf("UseSharedConfig(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, cfg.Region); diff != "" {
+				t.Errorf("UseSharedConfig(...): -want, +got:\n%s", diff)
+			}
+			cfgV1, err := UseSharedConfigV1(context.Background(), []byte(sharedConfig), tc.profile, tc.region)
+			if err != nil {
+				t.Fatalf("UseSharedConfigV1(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, awsv1.StringValue(cfgV1.Region)); diff != "" {
+				t.Errorf("UseSharedConfigV1(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
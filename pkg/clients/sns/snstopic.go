@@ -92,7 +92,9 @@ func LateInitializeTopicAttr(in *v1alpha1.SNSTopicParameters, attrs map[string]s
 	in.DisplayName = awsclients.LateInitializeStringPtr(in.DisplayName, aws.String(attrs[string(TopicDisplayName)]))
 	in.DeliveryPolicy = awsclients.LateInitializeStringPtr(in.DeliveryPolicy, aws.String(attrs[string(TopicDeliveryPolicy)]))
 	in.KMSMasterKeyID = awsclients.LateInitializeStringPtr(in.KMSMasterKeyID, aws.String(attrs[string(TopicKmsMasterKeyID)]))
-	in.Policy = awsclients.LateInitializeStringPtr(in.Policy, aws.String(attrs[string(TopicPolicy)]))
+	if in.PolicyDocument == nil {
+		in.Policy = awsclients.LateInitializeStringPtr(in.Policy, aws.String(attrs[string(TopicPolicy)]))
+	}
 
 }
 
@@ -102,16 +104,22 @@ func LateInitializeTopicAttr(in *v1alpha1.SNSTopicParameters, attrs map[string]s
 // Please see https://docs.aws.amazon.com/sns/latest/api/API_SetTopicAttributes.html
 // So we need to compare each topic attribute and call SetTopicAttribute for ones which has
 // changed.
-func GetChangedAttributes(p v1alpha1.SNSTopicParameters, attrs map[string]string) map[string]string {
-	topicAttrs := getTopicAttributes(p)
+func GetChangedAttributes(p v1alpha1.SNSTopicParameters, attrs map[string]string) (map[string]string, error) {
+	topicAttrs, err := getTopicAttributes(p)
+	if err != nil {
+		return nil, err
+	}
 	changedAttrs := make(map[string]string)
 	for k, v := range topicAttrs {
+		if k == string(TopicPolicy) && isPolicyUpToDate(v, attrs[k]) {
+			continue
+		}
 		if v != attrs[k] {
 			changedAttrs[k] = v
 		}
 	}
 
-	return changedAttrs
+	return changedAttrs, nil
 }
 
 // GenerateTopicObservation is used to produce SNSTopicObservation from attributes
@@ -139,22 +147,37 @@ func GenerateTopicObservation(attr map[string]string) v1alpha1.SNSTopicObservati
 
 // IsSNSTopicUpToDate checks if object is up to date
 func IsSNSTopicUpToDate(p v1alpha1.SNSTopicParameters, attr map[string]string) bool {
+	topicAttrs, err := getTopicAttributes(p)
+	if err != nil {
+		return false
+	}
 	return aws.ToString(p.DeliveryPolicy) == attr[string(TopicDeliveryPolicy)] &&
 		aws.ToString(p.DisplayName) == attr[string(TopicDisplayName)] &&
 		aws.ToString(p.KMSMasterKeyID) == attr[string(TopicKmsMasterKeyID)] &&
-		aws.ToString(p.Policy) == attr[string(TopicPolicy)]
+		isPolicyUpToDate(topicAttrs[string(TopicPolicy)], attr[string(TopicPolicy)])
 }
 
-func getTopicAttributes(p v1alpha1.SNSTopicParameters) map[string]string {
+func isPolicyUpToDate(desired, observed string) bool {
+	if desired == "" || observed == "" {
+		return desired == observed
+	}
+	return awsclients.IsPolicyUpToDate(&desired, &observed)
+}
+
+func getTopicAttributes(p v1alpha1.SNSTopicParameters) (map[string]string, error) {
 
 	topicAttr := make(map[string]string)
 
 	topicAttr[string(TopicDeliveryPolicy)] = aws.ToString(p.DeliveryPolicy)
 	topicAttr[string(TopicDisplayName)] = aws.ToString(p.DisplayName)
 	topicAttr[string(TopicKmsMasterKeyID)] = aws.ToString(p.KMSMasterKeyID)
-	topicAttr[string(TopicPolicy)] = aws.ToString(p.Policy)
+	policy, err := awsclients.PolicyDocumentString(p.PolicyDocument, p.Policy)
+	if err != nil {
+		return nil, err
+	}
+	topicAttr[string(TopicPolicy)] = aws.ToString(policy)
 
-	return topicAttr
+	return topicAttr, nil
 }
 
 // IsTopicNotFound returns true if the error code indicates that the item was not found
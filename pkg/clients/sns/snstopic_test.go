@@ -219,7 +219,10 @@ func TestGetChangedAttributes(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c := GetChangedAttributes(tc.args.p, *tc.args.attr)
+			c, err := GetChangedAttributes(tc.args.p, *tc.args.attr)
+			if err != nil {
+				t.Fatalf("GetChangedAttributes(...): %s", err)
+			}
 			if diff := cmp.Diff(*tc.want, c); diff != "" {
 				t.Errorf("GetChangedAttributes(...): -want, +got:\n%s", diff)
 			}
@@ -32,6 +32,8 @@ type MockSQSClient struct {
 	MockGetQueueAttributes func(ctx context.Context, input *sqs.GetQueueAttributesInput, opts []func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
 	MockSetQueueAttributes func(ctx context.Context, input *sqs.SetQueueAttributesInput, opts []func(*sqs.Options)) (*sqs.SetQueueAttributesOutput, error)
 	MockGetQueueURL        func(ctx context.Context, input *sqs.GetQueueUrlInput, opts []func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	MockReceiveMessage     func(ctx context.Context, input *sqs.ReceiveMessageInput, opts []func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	MockDeleteMessage      func(ctx context.Context, input *sqs.DeleteMessageInput, opts []func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
 }
 
 // CreateQueue mocks CreateQueue
@@ -73,3 +75,13 @@ func (m *MockSQSClient) SetQueueAttributes(ctx context.Context, i *sqs.SetQueueA
 func (m *MockSQSClient) GetQueueUrl(ctx context.Context, i *sqs.GetQueueUrlInput, opts ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) { //nolint:golint
 	return m.MockGetQueueURL(ctx, i, opts)
 }
+
+// ReceiveMessage mocks ReceiveMessage
+func (m *MockSQSClient) ReceiveMessage(ctx context.Context, i *sqs.ReceiveMessageInput, opts ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	return m.MockReceiveMessage(ctx, i, opts)
+}
+
+// DeleteMessage mocks DeleteMessage
+func (m *MockSQSClient) DeleteMessage(ctx context.Context, i *sqs.DeleteMessageInput, opts ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	return m.MockDeleteMessage(ctx, i, opts)
+}
@@ -52,6 +52,8 @@ type Client interface {
 	GetQueueAttributes(ctx context.Context, input *sqs.GetQueueAttributesInput, opts ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
 	SetQueueAttributes(ctx context.Context, input *sqs.SetQueueAttributesInput, opts ...func(*sqs.Options)) (*sqs.SetQueueAttributesOutput, error)
 	GetQueueUrl(ctx context.Context, input *sqs.GetQueueUrlInput, opts ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	ReceiveMessage(ctx context.Context, input *sqs.ReceiveMessageInput, opts ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, input *sqs.DeleteMessageInput, opts ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
 }
 
 // NewClient returns a new SQS Client.
@@ -60,8 +62,11 @@ func NewClient(cfg aws.Config) Client {
 }
 
 // GenerateCreateAttributes returns a map of queue attributes for Create operation
-func GenerateCreateAttributes(p *v1beta1.QueueParameters) map[string]string {
-	m := GenerateQueueAttributes(p)
+func GenerateCreateAttributes(p *v1beta1.QueueParameters) (map[string]string, error) {
+	m, err := GenerateQueueAttributes(p)
+	if err != nil {
+		return nil, err
+	}
 	if aws.ToBool(p.FIFOQueue) {
 		// SQS expects this attribute only if its value is true.
 		// https://github.com/aws/aws-sdk-php/issues/1331
@@ -70,11 +75,11 @@ func GenerateCreateAttributes(p *v1beta1.QueueParameters) map[string]string {
 		}
 		m[v1beta1.AttributeFifoQueue] = "true"
 	}
-	return m
+	return m, nil
 }
 
 // GenerateQueueAttributes returns a map of queue attributes
-func GenerateQueueAttributes(p *v1beta1.QueueParameters) map[string]string { // nolint:gocyclo
+func GenerateQueueAttributes(p *v1beta1.QueueParameters) (map[string]string, error) { // nolint:gocyclo
 	m := map[string]string{}
 	if p.DelaySeconds != nil {
 		m[v1beta1.AttributeDelaySeconds] = strconv.FormatInt(aws.ToInt64(p.DelaySeconds), 10)
@@ -85,8 +90,12 @@ func GenerateQueueAttributes(p *v1beta1.QueueParameters) map[string]string { //
 	if p.MessageRetentionPeriod != nil {
 		m[v1beta1.AttributeMessageRetentionPeriod] = strconv.FormatInt(aws.ToInt64(p.MessageRetentionPeriod), 10)
 	}
-	if p.Policy != nil {
-		m[v1beta1.AttributePolicy] = aws.ToString(p.Policy)
+	policy, err := awsclients.PolicyDocumentString(p.PolicyDocument, p.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		m[v1beta1.AttributePolicy] = aws.ToString(policy)
 	}
 	if p.ReceiveMessageWaitTimeSeconds != nil {
 		m[v1beta1.AttributeReceiveMessageWaitTimeSeconds] = strconv.FormatInt(aws.ToInt64(p.ReceiveMessageWaitTimeSeconds), 10)
@@ -117,9 +126,9 @@ func GenerateQueueAttributes(p *v1beta1.QueueParameters) map[string]string { //
 		m[v1beta1.AttributeContentBasedDeduplication] = strconv.FormatBool(aws.ToBool(p.ContentBasedDeduplication))
 	}
 	if len(m) == 0 {
-		return nil
+		return nil, nil
 	}
-	return m
+	return m, nil
 }
 
 // GenerateQueueObservation returns a QueueObservation with information retrieved
@@ -208,7 +217,7 @@ func IsUpToDate(p v1beta1.QueueParameters, attributes map[string]string, tags ma
 	if !cmp.Equal(aws.ToString(p.KMSMasterKeyID), attributes[v1beta1.AttributeKmsMasterKeyID]) {
 		return false
 	}
-	if !cmp.Equal(aws.ToString(p.Policy), attributes[v1beta1.AttributePolicy]) {
+	if !isPolicyUpToDate(p, attributes[v1beta1.AttributePolicy]) {
 		return false
 	}
 	if attributes[v1beta1.AttributeContentBasedDeduplication] != "" && strconv.FormatBool(aws.ToBool(p.ContentBasedDeduplication)) != attributes[v1beta1.AttributeContentBasedDeduplication] {
@@ -229,6 +238,17 @@ func IsUpToDate(p v1beta1.QueueParameters, attributes map[string]string, tags ma
 	return true
 }
 
+func isPolicyUpToDate(p v1beta1.QueueParameters, observed string) bool {
+	policy, err := awsclients.PolicyDocumentString(p.PolicyDocument, p.Policy)
+	if err != nil {
+		return false
+	}
+	if aws.ToString(policy) == "" || observed == "" {
+		return aws.ToString(policy) == observed
+	}
+	return awsclients.IsPolicyUpToDate(policy, &observed)
+}
+
 // TagsDiff returns the tags added and removed from spec when compared to the AWS SQS tags.
 func TagsDiff(sqsTags map[string]string, newTags map[string]string) (removed, added map[string]string) {
 	removed = map[string]string{}
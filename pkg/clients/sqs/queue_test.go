@@ -216,7 +216,10 @@ func TestGenerateQueueAttributes(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := GenerateQueueAttributes(&tc.in)
+			r, err := GenerateQueueAttributes(&tc.in)
+			if err != nil {
+				t.Fatalf("GenerateQueueAttributes(...): %s", err)
+			}
 			if diff := cmp.Diff(r, tc.out); diff != "" {
 				t.Errorf("GenerateQueueAttributes(...): -want, +got:\n%s", diff)
 			}
@@ -251,7 +254,10 @@ func TestGenerateCreateAttributes(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := GenerateCreateAttributes(&tc.in)
+			r, err := GenerateCreateAttributes(&tc.in)
+			if err != nil {
+				t.Fatalf("GenerateCreateAttributes(...): %s", err)
+			}
 			if diff := cmp.Diff(r, tc.out); diff != "" {
 				t.Errorf("GenerateCreateAttributes(...): -want, +got:\n%s", diff)
 			}
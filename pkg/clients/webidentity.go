@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+const (
+	errNoWebIdentity = "webIdentity must be given when the credentials source is WebIdentity"
+	errGetToken      = "cannot get web identity token"
+)
+
+// webIdentityRoles caches the web identity credential providers of every
+// ProviderConfig so that the STS credentials survive across reconciles.
+var webIdentityRoles = &assumeRoleCache{providers: map[assumeRoleKey]assumeRoleCacheEntry{}}
+
+// UseWebIdentity produces a config with the credentials of the IAM role that
+// the OIDC token of the supplied ProviderConfig is exchanged for through
+// AssumeRoleWithWebIdentity.
+func UseWebIdentity(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+	wi := pc.Spec.Credentials.WebIdentity
+	if wi == nil {
+		return nil, errors.New(errNoWebIdentity)
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load default AWS config")
+	}
+	SetEndpoints(pc, &cfg)
+	token := TokenRetrieverFunc(func() ([]byte, error) {
+		// NOTE: the token is retrieved whenever the credentials are refreshed,
+		// which happens outside of any reconcile.
+		b, err := resource.CommonCredentialExtractor(context.Background(), wi.TokenConfig.Source, c, wi.TokenConfig.CommonCredentialSelectors)
+		return b, errors.Wrap(err, errGetToken)
+	})
+	cfg.Credentials = webIdentityRoles.get(newAssumeRoleKey(pc, cfg.Region), pc.GetResourceVersion(), func() aws.CredentialsProvider {
+		return NewWebIdentityProvider(cfg, *wi, token)
+	})
+	return &cfg, nil
+}
+
+// NewWebIdentityProvider returns a credentials provider that exchanges the
+// token returned by the supplied retriever for the credentials of the
+// configured role. The credentials are cached and refreshed before they
+// expire.
+func NewWebIdentityProvider(cfg aws.Config, wi v1beta1.WebIdentityConfig, token stscreds.IdentityTokenRetriever) aws.CredentialsProvider {
+	if cfg.Region == "" {
+		cfg.Region = stsFallbackRegion
+	}
+	sessionName := aws.ToString(wi.RoleSessionName)
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("crossplane-provider-aws-%d", time.Now().UnixNano())
+	}
+	p := stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), wi.RoleARN, token, func(o *stscreds.WebIdentityRoleOptions) {
+		o.RoleSessionName = sessionName
+	})
+	return aws.NewCredentialsCache(p, func(co *aws.CredentialsCacheOptions) {
+		co.ExpiryWindow = assumeRoleExpiryWindow
+	})
+}
+
+// A TokenRetrieverFunc is a function that retrieves an OIDC token.
+type TokenRetrieverFunc func() ([]byte, error)
+
+// GetIdentityToken calls the TokenRetrieverFunc.
+func (fn TokenRetrieverFunc) GetIdentityToken() ([]byte, error) {
+	return fn()
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+func TestNewWebIdentityProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webidentity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("file-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			s := obj.(*corev1.Secret)
+			s.Data = map[string][]byte{"token": []byte("secret-token")}
+			return nil
+		},
+	}
+
+	cases := map[string]struct {
+		token stscreds.IdentityTokenRetriever
+		want  string
+	}{
+		"TokenFile": {
+			token: stscreds.IdentityTokenFile(tokenFile),
+			want:  "file-token",
+		},
+		"TokenSecret": {
+			token: TokenRetrieverFunc(func() ([]byte, error) {
+				return resource.CommonCredentialExtractor(context.Background(), xpv1.CredentialsSourceSecret, kube, xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "oidc"},
+						Key:             "token",
+					},
+				})
+			}),
+			want: "secret-token",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			stub := &stsStub{}
+			srv := httptest.NewServer(stub)
+			defer srv.Close()
+
+			cfg := aws.Config{
+				EndpointResolver: aws.EndpointResolverFunc(func(_, _ string) (aws.Endpoint, error) {
+					return aws.Endpoint{URL: srv.URL}, nil
+				}),
+			}
+			p := NewWebIdentityProvider(cfg, v1beta1.WebIdentityConfig{RoleARN: roleARN}, tc.token)
+			creds, err := p.Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("Retrieve(...): %s", err)
+			}
+			if diff := cmp.Diff(roleARN, creds.AccessKeyID); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{tc.want}, stub.tokens); diff != "" {
+				t.Errorf("tokens: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
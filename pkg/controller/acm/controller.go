@@ -29,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -41,6 +42,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/acm/v1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/acm"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -70,9 +72,11 @@ func SetupCertificate(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimit
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.Certificate{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.CertificateGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.CertificateGroupVersionKind),
-			managed.WithExternalConnecter(&connector{client: mgr.GetClient(), newClientFn: acm.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{client: mgr.GetClient(), newClientFn: acm.NewClient}, lifecycle.Options(mgr, name), lifecycle.WithExternalName(lifecycle.FullARN))),
 			managed.WithConnectionPublishers(),
 			managed.WithPollInterval(poll),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
@@ -129,7 +133,9 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 
 	certificate := *response.Certificate
 	current := cr.Spec.ForProvider.DeepCopy()
-	acm.LateInitializeCertificate(&cr.Spec.ForProvider, &certificate)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		acm.LateInitializeCertificate(p.(*v1alpha1.CertificateParameters), &certificate)
+	})
 	if !cmp.Equal(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errKubeUpdateFailed)
@@ -151,6 +157,7 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 	return managed.ExternalObservation{
 		ResourceUpToDate: acm.IsCertificateUpToDate(cr.Spec.ForProvider, certificate, tags.Tags),
 		ResourceExists:   true,
+		Diff:             diff,
 	}, nil
 }
 
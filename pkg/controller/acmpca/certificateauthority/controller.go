@@ -29,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -41,6 +42,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/acmpca/v1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/acmpca"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -68,9 +70,11 @@ func SetupCertificateAuthority(mgr ctrl.Manager, l logging.Logger, rl workqueue.
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.CertificateAuthority{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.CertificateAuthorityGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.CertificateAuthorityGroupVersionKind),
-			managed.WithExternalConnecter(&connector{client: mgr.GetClient(), newClientFn: acmpca.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{client: mgr.GetClient(), newClientFn: acmpca.NewClient}, lifecycle.Options(mgr, name), lifecycle.WithExternalName(lifecycle.FullARN))),
 			managed.WithConnectionPublishers(),
 			managed.WithPollInterval(poll),
 
@@ -129,7 +133,9 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 
 	certificateAuthority := *response.CertificateAuthority
 	current := cr.Spec.ForProvider.DeepCopy()
-	acmpca.LateInitializeCertificateAuthority(&cr.Spec.ForProvider, &certificateAuthority)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		acmpca.LateInitializeCertificateAuthority(p.(*v1alpha1.CertificateAuthorityParameters), &certificateAuthority)
+	})
 
 	if !cmp.Equal(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
@@ -153,6 +159,7 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 	return managed.ExternalObservation{
 		ResourceExists:   true,
 		ResourceUpToDate: acmpca.IsCertificateAuthorityUpToDate(cr, certificateAuthority, tags.Tags),
+		Diff:             diff,
 	}, nil
 }
 
@@ -29,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -41,6 +42,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/acmpca/v1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/acmpca"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -60,9 +62,11 @@ func SetupCertificateAuthorityPermission(mgr ctrl.Manager, l logging.Logger, rl
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.CertificateAuthorityPermission{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.CertificateAuthorityPermissionGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.CertificateAuthorityPermissionGroupVersionKind),
-			managed.WithExternalConnecter(&connector{client: mgr.GetClient(), newClientFn: acmpca.NewCAPermissionClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{client: mgr.GetClient(), newClientFn: acmpca.NewCAPermissionClient}, lifecycle.Options(mgr, name))),
 			managed.WithConnectionPublishers(),
 			managed.WithPollInterval(poll),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
@@ -24,6 +24,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +36,7 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/apigatewayv2/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupAPI adds a controller that reconciles API.
@@ -54,9 +56,11 @@ func SetupAPI(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.API{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.APIGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.APIGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name), lifecycle.WithExternalName(lifecycle.ResourceName))),
 			managed.WithInitializers(),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
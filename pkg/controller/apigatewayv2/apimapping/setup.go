@@ -24,6 +24,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -35,6 +36,7 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/apigatewayv2/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupAPIMapping adds a controller that reconciles APIMapping.
@@ -55,9 +57,11 @@ func SetupAPIMapping(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimite
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.APIMapping{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.APIMappingGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.APIMappingGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name), lifecycle.WithExternalName(lifecycle.ResourceName))),
 			managed.WithInitializers(),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
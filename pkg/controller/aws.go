@@ -19,11 +19,49 @@ package controller
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	acmv1alpha1 "github.com/crossplane/provider-aws/apis/acm/v1alpha1"
+	acmpcav1alpha1 "github.com/crossplane/provider-aws/apis/acmpca/v1alpha1"
+	apigatewayv2v1alpha1 "github.com/crossplane/provider-aws/apis/apigatewayv2/v1alpha1"
+	cachev1alpha1 "github.com/crossplane/provider-aws/apis/cache/v1alpha1"
+	cachev1beta1 "github.com/crossplane/provider-aws/apis/cache/v1beta1"
+	cloudformationv1alpha1 "github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
+	cloudfrontv1alpha1 "github.com/crossplane/provider-aws/apis/cloudfront/v1alpha1"
+	databasev1beta1 "github.com/crossplane/provider-aws/apis/database/v1beta1"
+	docdbv1alpha1 "github.com/crossplane/provider-aws/apis/docdb/v1alpha1"
+	dynamodbv1alpha1 "github.com/crossplane/provider-aws/apis/dynamodb/v1alpha1"
+	ec2manualv1alpha1 "github.com/crossplane/provider-aws/apis/ec2/manualv1alpha1"
+	ec2v1alpha1 "github.com/crossplane/provider-aws/apis/ec2/v1alpha1"
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+	ecrv1alpha1 "github.com/crossplane/provider-aws/apis/ecr/v1alpha1"
+	efsv1alpha1 "github.com/crossplane/provider-aws/apis/efs/v1alpha1"
+	eksv1alpha1 "github.com/crossplane/provider-aws/apis/eks/v1alpha1"
+	eksv1beta1 "github.com/crossplane/provider-aws/apis/eks/v1beta1"
+	elasticloadbalancingv1alpha1 "github.com/crossplane/provider-aws/apis/elasticloadbalancing/v1alpha1"
+	gluev1alpha1 "github.com/crossplane/provider-aws/apis/glue/v1alpha1"
+	identityv1alpha1 "github.com/crossplane/provider-aws/apis/identity/v1alpha1"
+	identityv1beta1 "github.com/crossplane/provider-aws/apis/identity/v1beta1"
+	kafkav1alpha1 "github.com/crossplane/provider-aws/apis/kafka/v1alpha1"
+	kmsv1alpha1 "github.com/crossplane/provider-aws/apis/kms/v1alpha1"
+	lambdav1alpha1 "github.com/crossplane/provider-aws/apis/lambda/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-aws/apis/notification/v1alpha1"
+	rdsv1alpha1 "github.com/crossplane/provider-aws/apis/rds/v1alpha1"
+	redshiftv1alpha1 "github.com/crossplane/provider-aws/apis/redshift/v1alpha1"
+	route53v1alpha1 "github.com/crossplane/provider-aws/apis/route53/v1alpha1"
+	route53resolverv1alpha1 "github.com/crossplane/provider-aws/apis/route53resolver/v1alpha1"
+	s3v1alpha3 "github.com/crossplane/provider-aws/apis/s3/v1alpha3"
+	s3v1beta1 "github.com/crossplane/provider-aws/apis/s3/v1beta1"
+	secretsmanagerv1alpha1 "github.com/crossplane/provider-aws/apis/secretsmanager/v1alpha1"
+	servicediscoveryv1alpha1 "github.com/crossplane/provider-aws/apis/servicediscovery/v1alpha1"
+	sfnv1alpha1 "github.com/crossplane/provider-aws/apis/sfn/v1alpha1"
+	sqsv1beta1 "github.com/crossplane/provider-aws/apis/sqs/v1beta1"
+	transferv1alpha1 "github.com/crossplane/provider-aws/apis/transfer/v1alpha1"
+
 	"github.com/crossplane/provider-aws/pkg/controller/acm"
 	"github.com/crossplane/provider-aws/pkg/controller/acmpca/certificateauthority"
 	"github.com/crossplane/provider-aws/pkg/controller/acmpca/certificateauthoritypermission"
@@ -42,6 +80,7 @@ import (
 	"github.com/crossplane/provider-aws/pkg/controller/cache"
 	"github.com/crossplane/provider-aws/pkg/controller/cache/cachesubnetgroup"
 	"github.com/crossplane/provider-aws/pkg/controller/cache/cluster"
+	"github.com/crossplane/provider-aws/pkg/controller/cloudformation/stack"
 	"github.com/crossplane/provider-aws/pkg/controller/cloudfront/cachepolicy"
 	"github.com/crossplane/provider-aws/pkg/controller/cloudfront/distribution"
 	"github.com/crossplane/provider-aws/pkg/controller/config"
@@ -117,102 +156,110 @@ import (
 	transferuser "github.com/crossplane/provider-aws/pkg/controller/transfer/user"
 )
 
-// Setup creates all AWS controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger, workqueue.RateLimiter, time.Duration) error{
-		cache.SetupReplicationGroup,
-		cachesubnetgroup.SetupCacheSubnetGroup,
-		cluster.SetupCacheCluster,
-		database.SetupRDSInstance,
-		docdbinstance.SetupDBInstance,
-		docdbcluster.SetupDBCluster,
-		docdbclusterparametergroup.SetupDBClusterParameterGroup,
-		docdbsubnetgroup.SetupDBSubnetGroup,
-		eks.SetupCluster,
-		elb.SetupELB,
-		elbattachment.SetupELBAttachment,
-		nodegroup.SetupNodeGroup,
-		s3.SetupBucket,
-		bucketpolicy.SetupBucketPolicy,
-		iamaccesskey.SetupIAMAccessKey,
-		iamuser.SetupIAMUser,
-		iamgroup.SetupIAMGroup,
-		iampolicy.SetupIAMPolicy,
-		iamrole.SetupIAMRole,
-		iamgroupusermembership.SetupIAMGroupUserMembership,
-		iamuserpolicyattachment.SetupIAMUserPolicyAttachment,
-		iamgrouppolicyattachment.SetupIAMGroupPolicyAttachment,
-		iamrolepolicyattachment.SetupIAMRolePolicyAttachment,
-		vpc.SetupVPC,
-		subnet.SetupSubnet,
-		securitygroup.SetupSecurityGroup,
-		internetgateway.SetupInternetGateway,
-		natgateway.SetupNatGateway,
-		routetable.SetupRouteTable,
-		dbsubnetgroup.SetupDBSubnetGroup,
-		certificateauthority.SetupCertificateAuthority,
-		certificateauthoritypermission.SetupCertificateAuthorityPermission,
-		acm.SetupCertificate,
-		resourcerecordset.SetupResourceRecordSet,
-		hostedzone.SetupHostedZone,
-		secret.SetupSecret,
-		snstopic.SetupSNSTopic,
-		snssubscription.SetupSubscription,
-		queue.SetupQueue,
-		redshift.SetupCluster,
-		address.SetupAddress,
-		repository.SetupRepository,
-		repositorypolicy.SetupRepositoryPolicy,
-		api.SetupAPI,
-		stage.SetupStage,
-		route.SetupRoute,
-		authorizer.SetupAuthorizer,
-		integration.SetupIntegration,
-		deployment.SetupDeployment,
-		domainname.SetupDomainName,
-		integrationresponse.SetupIntegrationResponse,
-		model.SetupModel,
-		apimapping.SetupAPIMapping,
-		routeresponse.SetupRouteResponse,
-		vpclink.SetupVPCLink,
-		fargateprofile.SetupFargateProfile,
-		activity.SetupActivity,
-		statemachine.SetupStateMachine,
-		table.SetupTable,
-		backup.SetupBackup,
-		globaltable.SetupGlobalTable,
-		key.SetupKey,
-		filesystem.SetupFileSystem,
-		dbcluster.SetupDBCluster,
-		dbclusterparametergroup.SetupDBClusterParameterGroup,
-		dbinstance.SetupDBInstance,
-		dbparametergroup.SetupDBParameterGroup,
-		globalcluster.SetupGlobalCluster,
-		vpccidrblock.SetupVPCCIDRBlock,
-		privatednsnamespace.SetupPrivateDNSNamespace,
-		publicdnsnamespace.SetupPublicDNSNamespace,
-		httpnamespace.SetupHTTPNamespace,
-		function.SetupFunction,
-		openidconnectprovider.SetupOpenIDConnectProvider,
-		distribution.SetupDistribution,
-		cachepolicy.SetupCachePolicy,
-		resolverendpoint.SetupResolverEndpoint,
-		resolverrule.SetupResolverRule,
-		vpcpeeringconnection.SetupVPCPeeringConnection,
-		kafkacluster.SetupCluster,
-		efsmounttarget.SetupMountTarget,
-		transferserver.SetupServer,
-		transferuser.SetupUser,
-		instance.SetupInstance,
-		gluejob.SetupJob,
-		gluesecurityconfiguration.SetupSecurityConfiguration,
-		glueconnection.SetupConnection,
-		glueDatabase.SetupDatabase,
-		gluecrawler.SetupCrawler,
-		glueclassifier.SetupClassifier,
+// Setup creates the AWS controllers enabled by the supplied filter with the
+// supplied logger and adds them to the supplied manager.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration, f Filter) error {
+	for _, c := range []struct {
+		kind  schema.GroupVersionKind
+		setup func(ctrl.Manager, logging.Logger, workqueue.RateLimiter, time.Duration) error
+	}{
+		{cachev1beta1.ReplicationGroupGroupVersionKind, cache.SetupReplicationGroup},
+		{cachev1alpha1.CacheSubnetGroupGroupVersionKind, cachesubnetgroup.SetupCacheSubnetGroup},
+		{cachev1alpha1.CacheClusterGroupVersionKind, cluster.SetupCacheCluster},
+		{databasev1beta1.RDSInstanceGroupVersionKind, database.SetupRDSInstance},
+		{docdbv1alpha1.DBInstanceGroupVersionKind, docdbinstance.SetupDBInstance},
+		{docdbv1alpha1.DBClusterGroupVersionKind, docdbcluster.SetupDBCluster},
+		{docdbv1alpha1.DBClusterParameterGroupGroupVersionKind, docdbclusterparametergroup.SetupDBClusterParameterGroup},
+		{docdbv1alpha1.DBSubnetGroupGroupVersionKind, docdbsubnetgroup.SetupDBSubnetGroup},
+		{eksv1beta1.ClusterGroupVersionKind, eks.SetupCluster},
+		{elasticloadbalancingv1alpha1.ELBGroupVersionKind, elb.SetupELB},
+		{elasticloadbalancingv1alpha1.ELBAttachmentGroupVersionKind, elbattachment.SetupELBAttachment},
+		{eksv1alpha1.NodeGroupGroupVersionKind, nodegroup.SetupNodeGroup},
+		{s3v1beta1.BucketGroupVersionKind, s3.SetupBucket},
+		{s3v1alpha3.BucketPolicyGroupVersionKind, bucketpolicy.SetupBucketPolicy},
+		{identityv1alpha1.IAMAccessKeyGroupVersionKind, iamaccesskey.SetupIAMAccessKey},
+		{identityv1alpha1.IAMUserGroupVersionKind, iamuser.SetupIAMUser},
+		{identityv1alpha1.IAMGroupGroupVersionKind, iamgroup.SetupIAMGroup},
+		{identityv1alpha1.IAMPolicyGroupVersionKind, iampolicy.SetupIAMPolicy},
+		{identityv1beta1.IAMRoleGroupVersionKind, iamrole.SetupIAMRole},
+		{identityv1alpha1.IAMGroupUserMembershipGroupVersionKind, iamgroupusermembership.SetupIAMGroupUserMembership},
+		{identityv1alpha1.IAMUserPolicyAttachmentGroupVersionKind, iamuserpolicyattachment.SetupIAMUserPolicyAttachment},
+		{identityv1alpha1.IAMGroupPolicyAttachmentGroupVersionKind, iamgrouppolicyattachment.SetupIAMGroupPolicyAttachment},
+		{identityv1beta1.IAMRolePolicyAttachmentGroupVersionKind, iamrolepolicyattachment.SetupIAMRolePolicyAttachment},
+		{ec2v1beta1.VPCGroupVersionKind, vpc.SetupVPC},
+		{ec2v1beta1.SubnetGroupVersionKind, subnet.SetupSubnet},
+		{ec2v1beta1.SecurityGroupGroupVersionKind, securitygroup.SetupSecurityGroup},
+		{ec2v1beta1.InternetGatewayGroupVersionKind, internetgateway.SetupInternetGateway},
+		{ec2v1beta1.NATGatewayGroupVersionKind, natgateway.SetupNatGateway},
+		{ec2v1beta1.RouteTableGroupVersionKind, routetable.SetupRouteTable},
+		{databasev1beta1.DBSubnetGroupGroupVersionKind, dbsubnetgroup.SetupDBSubnetGroup},
+		{acmpcav1alpha1.CertificateAuthorityGroupVersionKind, certificateauthority.SetupCertificateAuthority},
+		{acmpcav1alpha1.CertificateAuthorityPermissionGroupVersionKind, certificateauthoritypermission.SetupCertificateAuthorityPermission},
+		{acmv1alpha1.CertificateGroupVersionKind, acm.SetupCertificate},
+		{route53v1alpha1.ResourceRecordSetGroupVersionKind, resourcerecordset.SetupResourceRecordSet},
+		{route53v1alpha1.HostedZoneGroupVersionKind, hostedzone.SetupHostedZone},
+		{secretsmanagerv1alpha1.SecretGroupVersionKind, secret.SetupSecret},
+		{notificationv1alpha1.SNSTopicGroupVersionKind, snstopic.SetupSNSTopic},
+		{notificationv1alpha1.SNSSubscriptionGroupVersionKind, snssubscription.SetupSubscription},
+		{sqsv1beta1.QueueGroupVersionKind, queue.SetupQueue},
+		{redshiftv1alpha1.ClusterGroupVersionKind, redshift.SetupCluster},
+		{ec2v1beta1.AddressGroupVersionKind, address.SetupAddress},
+		{ecrv1alpha1.RepositoryGroupVersionKind, repository.SetupRepository},
+		{ecrv1alpha1.RepositoryPolicyGroupVersionKind, repositorypolicy.SetupRepositoryPolicy},
+		{apigatewayv2v1alpha1.APIGroupVersionKind, api.SetupAPI},
+		{apigatewayv2v1alpha1.StageGroupVersionKind, stage.SetupStage},
+		{apigatewayv2v1alpha1.RouteGroupVersionKind, route.SetupRoute},
+		{apigatewayv2v1alpha1.AuthorizerGroupVersionKind, authorizer.SetupAuthorizer},
+		{apigatewayv2v1alpha1.IntegrationGroupVersionKind, integration.SetupIntegration},
+		{apigatewayv2v1alpha1.DeploymentGroupVersionKind, deployment.SetupDeployment},
+		{apigatewayv2v1alpha1.DomainNameGroupVersionKind, domainname.SetupDomainName},
+		{apigatewayv2v1alpha1.IntegrationResponseGroupVersionKind, integrationresponse.SetupIntegrationResponse},
+		{apigatewayv2v1alpha1.ModelGroupVersionKind, model.SetupModel},
+		{apigatewayv2v1alpha1.APIMappingGroupVersionKind, apimapping.SetupAPIMapping},
+		{apigatewayv2v1alpha1.RouteResponseGroupVersionKind, routeresponse.SetupRouteResponse},
+		{apigatewayv2v1alpha1.VPCLinkGroupVersionKind, vpclink.SetupVPCLink},
+		{eksv1alpha1.FargateProfileGroupVersionKind, fargateprofile.SetupFargateProfile},
+		{sfnv1alpha1.ActivityGroupVersionKind, activity.SetupActivity},
+		{sfnv1alpha1.StateMachineGroupVersionKind, statemachine.SetupStateMachine},
+		{dynamodbv1alpha1.TableGroupVersionKind, table.SetupTable},
+		{dynamodbv1alpha1.BackupGroupVersionKind, backup.SetupBackup},
+		{dynamodbv1alpha1.GlobalTableGroupVersionKind, globaltable.SetupGlobalTable},
+		{kmsv1alpha1.KeyGroupVersionKind, key.SetupKey},
+		{efsv1alpha1.FileSystemGroupVersionKind, filesystem.SetupFileSystem},
+		{rdsv1alpha1.DBClusterGroupVersionKind, dbcluster.SetupDBCluster},
+		{rdsv1alpha1.DBClusterParameterGroupGroupVersionKind, dbclusterparametergroup.SetupDBClusterParameterGroup},
+		{rdsv1alpha1.DBInstanceGroupVersionKind, dbinstance.SetupDBInstance},
+		{rdsv1alpha1.DBParameterGroupGroupVersionKind, dbparametergroup.SetupDBParameterGroup},
+		{rdsv1alpha1.GlobalClusterGroupVersionKind, globalcluster.SetupGlobalCluster},
+		{ec2manualv1alpha1.VPCCIDRBlockGroupVersionKind, vpccidrblock.SetupVPCCIDRBlock},
+		{servicediscoveryv1alpha1.PrivateDNSNamespaceGroupVersionKind, privatednsnamespace.SetupPrivateDNSNamespace},
+		{servicediscoveryv1alpha1.PublicDNSNamespaceGroupVersionKind, publicdnsnamespace.SetupPublicDNSNamespace},
+		{servicediscoveryv1alpha1.HTTPNamespaceGroupVersionKind, httpnamespace.SetupHTTPNamespace},
+		{lambdav1alpha1.FunctionGroupVersionKind, function.SetupFunction},
+		{identityv1alpha1.OpenIDConnectProviderGroupVersionKind, openidconnectprovider.SetupOpenIDConnectProvider},
+		{cloudfrontv1alpha1.DistributionGroupVersionKind, distribution.SetupDistribution},
+		{cloudfrontv1alpha1.CachePolicyGroupVersionKind, cachepolicy.SetupCachePolicy},
+		{route53resolverv1alpha1.ResolverEndpointGroupVersionKind, resolverendpoint.SetupResolverEndpoint},
+		{route53resolverv1alpha1.ResolverRuleGroupVersionKind, resolverrule.SetupResolverRule},
+		{ec2v1alpha1.VPCPeeringConnectionGroupVersionKind, vpcpeeringconnection.SetupVPCPeeringConnection},
+		{kafkav1alpha1.ClusterGroupVersionKind, kafkacluster.SetupCluster},
+		{efsv1alpha1.MountTargetGroupVersionKind, efsmounttarget.SetupMountTarget},
+		{transferv1alpha1.ServerGroupVersionKind, transferserver.SetupServer},
+		{transferv1alpha1.UserGroupVersionKind, transferuser.SetupUser},
+		{ec2manualv1alpha1.InstanceGroupVersionKind, instance.SetupInstance},
+		{gluev1alpha1.JobGroupVersionKind, gluejob.SetupJob},
+		{gluev1alpha1.SecurityConfigurationGroupVersionKind, gluesecurityconfiguration.SetupSecurityConfiguration},
+		{gluev1alpha1.ConnectionGroupVersionKind, glueconnection.SetupConnection},
+		{gluev1alpha1.DatabaseGroupVersionKind, glueDatabase.SetupDatabase},
+		{gluev1alpha1.CrawlerGroupVersionKind, gluecrawler.SetupCrawler},
+		{gluev1alpha1.ClassifierGroupVersionKind, glueclassifier.SetupClassifier},
+		{cloudformationv1alpha1.StackGroupVersionKind, stack.SetupStack},
 	} {
-		if err := setup(mgr, l, rl, poll); err != nil {
+		if !f.Allows(c.kind.GroupKind()) {
+			l.Debug("Skipping disabled controller", "kind", c.kind.GroupKind().String())
+			continue
+		}
+		if err := c.setup(mgr, l, rl, poll); err != nil {
 			return err
 		}
 	}
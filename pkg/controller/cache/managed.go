@@ -30,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -42,7 +43,9 @@ import (
 
 	"github.com/crossplane/provider-aws/apis/cache/v1beta1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/elasticache"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // Error strings.
@@ -55,8 +58,13 @@ const (
 	errCreateReplicationGroup   = "cannot create ElastiCache replication group"
 	errModifyReplicationGroup   = "cannot modify ElastiCache replication group"
 	errDeleteReplicationGroup   = "cannot delete ElastiCache replication group"
+	errDescribeSnapshot         = "cannot describe ElastiCache snapshot"
+	errCreateSnapshot           = "cannot create ElastiCache snapshot"
 )
 
+// maxNameLength is the maximum length of the name of a replication group ID.
+const maxNameLength = 40
+
 // SetupReplicationGroup adds a controller that reconciles ReplicationGroups.
 func SetupReplicationGroup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
 	name := managed.ControllerName(v1beta1.ReplicationGroupGroupKind)
@@ -67,9 +75,12 @@ func SetupReplicationGroup(mgr ctrl.Manager, l logging.Logger, rl workqueue.Rate
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1beta1.ReplicationGroup{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1beta1.ReplicationGroupGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.ReplicationGroupGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: elasticache.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: elasticache.NewClient}, lifecycle.Options(mgr, name), lifecycle.WithReplacementName(lifecycle.SuffixedName(maxNameLength)))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), managed.NewNameAsExternalName(mgr.GetClient()), &tagger{kube: mgr.GetClient()}),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(poll),
@@ -125,7 +136,9 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	current := cr.Spec.ForProvider.DeepCopy()
-	elasticache.LateInitialize(&cr.Spec.ForProvider, rg, oneCC)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		elasticache.LateInitialize(p.(*v1beta1.ReplicationGroupParameters), rg, oneCC)
+	})
 	if !reflect.DeepEqual(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errUpdateReplicationGroupCR)
@@ -148,6 +161,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		ResourceExists:    true,
 		ResourceUpToDate:  !elasticache.ReplicationGroupNeedsUpdate(cr.Spec.ForProvider, rg, ccList),
 		ConnectionDetails: elasticache.ConnectionEndpoint(rg),
+		Diff:              diff,
 	}, nil
 }
 
@@ -212,6 +226,27 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	return awsclient.Wrap(resource.Ignore(elasticache.IsNotFound, err), errDeleteReplicationGroup)
 }
 
+func (e *external) Snapshot(ctx context.Context, mg resource.Managed, name string) (lifecycle.Snapshot, error) {
+	rsp, err := e.client.DescribeSnapshots(ctx, &awselasticache.DescribeSnapshotsInput{SnapshotName: aws.String(name)})
+	if resource.Ignore(elasticache.IsSnapshotNotFound, err) != nil {
+		return lifecycle.Snapshot{}, awsclient.Wrap(err, errDescribeSnapshot)
+	}
+	if err == nil && len(rsp.Snapshots) == 1 {
+		return lifecycle.Snapshot{
+			ID:        aws.ToString(rsp.Snapshots[0].ARN),
+			Completed: aws.ToString(rsp.Snapshots[0].SnapshotStatus) == elasticache.SnapshotStatusAvailable,
+		}, nil
+	}
+	out, err := e.client.CreateSnapshot(ctx, &awselasticache.CreateSnapshotInput{
+		ReplicationGroupId: aws.String(meta.GetExternalName(mg)),
+		SnapshotName:       aws.String(name),
+	})
+	if err != nil {
+		return lifecycle.Snapshot{}, awsclient.Wrap(err, errCreateSnapshot)
+	}
+	return lifecycle.Snapshot{ID: aws.ToString(out.Snapshot.ARN)}, nil
+}
+
 type tagger struct {
 	kube client.Client
 }
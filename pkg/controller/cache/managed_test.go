@@ -38,6 +38,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/cache/v1beta1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/elasticache/fake"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -144,6 +145,7 @@ func replicationGroup(rm ...replicationGroupModifier) *v1beta1.ReplicationGroup
 // Test that our Reconciler implementation satisfies the Reconciler interface.
 var _ managed.ExternalClient = &external{}
 var _ managed.ExternalConnecter = &connector{}
+var _ lifecycle.Snapshotter = &external{}
 
 func TestCreate(t *testing.T) {
 	cases := []testCase{
@@ -538,6 +540,73 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestSnapshot(t *testing.T) {
+	snapshotName := name + "-final-20211018-153045"
+	snapshotARN := "arn:aws:elasticache:us-east-1:123456789012:snapshot:" + snapshotName
+
+	cases := map[string]struct {
+		e       *external
+		want    lifecycle.Snapshot
+		wantErr error
+	}{
+		"Completed": {
+			e: &external{client: &fake.MockClient{
+				MockDescribeSnapshots: func(ctx context.Context, _ *elasticache.DescribeSnapshotsInput, opts []func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error) {
+					return &elasticache.DescribeSnapshotsOutput{
+						Snapshots: []types.Snapshot{{ARN: &snapshotARN, SnapshotStatus: aws.String("available")}},
+					}, nil
+				},
+			}},
+			want: lifecycle.Snapshot{ID: snapshotARN, Completed: true},
+		},
+		"Created": {
+			e: &external{client: &fake.MockClient{
+				MockDescribeSnapshots: func(ctx context.Context, _ *elasticache.DescribeSnapshotsInput, opts []func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error) {
+					return nil, &types.SnapshotNotFoundFault{}
+				},
+				MockCreateSnapshot: func(ctx context.Context, in *elasticache.CreateSnapshotInput, opts []func(*elasticache.Options)) (*elasticache.CreateSnapshotOutput, error) {
+					if aws.ToString(in.ReplicationGroupId) != name || aws.ToString(in.SnapshotName) != snapshotName {
+						return nil, errorBoom
+					}
+					return &elasticache.CreateSnapshotOutput{Snapshot: &types.Snapshot{ARN: &snapshotARN}}, nil
+				},
+			}},
+			want: lifecycle.Snapshot{ID: snapshotARN},
+		},
+		"DescribeFailed": {
+			e: &external{client: &fake.MockClient{
+				MockDescribeSnapshots: func(ctx context.Context, _ *elasticache.DescribeSnapshotsInput, opts []func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error) {
+					return nil, errorBoom
+				},
+			}},
+			wantErr: awsclient.Wrap(errorBoom, errDescribeSnapshot),
+		},
+		"CreateFailed": {
+			e: &external{client: &fake.MockClient{
+				MockDescribeSnapshots: func(ctx context.Context, _ *elasticache.DescribeSnapshotsInput, opts []func(*elasticache.Options)) (*elasticache.DescribeSnapshotsOutput, error) {
+					return nil, &types.SnapshotNotFoundFault{}
+				},
+				MockCreateSnapshot: func(ctx context.Context, in *elasticache.CreateSnapshotInput, opts []func(*elasticache.Options)) (*elasticache.CreateSnapshotOutput, error) {
+					return nil, errorBoom
+				},
+			}},
+			wantErr: awsclient.Wrap(errorBoom, errCreateSnapshot),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.e.Snapshot(ctx, replicationGroup(), snapshotName)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestInitialize(t *testing.T) {
 	type args struct {
 		cr   *v1beta1.ReplicationGroup
@@ -0,0 +1,322 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stack
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cf "github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/cloudformation"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+const (
+	errNotStack              = "managed resource is not a Stack custom resource"
+	errTemplate              = "exactly one of templateBody and templateConfigMapRef must be set"
+	errGetTemplateConfigMap  = "cannot get template ConfigMap"
+	errFmtTemplateKey        = "key %s is not found in template ConfigMap"
+	errDescribeStack         = "cannot describe Stack"
+	errGetTemplate           = "cannot get Stack template"
+	errDescribeEvents        = "cannot describe Stack events"
+	errDetectDrift           = "cannot detect Stack drift"
+	errCreate                = "cannot create Stack"
+	errDelete                = "cannot delete Stack"
+	errCreateChangeSet       = "cannot create Stack change set"
+	errDescribeChangeSet     = "cannot describe Stack change set"
+	errExecuteChangeSet      = "cannot execute Stack change set"
+	errDeleteChangeSet       = "cannot delete Stack change set"
+	errFmtChangeSetFailed    = "Stack change set %s failed: %s"
+	errRecordAppliedTemplate = "cannot record the applied Stack template"
+
+	// defaultTemplateKey is the key of the template in the template
+	// ConfigMap if none is given.
+	defaultTemplateKey = "template"
+
+	// driftDetectionInterval is how often the drift of a stack is detected.
+	driftDetectionInterval = time.Hour
+)
+
+// SetupStack adds a controller that reconciles Stack.
+func SetupStack(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
+	name := managed.ControllerName(v1alpha1.StackGroupKind)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(controller.Options{
+			RateLimiter: ratelimiter.NewController(rl),
+		}).
+		For(&v1alpha1.Stack{}).
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.StackGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
+			resource.ManagedKind(v1alpha1.StackGroupVersionKind),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: cloudformation.NewClient}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
+			managed.WithPollInterval(poll),
+			managed.WithLogger(l.WithValues("controller", name)),
+			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+}
+
+type connector struct {
+	kube        client.Client
+	newClientFn func(aws.Config) cloudformation.Client
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return nil, errors.New(errNotStack)
+	}
+	cfg, err := awsclient.GetConfig(ctx, c.kube, mg, cr.Spec.ForProvider.Region)
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: c.newClientFn(*cfg), kube: c.kube}, nil
+}
+
+type external struct {
+	client cloudformation.Client
+	kube   client.Client
+}
+
+// template returns the template of the supplied stack, either inline or from
+// its template ConfigMap.
+func (e *external) template(ctx context.Context, cr *v1alpha1.Stack) (string, error) {
+	p := cr.Spec.ForProvider
+	if (p.TemplateBody == nil) == (p.TemplateConfigMapRef == nil) {
+		return "", errors.New(errTemplate)
+	}
+	if p.TemplateBody != nil {
+		return *p.TemplateBody, nil
+	}
+	ref := p.TemplateConfigMapRef
+	cm := &corev1.ConfigMap{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return "", errors.Wrap(err, errGetTemplateConfigMap)
+	}
+	key := ref.Key
+	if key == "" {
+		key = defaultTemplateKey
+	}
+	t, ok := cm.Data[key]
+	if !ok {
+		return "", errors.Errorf(errFmtTemplateKey, key)
+	}
+	return t, nil
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) { // nolint:gocyclo
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotStack)
+	}
+
+	out, err := e.client.DescribeStacks(ctx, &cf.DescribeStacksInput{StackName: aws.String(meta.GetExternalName(cr))})
+	if err != nil {
+		return managed.ExternalObservation{}, awsclient.Wrap(resource.Ignore(cloudformation.IsErrorNotFound, err), errDescribeStack)
+	}
+	if len(out.Stacks) == 0 || out.Stacks[0].StackStatus == cftypes.StackStatusDeleteComplete {
+		return managed.ExternalObservation{}, nil
+	}
+	stack := out.Stacks[0]
+
+	// The template is not needed to delete a stack, and its ConfigMap may
+	// already be gone along with it.
+	deleted := meta.WasDeleted(cr)
+	var template, current string
+	if !deleted {
+		if template, err = e.template(ctx, cr); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		out, err := e.client.GetTemplate(ctx, &cf.GetTemplateInput{
+			StackName:     stack.StackId,
+			TemplateStage: cftypes.TemplateStageOriginal,
+		})
+		if err != nil {
+			return managed.ExternalObservation{}, awsclient.Wrap(err, errGetTemplate)
+		}
+		current = aws.ToString(out.TemplateBody)
+	}
+	events, err := e.client.DescribeStackEvents(ctx, &cf.DescribeStackEventsInput{StackName: stack.StackId})
+	if err != nil {
+		return managed.ExternalObservation{}, awsclient.Wrap(err, errDescribeEvents)
+	}
+	if !deleted {
+		if err := e.detectDrift(ctx, stack); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+
+	cr.Status.AtProvider = cloudformation.GenerateObservation(stack, events.StackEvents)
+
+	switch {
+	case stack.StackStatus == cftypes.StackStatusDeleteInProgress:
+		cr.SetConditions(xpv1.Deleting())
+	case cloudformation.IsInProgress(stack.StackStatus):
+		cr.SetConditions(xpv1.Creating())
+	case cloudformation.IsAvailable(stack.StackStatus):
+		cr.SetConditions(xpv1.Available())
+	default:
+		cr.SetConditions(xpv1.Unavailable())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists: true,
+		// A stack cannot be updated while an operation on it is in progress.
+		ResourceUpToDate: deleted || cloudformation.IsInProgress(stack.StackStatus) ||
+			cloudformation.IsUpToDate(cr.Spec.ForProvider, template, stack, current, cr.GetAnnotations()[cloudformation.AnnotationKeyAppliedTemplateHash]),
+		ConnectionDetails: cloudformation.GetConnectionDetails(stack),
+	}, nil
+}
+
+// detectDrift starts the drift detection of the supplied stack if it is not
+// in progress and its drift was not detected recently. The result is
+// reported by subsequent observations of the stack.
+func (e *external) detectDrift(ctx context.Context, stack cftypes.Stack) error {
+	if cloudformation.IsInProgress(stack.StackStatus) || !cloudformation.IsAvailable(stack.StackStatus) {
+		return nil
+	}
+	if d := stack.DriftInformation; d != nil && d.LastCheckTimestamp != nil && time.Since(*d.LastCheckTimestamp) < driftDetectionInterval {
+		return nil
+	}
+	_, err := e.client.DetectStackDrift(ctx, &cf.DetectStackDriftInput{StackName: stack.StackId})
+	return awsclient.Wrap(err, errDetectDrift)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotStack)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	template, err := e.template(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	_, err = e.client.CreateStack(ctx, cloudformation.GenerateCreateStackInput(meta.GetExternalName(cr), template, cr.Spec.ForProvider))
+	return managed.ExternalCreation{}, awsclient.Wrap(err, errCreate)
+}
+
+// Update updates the stack through a change set. The change set is created
+// by the first call and executed by a subsequent one once it is ready.
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotStack)
+	}
+
+	template, err := e.template(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	name := meta.GetExternalName(cr)
+	csName := cloudformation.ChangeSetName(template, cr.Spec.ForProvider)
+
+	cs, err := e.client.DescribeChangeSet(ctx, &cf.DescribeChangeSetInput{
+		StackName:     aws.String(name),
+		ChangeSetName: aws.String(csName),
+	})
+	if cloudformation.IsChangeSetNotFound(err) {
+		_, err := e.client.CreateChangeSet(ctx, cloudformation.GenerateCreateChangeSetInput(name, template, cr.Spec.ForProvider))
+		return managed.ExternalUpdate{}, awsclient.Wrap(err, errCreateChangeSet)
+	}
+	if err != nil {
+		return managed.ExternalUpdate{}, awsclient.Wrap(err, errDescribeChangeSet)
+	}
+
+	switch cs.Status { // nolint:exhaustive
+	case cftypes.ChangeSetStatusCreateComplete:
+		if cs.ExecutionStatus != cftypes.ExecutionStatusAvailable {
+			return managed.ExternalUpdate{}, nil
+		}
+		_, err := e.client.ExecuteChangeSet(ctx, &cf.ExecuteChangeSetInput{
+			StackName:     aws.String(name),
+			ChangeSetName: aws.String(csName),
+		})
+		return managed.ExternalUpdate{}, awsclient.Wrap(err, errExecuteChangeSet)
+	case cftypes.ChangeSetStatusFailed:
+		// The failed change set is deleted so that it is created again by
+		// the next update, e.g. once a missing capability is added.
+		if _, err := e.client.DeleteChangeSet(ctx, &cf.DeleteChangeSetInput{
+			StackName:     aws.String(name),
+			ChangeSetName: aws.String(csName),
+		}); err != nil {
+			return managed.ExternalUpdate{}, awsclient.Wrap(err, errDeleteChangeSet)
+		}
+		if cloudformation.IsNoChanges(aws.ToString(cs.StatusReason)) {
+			return managed.ExternalUpdate{}, e.recordAppliedTemplate(ctx, cr, template)
+		}
+		return managed.ExternalUpdate{}, errors.Errorf(errFmtChangeSetFailed, csName, aws.ToString(cs.StatusReason))
+	}
+	// The change set is still being created.
+	return managed.ExternalUpdate{}, nil
+}
+
+// recordAppliedTemplate records that the current template of the supplied
+// stack is equivalent to the supplied desired one, so that it is considered up
+// to date instead of being updated through another change set without
+// changes.
+func (e *external) recordAppliedTemplate(ctx context.Context, cr *v1alpha1.Stack, template string) error {
+	current, err := e.client.GetTemplate(ctx, &cf.GetTemplateInput{
+		StackName:     aws.String(meta.GetExternalName(cr)),
+		TemplateStage: cftypes.TemplateStageOriginal,
+	})
+	if err != nil {
+		return awsclient.Wrap(err, errGetTemplate)
+	}
+	meta.AddAnnotations(cr, map[string]string{
+		cloudformation.AnnotationKeyAppliedTemplateHash: cloudformation.AppliedTemplateHash(template, cr.Spec.ForProvider, aws.ToString(current.TemplateBody)),
+	})
+	return errors.Wrap(e.kube.Update(ctx, cr), errRecordAppliedTemplate)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Stack)
+	if !ok {
+		return errors.New(errNotStack)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	_, err := e.client.DeleteStack(ctx, &cf.DeleteStackInput{StackName: aws.String(meta.GetExternalName(cr))})
+	return awsclient.Wrap(resource.Ignore(cloudformation.IsErrorNotFound, err), errDelete)
+}
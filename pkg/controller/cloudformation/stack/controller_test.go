@@ -0,0 +1,593 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cf "github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-aws/apis/cloudformation/v1alpha1"
+	"github.com/crossplane/provider-aws/pkg/clients/cloudformation"
+	"github.com/crossplane/provider-aws/pkg/clients/cloudformation/fake"
+)
+
+var (
+	stackName = "some-stack"
+	stackID   = "arn:aws:cloudformation:us-east-1:123456789012:stack/some-stack/1"
+	template  = "Resources: {}"
+	checked   = time.Now()
+
+	errBoom     = errors.New("boom")
+	errNotFound = &smithy.GenericAPIError{Code: "ValidationError", Message: "Stack with id some-stack does not exist"}
+)
+
+type args struct {
+	kube client.Client
+	cf   cloudformation.Client
+	cr   *v1alpha1.Stack
+}
+
+type stackModifier func(*v1alpha1.Stack)
+
+func withExternalName(s string) stackModifier {
+	return func(r *v1alpha1.Stack) { meta.SetExternalName(r, s) }
+}
+
+func withConditions(c ...xpv1.Condition) stackModifier {
+	return func(r *v1alpha1.Stack) { r.Status.ConditionedStatus.Conditions = c }
+}
+
+func withSpec(p v1alpha1.StackParameters) stackModifier {
+	return func(r *v1alpha1.Stack) { r.Spec.ForProvider = p }
+}
+
+func withStatus(o v1alpha1.StackObservation) stackModifier {
+	return func(r *v1alpha1.Stack) { r.Status.AtProvider = o }
+}
+
+func withAnnotations(a map[string]string) stackModifier {
+	return func(r *v1alpha1.Stack) { meta.AddAnnotations(r, a) }
+}
+
+func withDeletionTimestamp(t metav1.Time) stackModifier {
+	return func(r *v1alpha1.Stack) { r.SetDeletionTimestamp(&t) }
+}
+
+func stack(m ...stackModifier) *v1alpha1.Stack {
+	cr := &v1alpha1.Stack{}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func awsStack(status cftypes.StackStatus) cftypes.Stack {
+	return cftypes.Stack{
+		StackId:     aws.String(stackID),
+		StackName:   aws.String(stackName),
+		StackStatus: status,
+		DriftInformation: &cftypes.StackDriftInformation{
+			StackDriftStatus:   cftypes.StackDriftStatusInSync,
+			LastCheckTimestamp: &checked,
+		},
+		Outputs: []cftypes.Output{{OutputKey: aws.String("BucketName"), OutputValue: aws.String("bucket")}},
+	}
+}
+
+func observation(status cftypes.StackStatus) v1alpha1.StackObservation {
+	t := metav1.NewTime(checked)
+	return v1alpha1.StackObservation{
+		StackID:            stackID,
+		StackStatus:        string(status),
+		DriftStatus:        string(cftypes.StackDriftStatusInSync),
+		LastDriftCheckTime: &t,
+		Outputs:            map[string]string{"BucketName": "bucket"},
+	}
+}
+
+func describe(s cftypes.Stack, current string) *fake.MockCloudFormationClient {
+	return &fake.MockCloudFormationClient{
+		MockDescribeStacks: func(_ context.Context, _ *cf.DescribeStacksInput, _ []func(*cf.Options)) (*cf.DescribeStacksOutput, error) {
+			return &cf.DescribeStacksOutput{Stacks: []cftypes.Stack{s}}, nil
+		},
+		MockGetTemplate: func(_ context.Context, _ *cf.GetTemplateInput, _ []func(*cf.Options)) (*cf.GetTemplateOutput, error) {
+			return &cf.GetTemplateOutput{TemplateBody: aws.String(current)}, nil
+		},
+		MockDescribeStackEvents: func(_ context.Context, _ *cf.DescribeStackEventsInput, _ []func(*cf.Options)) (*cf.DescribeStackEventsOutput, error) {
+			return &cf.DescribeStackEventsOutput{}, nil
+		},
+	}
+}
+
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connector{}
+
+func TestObserve(t *testing.T) {
+	connection := managed.ConnectionDetails{"BucketName": []byte("bucket")}
+	deleted := metav1.Now()
+	jsonTemplate := `{"Resources": {}, "Outputs": {}}`
+	reformatted := "Resources:\n  {}\n"
+	configMapRef := &v1alpha1.TemplateConfigMapReference{Name: "template", Namespace: "default"}
+	applied := map[string]string{
+		cloudformation.AnnotationKeyAppliedTemplateHash: cloudformation.AppliedTemplateHash(template, v1alpha1.StackParameters{TemplateBody: &template}, reformatted),
+	}
+
+	type want struct {
+		cr     *v1alpha1.Stack
+		result managed.ExternalObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Available": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), template),
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusCreateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"TemplateChanged": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusUpdateComplete), "Resources: {Old: {}}"),
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusUpdateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"InProgress": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusUpdateInProgress), "Resources: {Old: {}}"),
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Creating()),
+					withStatus(observation(cftypes.StackStatusUpdateInProgress))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"ConfigMapTemplate": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						obj.(*corev1.ConfigMap).Data = map[string]string{"template": template}
+						return nil
+					},
+				},
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), template),
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{
+					TemplateConfigMapRef: &v1alpha1.TemplateConfigMapReference{Name: "template", Namespace: "default"},
+				})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{
+						TemplateConfigMapRef: &v1alpha1.TemplateConfigMapReference{Name: "template", Namespace: "default"},
+					}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusCreateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"JSONTemplateReformatted": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), "{\n  \"Outputs\": {},\n  \"Resources\": {}\n}"),
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &jsonTemplate})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &jsonTemplate}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusCreateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"AppliedTemplate": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), reformatted),
+				cr: stack(withExternalName(stackName), withAnnotations(applied), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withAnnotations(applied),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusCreateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"AppliedTemplateChanged": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), "Resources: {Old: {}}"),
+				cr: stack(withExternalName(stackName), withAnnotations(applied), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withAnnotations(applied),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Available()),
+					withStatus(observation(cftypes.StackStatusCreateComplete))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"DeletedWithoutTemplate": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				cf: describe(awsStack(cftypes.StackStatusDeleteInProgress), template),
+				cr: stack(withExternalName(stackName), withDeletionTimestamp(deleted), withSpec(v1alpha1.StackParameters{TemplateConfigMapRef: configMapRef})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withDeletionTimestamp(deleted),
+					withSpec(v1alpha1.StackParameters{TemplateConfigMapRef: configMapRef}),
+					withConditions(xpv1.Deleting()),
+					withStatus(observation(cftypes.StackStatusDeleteInProgress))),
+				result: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connection,
+				},
+			},
+		},
+		"NoTemplate": {
+			args: args{
+				cf: describe(awsStack(cftypes.StackStatusCreateComplete), template),
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr:  stack(withExternalName(stackName)),
+				err: errors.New(errTemplate),
+			},
+		},
+		"NotFound": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockDescribeStacks: func(_ context.Context, _ *cf.DescribeStacksInput, _ []func(*cf.Options)) (*cf.DescribeStacksOutput, error) {
+						return nil, errNotFound
+					},
+				},
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName)),
+			},
+		},
+		"DescribeFailed": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockDescribeStacks: func(_ context.Context, _ *cf.DescribeStacksInput, _ []func(*cf.Options)) (*cf.DescribeStacksOutput, error) {
+						return nil, errBoom
+					},
+				},
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr:  stack(withExternalName(stackName)),
+				err: errors.Wrap(errBoom, errDescribeStack),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.cf}
+			o, err := e.Observe(context.Background(), tc.args.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, o); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		cr  *v1alpha1.Stack
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Successful": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockCreateStack: func(_ context.Context, input *cf.CreateStackInput, _ []func(*cf.Options)) (*cf.CreateStackOutput, error) {
+						if aws.ToString(input.StackName) != stackName || aws.ToString(input.TemplateBody) != template {
+							return nil, errBoom
+						}
+						return &cf.CreateStackOutput{StackId: aws.String(stackID)}, nil
+					},
+				},
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Creating())),
+			},
+		},
+		"Failed": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockCreateStack: func(_ context.Context, _ *cf.CreateStackInput, _ []func(*cf.Options)) (*cf.CreateStackOutput, error) {
+						return nil, errBoom
+					},
+				},
+				cr: stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName),
+					withSpec(v1alpha1.StackParameters{TemplateBody: &template}),
+					withConditions(xpv1.Creating())),
+				err: errors.Wrap(errBoom, errCreate),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.cf}
+			_, err := e.Create(context.Background(), tc.args.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	csName := cloudformation.ChangeSetName(template, v1alpha1.StackParameters{TemplateBody: &template})
+	errChangeSetNotFound := &cftypes.ChangeSetNotFoundException{}
+	current := "Resources:\n  {}\n"
+
+	describeChangeSet := func(status cftypes.ChangeSetStatus, reason string) func(context.Context, *cf.DescribeChangeSetInput, []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error) {
+		return func(_ context.Context, _ *cf.DescribeChangeSetInput, _ []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error) {
+			return &cf.DescribeChangeSetOutput{
+				Status:          status,
+				StatusReason:    aws.String(reason),
+				ExecutionStatus: cftypes.ExecutionStatusAvailable,
+			}, nil
+		}
+	}
+
+	type want struct {
+		calls []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		args
+		describe func(context.Context, *cf.DescribeChangeSetInput, []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error)
+		want
+	}{
+		"CreateChangeSet": {
+			describe: func(_ context.Context, _ *cf.DescribeChangeSetInput, _ []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error) {
+				return nil, errChangeSetNotFound
+			},
+			want: want{
+				calls: []string{"create " + csName},
+			},
+		},
+		"Pending": {
+			describe: describeChangeSet(cftypes.ChangeSetStatusCreateInProgress, ""),
+		},
+		"ExecuteChangeSet": {
+			describe: describeChangeSet(cftypes.ChangeSetStatusCreateComplete, ""),
+			want: want{
+				calls: []string{"execute " + csName},
+			},
+		},
+		"NoChanges": {
+			describe: describeChangeSet(cftypes.ChangeSetStatusFailed, "The submitted information didn't contain changes."),
+			want: want{
+				calls: []string{"delete " + csName, "record " + cloudformation.AppliedTemplateHash(template, v1alpha1.StackParameters{TemplateBody: &template}, current)},
+			},
+		},
+		"Failed": {
+			describe: describeChangeSet(cftypes.ChangeSetStatusFailed, "Requires capabilities : [CAPABILITY_IAM]"),
+			want: want{
+				calls: []string{"delete " + csName},
+				err:   errors.Errorf(errFmtChangeSetFailed, csName, "Requires capabilities : [CAPABILITY_IAM]"),
+			},
+		},
+		"DescribeFailed": {
+			describe: func(_ context.Context, _ *cf.DescribeChangeSetInput, _ []func(*cf.Options)) (*cf.DescribeChangeSetOutput, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDescribeChangeSet),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			cfc := &fake.MockCloudFormationClient{
+				MockDescribeChangeSet: tc.describe,
+				MockCreateChangeSet: func(_ context.Context, input *cf.CreateChangeSetInput, _ []func(*cf.Options)) (*cf.CreateChangeSetOutput, error) {
+					calls = append(calls, "create "+aws.ToString(input.ChangeSetName))
+					return &cf.CreateChangeSetOutput{}, nil
+				},
+				MockExecuteChangeSet: func(_ context.Context, input *cf.ExecuteChangeSetInput, _ []func(*cf.Options)) (*cf.ExecuteChangeSetOutput, error) {
+					calls = append(calls, "execute "+aws.ToString(input.ChangeSetName))
+					return &cf.ExecuteChangeSetOutput{}, nil
+				},
+				MockDeleteChangeSet: func(_ context.Context, input *cf.DeleteChangeSetInput, _ []func(*cf.Options)) (*cf.DeleteChangeSetOutput, error) {
+					calls = append(calls, "delete "+aws.ToString(input.ChangeSetName))
+					return &cf.DeleteChangeSetOutput{}, nil
+				},
+				MockGetTemplate: func(_ context.Context, _ *cf.GetTemplateInput, _ []func(*cf.Options)) (*cf.GetTemplateOutput, error) {
+					return &cf.GetTemplateOutput{TemplateBody: aws.String(current)}, nil
+				},
+			}
+			kube := &test.MockClient{
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					calls = append(calls, "record "+obj.GetAnnotations()[cloudformation.AnnotationKeyAppliedTemplateHash])
+					return nil
+				},
+			}
+			e := &external{kube: kube, client: cfc}
+			_, err := e.Update(context.Background(), stack(withExternalName(stackName), withSpec(v1alpha1.StackParameters{TemplateBody: &template})))
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		cr  *v1alpha1.Stack
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Successful": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockDeleteStack: func(_ context.Context, _ *cf.DeleteStackInput, _ []func(*cf.Options)) (*cf.DeleteStackOutput, error) {
+						return &cf.DeleteStackOutput{}, nil
+					},
+				},
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName), withConditions(xpv1.Deleting())),
+			},
+		},
+		"NotFound": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockDeleteStack: func(_ context.Context, _ *cf.DeleteStackInput, _ []func(*cf.Options)) (*cf.DeleteStackOutput, error) {
+						return nil, errNotFound
+					},
+				},
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr: stack(withExternalName(stackName), withConditions(xpv1.Deleting())),
+			},
+		},
+		"Failed": {
+			args: args{
+				cf: &fake.MockCloudFormationClient{
+					MockDeleteStack: func(_ context.Context, _ *cf.DeleteStackInput, _ []func(*cf.Options)) (*cf.DeleteStackOutput, error) {
+						return nil, errBoom
+					},
+				},
+				cr: stack(withExternalName(stackName)),
+			},
+			want: want{
+				cr:  stack(withExternalName(stackName), withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errBoom, errDelete),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.cf}
+			err := e.Delete(context.Background(), tc.args.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
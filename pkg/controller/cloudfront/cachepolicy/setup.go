@@ -31,10 +31,12 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/cloudfront/v1alpha1"
 	awsclients "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/controller/cloudfront"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupCachePolicy adds a controller that reconciles CachePolicy.
@@ -46,9 +48,11 @@ func SetupCachePolicy(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimit
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.CachePolicy{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.CachePolicyGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.CachePolicyGroupVersionKind),
-			managed.WithExternalConnecter(&connector{
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{
 				kube: mgr.GetClient(),
 				opts: []option{
 					func(e *external) {
@@ -61,7 +65,7 @@ func SetupCachePolicy(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimit
 						e.preDelete = preDelete
 					},
 				},
-			}),
+			}, lifecycle.Options(mgr, name))),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// Reasons of the Ready condition of a ProviderConfig.
+const (
+	ReasonCredentialsVerified xpv1.ConditionReason = "CredentialsVerified"
+	ReasonCredentialsError    xpv1.ConditionReason = "CredentialsError"
+)
+
+const (
+	errGetProviderConfig  = "cannot get ProviderConfig"
+	errGetConfig          = "cannot get AWS config"
+	errGetCallerIdentity  = "cannot get caller identity"
+	errUpdateStatus       = "cannot update ProviderConfig status"
+	reasonCredentialsFail = event.Reason("CannotVerifyCredentials")
+
+	// verifyInterval is how often the credentials of a ProviderConfig are
+	// verified.
+	verifyInterval = 10 * time.Minute
+
+	// minRetryInterval is how long after a failed verification the
+	// credentials of a ProviderConfig are verified again. It doubles with
+	// every consecutive failure, up to the verify interval.
+	minRetryInterval = 30 * time.Second
+
+	// maxRetryShift bounds the doubling of the retry interval so that it
+	// cannot overflow.
+	maxRetryShift = 16
+
+	// stsRegion is the region used to verify credentials of
+	// ProviderConfigs without a default region.
+	stsRegion = "us-east-1"
+)
+
+// A CallerIdentityFn returns the identity of the credentials of the supplied
+// ProviderConfig.
+type CallerIdentityFn func(ctx context.Context, kube client.Client, pc *v1beta1.ProviderConfig) (*sts.GetCallerIdentityOutput, error)
+
+// GetCallerIdentity calls STS GetCallerIdentity with the credentials of the
+// supplied ProviderConfig.
+func GetCallerIdentity(ctx context.Context, kube client.Client, pc *v1beta1.ProviderConfig) (*sts.GetCallerIdentityOutput, error) {
+	region := pc.Spec.Region
+	if region == "" {
+		region = stsRegion
+	}
+	cfg, err := awsclient.GetConfigForProviderConfig(ctx, kube, pc, region)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetConfig)
+	}
+	out, err := sts.NewFromConfig(*cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	return out, awsclient.Wrap(err, errGetCallerIdentity)
+}
+
+// CredentialsVerified returns a condition that indicates the credentials of
+// a ProviderConfig were verified.
+func CredentialsVerified() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCredentialsVerified,
+	}
+}
+
+// CredentialsError returns a condition that indicates the credentials of a
+// ProviderConfig could not be verified.
+func CredentialsError(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCredentialsError,
+		Message:            awsclient.CleanError(err).Error(),
+	}
+}
+
+// A CredentialsReconciler verifies the credentials of ProviderConfigs after
+// they are reconciled by the Reconciler it wraps. The identity of the
+// credentials is reported in the status of the ProviderConfig, together with
+// a Ready condition.
+type CredentialsReconciler struct {
+	reconcile.Reconciler
+
+	kube     client.Client
+	identity CallerIdentityFn
+	interval time.Duration
+	log      logging.Logger
+	record   event.Recorder
+
+	mu       sync.Mutex
+	failures map[types.UID]failure
+}
+
+// A failure records the consecutive failed verifications of the credentials
+// of a ProviderConfig at a generation of its spec.
+type failure struct {
+	generation int64
+	count      int
+	last       time.Time
+}
+
+// A CredentialsReconcilerOption configures a CredentialsReconciler.
+type CredentialsReconcilerOption func(*CredentialsReconciler)
+
+// WithCallerIdentity configures how the identity of credentials is
+// retrieved.
+func WithCallerIdentity(fn CallerIdentityFn) CredentialsReconcilerOption {
+	return func(r *CredentialsReconciler) {
+		r.identity = fn
+	}
+}
+
+// WithLogger configures the logger of the CredentialsReconciler.
+func WithLogger(l logging.Logger) CredentialsReconcilerOption {
+	return func(r *CredentialsReconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder configures the event recorder of the CredentialsReconciler.
+func WithRecorder(er event.Recorder) CredentialsReconcilerOption {
+	return func(r *CredentialsReconciler) {
+		r.record = er
+	}
+}
+
+// NewCredentialsReconciler returns a CredentialsReconciler that wraps the
+// supplied Reconciler.
+func NewCredentialsReconciler(kube client.Client, wrapped reconcile.Reconciler, o ...CredentialsReconcilerOption) *CredentialsReconciler {
+	r := &CredentialsReconciler{
+		Reconciler: wrapped,
+		kube:       kube,
+		identity:   GetCallerIdentity,
+		interval:   verifyInterval,
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+		failures:   map[types.UID]failure{},
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Reconcile the supplied ProviderConfig with the wrapped Reconciler, then
+// verify its credentials unless they were verified recently.
+func (r *CredentialsReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.Reconciler.Reconcile(ctx, req)
+	if err != nil || res.Requeue || res.RequeueAfter > 0 {
+		return res, err
+	}
+
+	log := r.log.WithValues("request", req)
+	pc := &v1beta1.ProviderConfig{}
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		log.Debug(errGetProviderConfig, "error", err)
+		if kerrors.IsNotFound(err) {
+			awsclient.ForgetProviderConfig(req.Name)
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if meta.WasDeleted(pc) {
+		// The AWS configs built from a deleted ProviderConfig are never
+		// used again.
+		r.resetFailures(pc)
+		awsclient.ForgetProviderConfig(pc.GetName())
+		return reconcile.Result{}, nil
+	}
+
+	// Failed verifications are retried with a backoff, or at once if the
+	// spec of the ProviderConfig changed, e.g. because its credentials were
+	// fixed.
+	if wait := r.untilNextVerify(pc); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	id, err := r.identity(ctx, r.kube, pc)
+	if err != nil {
+		log.Debug(errGetCallerIdentity, "error", err)
+		if pc.Status.GetCondition(xpv1.TypeReady).Reason != ReasonCredentialsError {
+			r.record.Event(pc, event.Warning(reasonCredentialsFail, err))
+		}
+		pc.Status.SetConditions(CredentialsError(err))
+		wait := r.retryInterval(r.recordFailure(pc))
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.kube.Status().Update(ctx, pc), errUpdateStatus)
+	}
+	r.resetFailures(pc)
+	t := metav1.Now()
+	pc.Status.AccountID = aws.ToString(id.Account)
+	pc.Status.CallerARN = aws.ToString(id.Arn)
+	pc.Status.LastVerifiedTime = &t
+	pc.Status.SetConditions(CredentialsVerified())
+	return reconcile.Result{RequeueAfter: r.interval}, errors.Wrap(r.kube.Status().Update(ctx, pc), errUpdateStatus)
+}
+
+// untilNextVerify returns how long until the credentials of the supplied
+// ProviderConfig should be verified again.
+func (r *CredentialsReconciler) untilNextVerify(pc *v1beta1.ProviderConfig) time.Duration {
+	r.mu.Lock()
+	f, failed := r.failures[pc.GetUID()]
+	r.mu.Unlock()
+	if failed && f.generation == pc.GetGeneration() {
+		return r.retryInterval(f) - time.Since(f.last)
+	}
+	if failed || pc.Status.LastVerifiedTime == nil || pc.Status.GetCondition(xpv1.TypeReady).Reason != ReasonCredentialsVerified {
+		return 0
+	}
+	return r.interval - time.Since(pc.Status.LastVerifiedTime.Time)
+}
+
+// retryInterval returns how long after the supplied failure the credentials
+// should be verified again.
+func (r *CredentialsReconciler) retryInterval(f failure) time.Duration {
+	if f.count > maxRetryShift {
+		return r.interval
+	}
+	d := minRetryInterval << (f.count - 1)
+	if d > r.interval {
+		return r.interval
+	}
+	return d
+}
+
+// recordFailure records a failed verification of the credentials of the
+// supplied ProviderConfig and returns it.
+func (r *CredentialsReconciler) recordFailure(pc *v1beta1.ProviderConfig) failure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.failures[pc.GetUID()]
+	if f.generation != pc.GetGeneration() {
+		f = failure{generation: pc.GetGeneration()}
+	}
+	f.count++
+	f.last = time.Now()
+	r.failures[pc.GetUID()] = f
+	return f
+}
+
+// resetFailures forgets the failed verifications of the credentials of the
+// supplied ProviderConfig.
+func (r *CredentialsReconciler) resetFailures(pc *v1beta1.ProviderConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, pc.GetUID())
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+var (
+	errBoom   = errors.New("boom")
+	accountID = "123456789012"
+	callerARN = "arn:aws:iam::123456789012:user/crossplane"
+)
+
+type reconcilerFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcilerFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func reconciled(res reconcile.Result, err error) reconcile.Reconciler {
+	return reconcilerFn(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		return res, err
+	})
+}
+
+func identity(err error) CallerIdentityFn {
+	return func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig) (*sts.GetCallerIdentityOutput, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &sts.GetCallerIdentityOutput{Account: aws.String(accountID), Arn: aws.String(callerARN)}, nil
+	}
+}
+
+func providerConfig(s v1beta1.ProviderConfigStatus) *v1beta1.ProviderConfig {
+	return &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Status:     s,
+	}
+}
+
+func TestCredentialsReconcile(t *testing.T) {
+	now := metav1.Now()
+	recently := metav1.NewTime(time.Now().Add(-time.Minute))
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	verified := func(at *metav1.Time) v1beta1.ProviderConfigStatus {
+		s := v1beta1.ProviderConfigStatus{AccountID: accountID, CallerARN: callerARN, LastVerifiedTime: at}
+		s.SetConditions(CredentialsVerified())
+		return s
+	}
+	failed := func(at *metav1.Time) v1beta1.ProviderConfigStatus {
+		s := v1beta1.ProviderConfigStatus{AccountID: accountID, CallerARN: callerARN, LastVerifiedTime: at}
+		s.SetConditions(CredentialsError(errBoom))
+		return s
+	}
+
+	type args struct {
+		wrapped  reconcile.Reconciler
+		kube     client.Client
+		identity CallerIdentityFn
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+		status *v1beta1.ProviderConfigStatus
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"WrappedError": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{RequeueAfter: time.Second}, errBoom),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Second},
+				err:    errBoom,
+			},
+		},
+		"NotFound": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "default")),
+				},
+			},
+			want: want{},
+		},
+		"GetError": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetProviderConfig),
+			},
+		},
+		"RecentlyVerified": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*v1beta1.ProviderConfig) = *providerConfig(verified(&recently))
+						return nil
+					}),
+				},
+				identity: identity(errBoom),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: verifyInterval - time.Minute},
+			},
+		},
+		"Verified": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*v1beta1.ProviderConfig) = *providerConfig(verified(&longAgo))
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				identity: identity(nil),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: verifyInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := verified(&now)
+					return &s
+				}(),
+			},
+		},
+		"CredentialsError": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						*o.(*v1beta1.ProviderConfig) = *providerConfig(verified(&recently))
+						o.(*v1beta1.ProviderConfig).Status.SetConditions(CredentialsError(errBoom))
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				identity: identity(errBoom),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: minRetryInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := failed(&recently)
+					return &s
+				}(),
+			},
+		},
+		"UpdateStatusError": {
+			args: args{
+				wrapped: reconciled(reconcile.Result{}, nil),
+				kube: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				identity: identity(nil),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: verifyInterval},
+				err:    errors.Wrap(errBoom, errUpdateStatus),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var status *v1beta1.ProviderConfigStatus
+			if mc, ok := tc.args.kube.(*test.MockClient); ok && mc.MockStatusUpdate != nil {
+				update := mc.MockStatusUpdate
+				mc.MockStatusUpdate = func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+					s := obj.(*v1beta1.ProviderConfig).Status
+					status = &s
+					return update(ctx, obj, opts...)
+				}
+			}
+
+			r := NewCredentialsReconciler(tc.args.kube, tc.args.wrapped, WithCallerIdentity(tc.args.identity))
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got, equateApprox()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+			if tc.want.status == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.status, status, test.EquateConditions(), equateApprox()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCredentialsRetry(t *testing.T) {
+	pc := providerConfig(v1beta1.ProviderConfigStatus{})
+	pc.SetUID("uid")
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+			pc.DeepCopyInto(o.(*v1beta1.ProviderConfig))
+			return nil
+		}),
+		MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+	}
+	calls := 0
+	fail := func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig) (*sts.GetCallerIdentityOutput, error) {
+		calls++
+		return nil, errBoom
+	}
+	r := NewCredentialsReconciler(kube, reconciled(reconcile.Result{}, nil), WithCallerIdentity(fail))
+
+	steps := []struct {
+		name   string
+		before func()
+		calls  int
+		result reconcile.Result
+	}{
+		{
+			name:   "FirstFailure",
+			calls:  1,
+			result: reconcile.Result{RequeueAfter: minRetryInterval},
+		},
+		{
+			name:   "ReconciledAgainAtOnce",
+			calls:  1,
+			result: reconcile.Result{RequeueAfter: minRetryInterval},
+		},
+		{
+			name: "RetryIntervalElapsed",
+			before: func() {
+				f := r.failures[pc.GetUID()]
+				f.last = f.last.Add(-minRetryInterval)
+				r.failures[pc.GetUID()] = f
+			},
+			calls:  2,
+			result: reconcile.Result{RequeueAfter: 2 * minRetryInterval},
+		},
+		{
+			name:   "SpecChanged",
+			before: func() { pc.SetGeneration(pc.GetGeneration() + 1) },
+			calls:  3,
+			result: reconcile.Result{RequeueAfter: minRetryInterval},
+		},
+	}
+
+	for _, s := range steps {
+		if s.before != nil {
+			s.before()
+		}
+		got, err := r.Reconcile(context.Background(), reconcile.Request{})
+		if err != nil {
+			t.Fatalf("%s: Reconcile(...): %s", s.name, err)
+		}
+		if diff := cmp.Diff(s.calls, calls); diff != "" {
+			t.Errorf("%s: calls: -want, +got:\n%s", s.name, diff)
+		}
+		if diff := cmp.Diff(s.result, got, equateApprox()); diff != "" {
+			t.Errorf("%s: r: -want, +got:\n%s", s.name, diff)
+		}
+	}
+}
+
+// equateApprox equates durations and times that differ by less than a second,
+// since the reconciler computes them relative to the current time.
+func equateApprox() cmp.Option {
+	return cmp.Options{
+		cmp.Comparer(func(a, b time.Duration) bool {
+			d := a - b
+			return d < time.Second && d > -time.Second
+		}),
+		cmp.Comparer(func(a, b *metav1.Time) bool {
+			if a == nil || b == nil {
+				return a == b
+			}
+			d := a.Sub(b.Time)
+			return d < time.Second && d > -time.Second
+		}),
+	}
+}
@@ -30,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -42,7 +43,9 @@ import (
 
 	"github.com/crossplane/provider-aws/apis/database/v1beta1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/rds"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -58,6 +61,9 @@ const (
 	errGetPasswordSecretFailed = "cannot get password secret"
 )
 
+// maxNameLength is the maximum length of the name of a DB instance identifier.
+const maxNameLength = 63
+
 // SetupRDSInstance adds a controller that reconciles RDSInstances.
 func SetupRDSInstance(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
 	name := managed.ControllerName(v1beta1.RDSInstanceGroupKind)
@@ -68,9 +74,12 @@ func SetupRDSInstance(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimit
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1beta1.RDSInstance{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1beta1.RDSInstanceGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.RDSInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: rds.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: rds.NewClient}, lifecycle.Options(mgr, name), lifecycle.WithReplacementName(lifecycle.SuffixedName(maxNameLength)))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), managed.NewNameAsExternalName(mgr.GetClient()), &tagger{kube: mgr.GetClient()}),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(poll),
@@ -119,7 +128,9 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// be only 1 element in the list.
 	instance := rsp.DBInstances[0]
 	current := cr.Spec.ForProvider.DeepCopy()
-	rds.LateInitialize(&cr.Spec.ForProvider, &instance)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		rds.LateInitialize(p.(*v1beta1.RDSInstanceParameters), &instance)
+	})
 	if !reflect.DeepEqual(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(e.kube.Update(ctx, cr), errKubeUpdateFailed)
@@ -146,6 +157,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		ResourceExists:    true,
 		ResourceUpToDate:  upToDate,
 		ConnectionDetails: rds.GetConnectionDetails(*cr),
+		Diff:              diff,
 	}, nil
 }
 
@@ -266,6 +278,21 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	return awsclient.Wrap(resource.Ignore(rds.IsErrorNotFound, err), errDeleteFailed)
 }
 
+// DisableDeletionProtection disables the deletion protection of the supplied
+// RDSInstance, and nothing else, so that it can be deleted.
+func (e *external) DisableDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1beta1.RDSInstance)
+	if !ok {
+		return errors.New(errNotRDSInstance)
+	}
+	_, err := e.client.ModifyDBInstance(ctx, &awsrds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(meta.GetExternalName(cr)),
+		DeletionProtection:   aws.Bool(false),
+		ApplyImmediately:     true,
+	})
+	return awsclient.Wrap(err, errModifyFailed)
+}
+
 type tagger struct {
 	kube client.Client
 }
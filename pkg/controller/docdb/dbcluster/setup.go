@@ -27,6 +27,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	svcsdk "github.com/aws/aws-sdk-go/service/docdb"
 	"github.com/aws/aws-sdk-go/service/docdb/docdbiface"
@@ -42,7 +43,9 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/docdb/v1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	svcutils "github.com/crossplane/provider-aws/pkg/controller/docdb"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -59,12 +62,15 @@ func SetupDBCluster(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&svcapitypes.DBCluster{}).
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.DBClusterGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
 		WithOptions(controller.Options{
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.DBClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithInitializers(managed.NewNameAsExternalName(mgr.GetClient()), &tagger{kube: mgr.GetClient()}),
 			managed.WithPollInterval(poll),
@@ -220,8 +226,7 @@ func (e *hooks) postCreate(ctx context.Context, cr *svcapitypes.DBCluster, resp
 
 func preDelete(_ context.Context, cr *svcapitypes.DBCluster, obj *svcsdk.DeleteDBClusterInput) (bool, error) {
 	obj.DBClusterIdentifier = awsclient.String(meta.GetExternalName(cr))
-	obj.FinalDBSnapshotIdentifier = cr.Spec.ForProvider.FinalDBSnapshotIdentifier
-	obj.SkipFinalSnapshot = cr.Spec.ForProvider.SkipFinalSnapshot
+	obj.SkipFinalSnapshot, obj.FinalDBSnapshotIdentifier = lifecycle.FinalSnapshotParameters(cr, cr.Spec.ForProvider.SkipFinalSnapshot, cr.Spec.ForProvider.FinalDBSnapshotIdentifier)
 	return false, nil
 }
 
@@ -331,3 +336,18 @@ func (t *tagger) Initialize(ctx context.Context, mg resource.Managed) error {
 	cr.Spec.ForProvider.Tags = svcutils.AddExternalTags(mg, cr.Spec.ForProvider.Tags)
 	return errors.Wrap(t.kube.Update(ctx, cr), errKubeUpdateFailed)
 }
+
+// DisableDeletionProtection disables the deletion protection of the supplied
+// DBCluster, and nothing else, so that it can be deleted.
+func (e *external) DisableDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*svcapitypes.DBCluster)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	_, err := e.client.ModifyDBClusterWithContext(ctx, &svcsdk.ModifyDBClusterInput{
+		DBClusterIdentifier: awsclient.String(meta.GetExternalName(cr)),
+		DeletionProtection:  awsclient.Bool(false, awsclient.FieldRequired),
+		ApplyImmediately:    awsclient.Bool(true),
+	})
+	return awsclient.Wrap(err, errUpdate)
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -38,6 +41,7 @@ import (
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/docdb/fake"
 	svcutils "github.com/crossplane/provider-aws/pkg/controller/docdb"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -89,6 +93,10 @@ const (
 	testErrGetSecret                = "testErrGetSecret"
 )
 
+// ignoreContext ignores the contexts of the recorded calls, which cmp cannot
+// compare because of their unexported fields.
+var ignoreContext = cmpopts.IgnoreInterfaces(struct{ context.Context }{})
+
 type args struct {
 	docdb *fake.MockDocDBClient
 	kube  client.Client
@@ -304,6 +312,18 @@ func withFinalDBSnapshotIdentifier(value string) docDBModifier {
 	}
 }
 
+func withDeletionTimestamp(value metav1.Time) docDBModifier {
+	return func(o *svcapitypes.DBCluster) {
+		o.SetDeletionTimestamp(&value)
+	}
+}
+
+func withAnnotations(value map[string]string) docDBModifier {
+	return func(o *svcapitypes.DBCluster) {
+		meta.AddAnnotations(o, value)
+	}
+}
+
 func mergeTags(lists ...[]*svcapitypes.Tag) []*svcapitypes.Tag {
 	res := []*svcapitypes.Tag{}
 	for _, list := range lists {
@@ -1950,7 +1970,7 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.result, o); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called); diff != "" {
+			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called, ignoreContext); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -2205,7 +2225,7 @@ func TestCreate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.result, o); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called); diff != "" {
+			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called, ignoreContext); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -2213,6 +2233,8 @@ func TestCreate(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
+	testDeletionTimestamp := metav1.NewTime(time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC))
+
 	type want struct {
 		cr    *svcapitypes.DBCluster
 		err   error
@@ -2269,12 +2291,14 @@ func TestDelete(t *testing.T) {
 				cr: instance(
 					withDBClusterIdentifier(testDBClusterIdentifier),
 					withExternalName(testDBClusterIdentifier),
+					withAnnotations(map[string]string{lifecycle.AnnotationKeySnapshotBeforeDelete: "false"}),
 				),
 			},
 			want: want{
 				cr: instance(
 					withDBClusterIdentifier(testDBClusterIdentifier),
 					withExternalName(testDBClusterIdentifier),
+					withAnnotations(map[string]string{lifecycle.AnnotationKeySnapshotBeforeDelete: "false"}),
 					withConditions(xpv1.Deleting()),
 				),
 				err: errors.Wrap(errors.New(testErrDeleteDBClusterFailed), errDelete),
@@ -2284,6 +2308,41 @@ func TestDelete(t *testing.T) {
 							Ctx: context.Background(),
 							I: &docdb.DeleteDBClusterInput{
 								DBClusterIdentifier: awsclient.String(testDBClusterIdentifier),
+								SkipFinalSnapshot:   awsclient.Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		"FinalSnapshot": {
+			args: args{
+				docdb: &fake.MockDocDBClient{
+					MockDeleteDBClusterWithContext: func(c context.Context, ddpgi *docdb.DeleteDBClusterInput, o []request.Option) (*docdb.DeleteDBClusterOutput, error) {
+						return &docdb.DeleteDBClusterOutput{}, nil
+					},
+				},
+				cr: instance(
+					withDBClusterIdentifier(testDBClusterIdentifier),
+					withExternalName(testDBClusterIdentifier),
+					withDeletionTimestamp(testDeletionTimestamp),
+				),
+			},
+			want: want{
+				cr: instance(
+					withDBClusterIdentifier(testDBClusterIdentifier),
+					withExternalName(testDBClusterIdentifier),
+					withDeletionTimestamp(testDeletionTimestamp),
+					withConditions(xpv1.Deleting()),
+				),
+				docdb: fake.MockDocDBClientCall{
+					DeleteDBClusterWithContext: []*fake.CallDeleteDBClusterWithContext{
+						{
+							Ctx: context.Background(),
+							I: &docdb.DeleteDBClusterInput{
+								DBClusterIdentifier:       awsclient.String(testDBClusterIdentifier),
+								FinalDBSnapshotIdentifier: awsclient.String(testDBClusterIdentifier + "-final-20211018-153045"),
+								SkipFinalSnapshot:         awsclient.Bool(false, awsclient.FieldRequired),
 							},
 						},
 					},
@@ -2304,7 +2363,7 @@ func TestDelete(t *testing.T) {
 			if diff := cmp.Diff(tc.want.cr, tc.args.cr, test.EquateConditions()); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called); diff != "" {
+			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called, ignoreContext); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -2704,7 +2763,7 @@ func TestUpdate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.result, o); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
-			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called); diff != "" {
+			if diff := cmp.Diff(tc.want.docdb, tc.args.docdb.Called, ignoreContext); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -26,6 +26,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/aws/aws-sdk-go/service/docdb/docdbiface"
 
@@ -42,7 +43,9 @@ import (
 	svcsdk "github.com/aws/aws-sdk-go/service/docdb"
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/docdb/v1alpha1"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	svcutils "github.com/crossplane/provider-aws/pkg/controller/docdb"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 
 	"github.com/pkg/errors"
 )
@@ -60,12 +63,15 @@ func SetupDBInstance(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimite
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&svcapitypes.DBInstance{}).
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.DBInstanceGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
 		WithOptions(controller.Options{
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.DBInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithInitializers(managed.NewNameAsExternalName(mgr.GetClient()), &tagger{kube: mgr.GetClient()}),
 			managed.WithPollInterval(poll),
@@ -32,6 +32,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -43,8 +44,17 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/dynamodb/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
+const (
+	errListBackups  = "cannot list backups of Table in AWS"
+	errCreateBackup = "cannot create backup of Table in AWS"
+)
+
+// maxNameLength is the maximum length of the name of a DynamoDB table.
+const maxNameLength = 255
+
 // SetupTable adds a controller that reconciles Table.
 func SetupTable(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
 	name := managed.ControllerName(svcapitypes.TableGroupKind)
@@ -66,9 +76,11 @@ func SetupTable(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, po
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.Table{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.TableGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.TableGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name), lifecycle.WithReplacementName(lifecycle.SuffixedName(maxNameLength)))),
 			managed.WithInitializers(
 				managed.NewNameAsExternalName(mgr.GetClient()),
 				managed.NewDefaultProviderConfig(mgr.GetClient()),
@@ -91,6 +103,43 @@ func preDelete(_ context.Context, cr *svcapitypes.Table, obj *svcsdk.DeleteTable
 	return false, nil
 }
 
+// Snapshot takes an on-demand backup of the Table.
+func (e *external) Snapshot(ctx context.Context, mg resource.Managed, name string) (lifecycle.Snapshot, error) {
+	in := &svcsdk.ListBackupsInput{
+		TableName:  aws.String(meta.GetExternalName(mg)),
+		BackupType: aws.String(svcsdk.BackupTypeFilterUser),
+	}
+	for {
+		out, err := e.client.ListBackupsWithContext(ctx, in)
+		if err != nil {
+			return lifecycle.Snapshot{}, aws.Wrap(err, errListBackups)
+		}
+		for _, b := range out.BackupSummaries {
+			if aws.StringValue(b.BackupName) == name {
+				return lifecycle.Snapshot{
+					ID:        aws.StringValue(b.BackupArn),
+					Completed: aws.StringValue(b.BackupStatus) == svcsdk.BackupStatusAvailable,
+				}, nil
+			}
+		}
+		if out.LastEvaluatedBackupArn == nil {
+			break
+		}
+		in.ExclusiveStartBackupArn = out.LastEvaluatedBackupArn
+	}
+	out, err := e.client.CreateBackupWithContext(ctx, &svcsdk.CreateBackupInput{
+		TableName:  in.TableName,
+		BackupName: aws.String(name),
+	})
+	if err != nil {
+		return lifecycle.Snapshot{}, aws.Wrap(err, errCreateBackup)
+	}
+	return lifecycle.Snapshot{
+		ID:        aws.StringValue(out.BackupDetails.BackupArn),
+		Completed: aws.StringValue(out.BackupDetails.BackupStatus) == svcsdk.BackupStatusAvailable,
+	}, nil
+}
+
 func postObserve(_ context.Context, cr *svcapitypes.Table, resp *svcsdk.DescribeTableOutput, obs managed.ExternalObservation, err error) (managed.ExternalObservation, error) {
 	if err != nil {
 		return managed.ExternalObservation{}, err
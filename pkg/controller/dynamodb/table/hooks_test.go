@@ -17,13 +17,21 @@ limitations under the License.
 package table
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	svcsdk "github.com/aws/aws-sdk-go/service/dynamodb"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 
 	"github.com/crossplane/provider-aws/apis/dynamodb/v1alpha1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 var (
@@ -276,3 +284,102 @@ func TestLateInitialize(t *testing.T) {
 		})
 	}
 }
+
+type mockBackupClient struct {
+	svcsdkapi.DynamoDBAPI
+
+	pages  []*svcsdk.ListBackupsOutput
+	create func(*svcsdk.CreateBackupInput) (*svcsdk.CreateBackupOutput, error)
+}
+
+func (m *mockBackupClient) ListBackupsWithContext(_ context.Context, in *svcsdk.ListBackupsInput, _ ...request.Option) (*svcsdk.ListBackupsOutput, error) {
+	i := 0
+	if in.ExclusiveStartBackupArn != nil {
+		i = 1
+	}
+	return m.pages[i], nil
+}
+
+func (m *mockBackupClient) CreateBackupWithContext(_ context.Context, in *svcsdk.CreateBackupInput, _ ...request.Option) (*svcsdk.CreateBackupOutput, error) {
+	return m.create(in)
+}
+
+func TestSnapshot(t *testing.T) {
+	errBoom := errors.New("boom")
+	name := "table-final-20211018-153045"
+	backupArn := "arn:aws:dynamodb:us-east-1:123456789012:table/table/backup/01"
+
+	type want struct {
+		snapshot lifecycle.Snapshot
+		err      error
+	}
+
+	cases := map[string]struct {
+		client *mockBackupClient
+		want   want
+	}{
+		"CompletedOnSecondPage": {
+			client: &mockBackupClient{
+				pages: []*svcsdk.ListBackupsOutput{
+					{
+						BackupSummaries:        []*svcsdk.BackupSummary{{BackupName: aws.String("other")}},
+						LastEvaluatedBackupArn: aws.String("other-arn"),
+					},
+					{
+						BackupSummaries: []*svcsdk.BackupSummary{{
+							BackupName:   &name,
+							BackupArn:    &backupArn,
+							BackupStatus: aws.String(svcsdk.BackupStatusAvailable),
+						}},
+					},
+				},
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: backupArn, Completed: true},
+			},
+		},
+		"Created": {
+			client: &mockBackupClient{
+				pages: []*svcsdk.ListBackupsOutput{{}},
+				create: func(in *svcsdk.CreateBackupInput) (*svcsdk.CreateBackupOutput, error) {
+					if aws.StringValue(in.TableName) != "table" || aws.StringValue(in.BackupName) != name {
+						return nil, errBoom
+					}
+					return &svcsdk.CreateBackupOutput{BackupDetails: &svcsdk.BackupDetails{
+						BackupArn:    &backupArn,
+						BackupStatus: aws.String(svcsdk.BackupStatusCreating),
+					}}, nil
+				},
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: backupArn},
+			},
+		},
+		"CreateFailed": {
+			client: &mockBackupClient{
+				pages: []*svcsdk.ListBackupsOutput{{}},
+				create: func(in *svcsdk.CreateBackupInput) (*svcsdk.CreateBackupOutput, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				err: awsclient.Wrap(errBoom, errCreateBackup),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cr := &v1alpha1.Table{}
+			meta.SetExternalName(cr, "table")
+			e := &external{client: tc.client}
+			got, err := e.Snapshot(context.Background(), cr, name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Snapshot(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.snapshot, got); diff != "" {
+				t.Errorf("Snapshot(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
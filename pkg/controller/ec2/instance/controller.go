@@ -30,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -42,6 +43,7 @@ import (
 	svcapitypes "github.com/crossplane/provider-aws/apis/ec2/manualv1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/ec2"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -66,9 +68,11 @@ func SetupInstance(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter,
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.Instance{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.InstanceGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.InstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: ec2.NewInstanceClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: ec2.NewInstanceClient}, lifecycle.Options(mgr, name))),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithConnectionPublishers(),
 			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), &tagger{kube: mgr.GetClient()}),
@@ -186,7 +190,9 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 		}
 	}
 
-	ec2.LateInitializeInstance(&cr.Spec.ForProvider, &observed, &o)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		ec2.LateInitializeInstance(p.(*svcapitypes.InstanceParameters), &observed, &o)
+	})
 
 	if !cmp.Equal(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
@@ -220,6 +226,7 @@ func (e *external) Observe(ctx context.Context, mgd resource.Managed) (managed.E
 		ResourceExists:          true,
 		ResourceUpToDate:        ec2.IsInstanceUpToDate(cr.Spec.ForProvider, observed, o),
 		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		Diff:                    diff,
 	}, nil
 }
 
@@ -349,6 +356,20 @@ func (e *external) Delete(ctx context.Context, mgd resource.Managed) error {
 	return awsclient.Wrap(resource.Ignore(ec2.IsInstanceNotFoundErr, err), errDelete)
 }
 
+// DisableDeletionProtection disables the API termination protection of the
+// supplied Instance, and nothing else, so that it can be terminated.
+func (e *external) DisableDeletionProtection(ctx context.Context, mgd resource.Managed) error {
+	cr, ok := mgd.(*svcapitypes.Instance)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	_, err := e.client.ModifyInstanceAttribute(ctx, &awsec2.ModifyInstanceAttributeInput{
+		InstanceId:            aws.String(meta.GetExternalName(cr)),
+		DisableApiTermination: &types.AttributeBooleanValue{Value: aws.Bool(false)},
+	})
+	return awsclient.Wrap(err, errModifyInstanceAttributes)
+}
+
 type tagger struct {
 	kube client.Client
 }
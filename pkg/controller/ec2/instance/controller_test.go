@@ -357,6 +357,55 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestDisableDeletionProtection(t *testing.T) {
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Successful": {
+			args: args{
+				instance: &fake.MockInstanceClient{
+					MockModifyInstanceAttribute: func(ctx context.Context, input *awsec2.ModifyInstanceAttributeInput, opts []func(*awsec2.Options)) (*awsec2.ModifyInstanceAttributeOutput, error) {
+						if diff := cmp.Diff(&types.AttributeBooleanValue{Value: aws.Bool(false)}, input.DisableApiTermination, cmpopts.IgnoreUnexported(types.AttributeBooleanValue{})); diff != "" {
+							t.Errorf("r: -want, +got:\n%s", diff)
+						}
+						return &awsec2.ModifyInstanceAttributeOutput{}, nil
+					},
+				},
+				cr: instance(withExternalName(instanceID)),
+			},
+		},
+		"ModifyFailed": {
+			args: args{
+				instance: &fake.MockInstanceClient{
+					MockModifyInstanceAttribute: func(ctx context.Context, input *awsec2.ModifyInstanceAttributeInput, opts []func(*awsec2.Options)) (*awsec2.ModifyInstanceAttributeOutput, error) {
+						return nil, errBoom
+					},
+				},
+				cr: instance(withExternalName(instanceID)),
+			},
+			want: want{
+				err: awsclient.Wrap(errBoom, errModifyInstanceAttributes),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.instance}
+			err := e.DisableDeletionProtection(context.Background(), tc.args.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	type want struct {
 		cr     *manualv1alpha1.Instance
@@ -164,6 +164,7 @@ func TestObserve(t *testing.T) {
 					ResourceExists:          true,
 					ResourceUpToDate:        false,
 					ResourceLateInitialized: true,
+					Diff:                    `{"mapPublicIPOnLaunch":true}`,
 				},
 			},
 		},
@@ -2,13 +2,18 @@ package filesystem
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/backup"
+	"github.com/aws/aws-sdk-go/service/backup/backupiface"
 	svcsdk "github.com/aws/aws-sdk-go/service/efs"
+	"github.com/pkg/errors"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -20,6 +25,29 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/efs/v1alpha1"
 	awsclients "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+const (
+	// AnnotationKeyBackupVaultName is the key of the annotation that names
+	// the AWS Backup vault that stores the final backup of a FileSystem. The
+	// Default vault is used when it is not set.
+	AnnotationKeyBackupVaultName = "aws.crossplane.io/backup-vault-name"
+
+	// AnnotationKeyBackupRoleARN is the key of the annotation with the ARN of
+	// the IAM role that AWS Backup assumes to take the final backup of a
+	// FileSystem. The AWSBackupDefaultServiceRole of the account of the
+	// FileSystem is used when it is not set.
+	AnnotationKeyBackupRoleARN = "aws.crossplane.io/backup-role-arn"
+
+	defaultBackupVaultName = "Default"
+	defaultBackupRoleARN   = "arn:%s:iam::%s:role/service-role/AWSBackupDefaultServiceRole"
+
+	errNoFileSystemARN  = "FileSystem has no ARN to back up"
+	errListBackupJobs   = "cannot list backup jobs of FileSystem"
+	errStartBackupJob   = "cannot start backup job of FileSystem"
+	errBackupJobStopped = "backup job %s of FileSystem is %s: %s"
 )
 
 // SetupFileSystem adds a controller that reconciles FileSystem.
@@ -42,10 +70,13 @@ func SetupFileSystem(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimite
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.FileSystem{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.FileSystemGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.FileSystemGroupVersionKind),
 			managed.WithInitializers(),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&backupConnector{connector: &connector{kube: mgr.GetClient(), opts: opts}}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -110,3 +141,111 @@ func postCreate(_ context.Context, cr *svcapitypes.FileSystem, obj *svcsdk.FileS
 	meta.SetExternalName(cr, awsclients.StringValue(obj.FileSystemId))
 	return managed.ExternalCreation{}, nil
 }
+
+// A backupConnector connects to AWS Backup in addition to EFS, which has no
+// snapshot API of its own, so that a final backup of a FileSystem is taken
+// before it is deleted.
+type backupConnector struct {
+	*connector
+}
+
+func (c *backupConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.connector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	// The EFS connector has already checked the type of the managed resource.
+	cr := mg.(*svcapitypes.FileSystem)
+	sess, err := awsclients.GetConfigV1(ctx, c.kube, mg, cr.Spec.ForProvider.Region)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateSession)
+	}
+	return &backupExternal{external: ext.(*external), backup: backup.New(sess)}, nil
+}
+
+type backupExternal struct {
+	*external
+	backup backupiface.BackupAPI
+}
+
+// Snapshot takes an AWS Backup backup of the FileSystem. The backup job that
+// was started after the FileSystem was deleted is reused, so that a single
+// backup is taken however often its deletion is reconciled.
+func (e *backupExternal) Snapshot(ctx context.Context, mg resource.Managed, name string) (lifecycle.Snapshot, error) {
+	cr, ok := mg.(*svcapitypes.FileSystem)
+	if !ok {
+		return lifecycle.Snapshot{}, errors.New(errUnexpectedObject)
+	}
+	arn := awsclients.StringValue(cr.Status.AtProvider.FileSystemARN)
+	if arn == "" {
+		return lifecycle.Snapshot{}, errors.New(errNoFileSystemARN)
+	}
+	vault := backupVaultName(cr)
+	in := &backup.ListBackupJobsInput{
+		ByBackupVaultName: aws.String(vault),
+		ByResourceArn:     aws.String(arn),
+	}
+	if dt := cr.GetDeletionTimestamp(); dt != nil {
+		in.ByCreatedAfter = aws.Time(dt.Time)
+	}
+	rsp, err := e.backup.ListBackupJobsWithContext(ctx, in)
+	if err != nil {
+		return lifecycle.Snapshot{}, awsclients.Wrap(err, errListBackupJobs)
+	}
+	if job := latestBackupJob(rsp.BackupJobs); job != nil {
+		return generateSnapshot(job)
+	}
+	role, err := backupRoleARN(cr, arn)
+	if err != nil {
+		return lifecycle.Snapshot{}, err
+	}
+	out, err := e.backup.StartBackupJobWithContext(ctx, &backup.StartBackupJobInput{
+		BackupVaultName:  aws.String(vault),
+		IamRoleArn:       aws.String(role),
+		ResourceArn:      aws.String(arn),
+		IdempotencyToken: aws.String(name),
+	})
+	if err != nil {
+		return lifecycle.Snapshot{}, awsclients.Wrap(err, errStartBackupJob)
+	}
+	return lifecycle.Snapshot{ID: awsclients.StringValue(out.RecoveryPointArn)}, nil
+}
+
+func backupVaultName(cr *svcapitypes.FileSystem) string {
+	if v := cr.GetAnnotations()[AnnotationKeyBackupVaultName]; v != "" {
+		return v
+	}
+	return defaultBackupVaultName
+}
+
+func backupRoleARN(cr *svcapitypes.FileSystem, fileSystemARN string) (string, error) {
+	if v := cr.GetAnnotations()[AnnotationKeyBackupRoleARN]; v != "" {
+		return v, nil
+	}
+	a, err := awsclients.ParseARN(fileSystemARN)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(defaultBackupRoleARN, a.Partition, a.AccountID), nil
+}
+
+func latestBackupJob(jobs []*backup.Job) *backup.Job {
+	var latest *backup.Job
+	for _, j := range jobs {
+		if latest == nil || aws.TimeValue(j.CreationDate).After(aws.TimeValue(latest.CreationDate)) {
+			latest = j
+		}
+	}
+	return latest
+}
+
+func generateSnapshot(job *backup.Job) (lifecycle.Snapshot, error) {
+	s := lifecycle.Snapshot{ID: awsclients.StringValue(job.RecoveryPointArn)}
+	switch state := awsclients.StringValue(job.State); state {
+	case backup.JobStateCompleted:
+		s.Completed = true
+	case backup.JobStateAborted, backup.JobStateExpired, backup.JobStateFailed:
+		return lifecycle.Snapshot{}, errors.Errorf(errBackupJobStopped, awsclients.StringValue(job.BackupJobId), state, awsclients.StringValue(job.StatusMessage))
+	}
+	return s, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesystem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/backup"
+	"github.com/aws/aws-sdk-go/service/backup/backupiface"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	svcapitypes "github.com/crossplane/provider-aws/apis/efs/v1alpha1"
+	awsclients "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+var (
+	errBoom = errors.New("boom")
+
+	fileSystemARN  = "arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/fs-cool"
+	recoveryPoint  = "arn:aws:backup:us-east-1:123456789012:recovery-point:cool"
+	snapshotName   = "fs-cool-final-20211018-153045"
+	deletedAt      = time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC)
+	defaultRoleARN = "arn:aws:iam::123456789012:role/service-role/AWSBackupDefaultServiceRole"
+)
+
+var _ lifecycle.Snapshotter = &backupExternal{}
+
+type mockBackup struct {
+	backupiface.BackupAPI
+	list  func(*backup.ListBackupJobsInput) (*backup.ListBackupJobsOutput, error)
+	start func(*backup.StartBackupJobInput) (*backup.StartBackupJobOutput, error)
+}
+
+func (m *mockBackup) ListBackupJobsWithContext(_ context.Context, in *backup.ListBackupJobsInput, _ ...request.Option) (*backup.ListBackupJobsOutput, error) {
+	return m.list(in)
+}
+
+func (m *mockBackup) StartBackupJobWithContext(_ context.Context, in *backup.StartBackupJobInput, _ ...request.Option) (*backup.StartBackupJobOutput, error) {
+	return m.start(in)
+}
+
+func fileSystem(a map[string]string) *svcapitypes.FileSystem {
+	cr := &svcapitypes.FileSystem{}
+	meta.SetExternalName(cr, "fs-cool")
+	meta.AddAnnotations(cr, a)
+	cr.SetDeletionTimestamp(&metav1.Time{Time: deletedAt})
+	cr.Status.AtProvider.FileSystemARN = aws.String(fileSystemARN)
+	return cr
+}
+
+func listJobs(jobs ...*backup.Job) func(*backup.ListBackupJobsInput) (*backup.ListBackupJobsOutput, error) {
+	return func(in *backup.ListBackupJobsInput) (*backup.ListBackupJobsOutput, error) {
+		if aws.StringValue(in.ByResourceArn) != fileSystemARN || !aws.TimeValue(in.ByCreatedAfter).Equal(deletedAt) {
+			return nil, errBoom
+		}
+		return &backup.ListBackupJobsOutput{BackupJobs: jobs}, nil
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	type want struct {
+		snapshot lifecycle.Snapshot
+		err      error
+	}
+
+	cases := map[string]struct {
+		cr     *svcapitypes.FileSystem
+		backup *mockBackup
+		want   want
+	}{
+		"Completed": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: listJobs(
+					&backup.Job{State: aws.String(backup.JobStateFailed), CreationDate: aws.Time(deletedAt)},
+					&backup.Job{State: aws.String(backup.JobStateCompleted), RecoveryPointArn: aws.String(recoveryPoint), CreationDate: aws.Time(deletedAt.Add(time.Minute))},
+				),
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: recoveryPoint, Completed: true},
+			},
+		},
+		"Running": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: listJobs(&backup.Job{State: aws.String(backup.JobStateRunning), RecoveryPointArn: aws.String(recoveryPoint)}),
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: recoveryPoint},
+			},
+		},
+		"Failed": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: listJobs(&backup.Job{BackupJobId: aws.String("job"), State: aws.String(backup.JobStateFailed), StatusMessage: aws.String("denied")}),
+			},
+			want: want{
+				err: errors.Errorf(errBackupJobStopped, "job", backup.JobStateFailed, "denied"),
+			},
+		},
+		"StartedWithDefaults": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: listJobs(),
+				start: func(in *backup.StartBackupJobInput) (*backup.StartBackupJobOutput, error) {
+					want := &backup.StartBackupJobInput{
+						BackupVaultName:  aws.String(defaultBackupVaultName),
+						IamRoleArn:       aws.String(defaultRoleARN),
+						ResourceArn:      aws.String(fileSystemARN),
+						IdempotencyToken: aws.String(snapshotName),
+					}
+					if diff := cmp.Diff(want, in); diff != "" {
+						t.Errorf("StartBackupJob: -want, +got:\n%s", diff)
+					}
+					return &backup.StartBackupJobOutput{RecoveryPointArn: aws.String(recoveryPoint)}, nil
+				},
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: recoveryPoint},
+			},
+		},
+		"StartedWithAnnotations": {
+			cr: fileSystem(map[string]string{
+				AnnotationKeyBackupVaultName: "vault",
+				AnnotationKeyBackupRoleARN:   "role",
+			}),
+			backup: &mockBackup{
+				list: func(in *backup.ListBackupJobsInput) (*backup.ListBackupJobsOutput, error) {
+					if aws.StringValue(in.ByBackupVaultName) != "vault" {
+						return nil, errBoom
+					}
+					return &backup.ListBackupJobsOutput{}, nil
+				},
+				start: func(in *backup.StartBackupJobInput) (*backup.StartBackupJobOutput, error) {
+					if aws.StringValue(in.BackupVaultName) != "vault" || aws.StringValue(in.IamRoleArn) != "role" {
+						return nil, errBoom
+					}
+					return &backup.StartBackupJobOutput{RecoveryPointArn: aws.String(recoveryPoint)}, nil
+				},
+			},
+			want: want{
+				snapshot: lifecycle.Snapshot{ID: recoveryPoint},
+			},
+		},
+		"ListFailed": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: func(*backup.ListBackupJobsInput) (*backup.ListBackupJobsOutput, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				err: awsclients.Wrap(errBoom, errListBackupJobs),
+			},
+		},
+		"StartFailed": {
+			cr: fileSystem(nil),
+			backup: &mockBackup{
+				list: listJobs(),
+				start: func(*backup.StartBackupJobInput) (*backup.StartBackupJobOutput, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				err: awsclients.Wrap(errBoom, errStartBackupJob),
+			},
+		},
+		"NoARN": {
+			cr: &svcapitypes.FileSystem{},
+			want: want{
+				err: errors.New(errNoFileSystemARN),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &backupExternal{external: &external{}, backup: tc.backup}
+			snapshot, err := e.Snapshot(context.Background(), tc.cr, snapshotName)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Snapshot(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.snapshot, snapshot); diff != "" {
+				t.Errorf("Snapshot(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// groupSuffix is the suffix of the API groups of this provider, which may be
+// omitted from the groups of a Filter.
+const groupSuffix = ".aws.crossplane.io"
+
+// A Filter selects the controllers to set up by the kind of the managed
+// resources they reconcile. Its entries are either an API group, with or
+// without its suffix, e.g. ec2 or ec2.aws.crossplane.io, a Kind, e.g.
+// IAMPolicy, or a Kind qualified with its group, e.g.
+// Cluster.eks.aws.crossplane.io. Entries are case-insensitive.
+type Filter struct {
+	// Enabled selects the only controllers that are set up. All controllers
+	// are enabled when it is empty.
+	Enabled []string
+
+	// Disabled selects the controllers that are not set up, even if they are
+	// enabled.
+	Disabled []string
+}
+
+// Allows returns true if the controller of the supplied kind is enabled.
+func (f Filter) Allows(gk schema.GroupKind) bool {
+	if matches(f.Disabled, gk) {
+		return false
+	}
+	return len(f.Enabled) == 0 || matches(f.Enabled, gk)
+}
+
+func matches(entries []string, gk schema.GroupKind) bool {
+	for _, e := range entries {
+		switch strings.ToLower(e) {
+		case strings.ToLower(gk.Group),
+			strings.ToLower(strings.TrimSuffix(gk.Group, groupSuffix)),
+			strings.ToLower(gk.Kind),
+			strings.ToLower(gk.Kind + "." + gk.Group):
+			return true
+		}
+	}
+	return false
+}
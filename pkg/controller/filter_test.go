@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestFilterAllows(t *testing.T) {
+	cluster := schema.GroupKind{Group: "eks.aws.crossplane.io", Kind: "Cluster"}
+
+	cases := map[string]struct {
+		f    Filter
+		want bool
+	}{
+		"Empty": {
+			want: true,
+		},
+		"EnabledGroup": {
+			f:    Filter{Enabled: []string{"ec2", "eks.aws.crossplane.io"}},
+			want: true,
+		},
+		"EnabledShortGroup": {
+			f:    Filter{Enabled: []string{"EKS"}},
+			want: true,
+		},
+		"EnabledKind": {
+			f:    Filter{Enabled: []string{"cluster"}},
+			want: true,
+		},
+		"EnabledQualifiedKind": {
+			f:    Filter{Enabled: []string{"Cluster.eks.aws.crossplane.io"}},
+			want: true,
+		},
+		"NotEnabled": {
+			f:    Filter{Enabled: []string{"Cluster.redshift.aws.crossplane.io"}},
+			want: false,
+		},
+		"Disabled": {
+			f:    Filter{Disabled: []string{"eks"}},
+			want: false,
+		},
+		"DisabledTakesPrecedence": {
+			f:    Filter{Enabled: []string{"eks"}, Disabled: []string{"Cluster"}},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.f.Allows(cluster)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Allows(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
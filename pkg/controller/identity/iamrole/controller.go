@@ -28,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -40,6 +41,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/identity/v1beta1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/iam"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -55,6 +57,9 @@ const (
 	errUpToDateFailed   = "cannot check whether object is up-to-date"
 )
 
+// maxNameLength is the maximum length of the name of an IAM role.
+const maxNameLength = 64
+
 // SetupIAMRole adds a controller that reconciles IAMRoles.
 func SetupIAMRole(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
 	name := managed.ControllerName(v1beta1.IAMRoleGroupKind)
@@ -65,9 +70,11 @@ func SetupIAMRole(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter,
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1beta1.IAMRole{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1beta1.IAMRoleGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.IAMRoleGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: iam.NewRoleClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: iam.NewRoleClient}, lifecycle.Options(mgr, name), lifecycle.WithExternalName(lifecycle.ResourceName), lifecycle.WithReplacementName(lifecycle.SuffixedName(maxNameLength)))),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithConnectionPublishers(),
 			managed.WithPollInterval(poll),
@@ -144,7 +151,11 @@ func (e *external) Create(ctx context.Context, mgd resource.Managed) (managed.Ex
 
 	cr.Status.SetConditions(xpv1.Creating())
 
-	_, err := e.client.CreateRole(ctx, iam.GenerateCreateRoleInput(meta.GetExternalName(cr), &cr.Spec.ForProvider))
+	input, err := iam.GenerateCreateRoleInput(meta.GetExternalName(cr), &cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+	_, err = e.client.CreateRole(ctx, input)
 	return managed.ExternalCreation{}, awsclient.Wrap(err, errCreate)
 }
 
@@ -200,9 +211,17 @@ func (e *external) Update(ctx context.Context, mgd resource.Managed) (managed.Ex
 		}
 	}
 
-	if patch.AssumeRolePolicyDocument != "" {
+	doc, err := iam.AssumeRolePolicyDocument(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+	policyUpToDate, err := iam.IsAssumeRolePolicyUpToDate(&doc, observed.Role.AssumeRolePolicyDocument)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+	if doc != "" && !policyUpToDate {
 		_, err = e.client.UpdateAssumeRolePolicy(ctx, &awsiam.UpdateAssumeRolePolicyInput{
-			PolicyDocument: &cr.Spec.ForProvider.AssumeRolePolicyDocument,
+			PolicyDocument: &doc,
 			RoleName:       aws.String(meta.GetExternalName(cr)),
 		})
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -21,6 +22,7 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/kms/v1alpha1"
 	awsclients "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupKey adds a controller that reconciles Key.
@@ -29,13 +31,14 @@ func SetupKey(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll
 	opts := []option{
 		func(e *external) {
 			e.preObserve = preObserve
-			e.postObserve = postObserve
+			e.preCreate = preCreate
 			e.postCreate = postCreate
 			u := &updater{client: e.client}
 			e.update = u.update
 			d := &deleter{client: e.client}
 			e.delete = d.delete
 			o := &observer{client: e.client}
+			e.postObserve = o.postObserve
 			e.isUpToDate = o.isUpToDate
 			e.lateInitialize = o.lateInitialize
 		},
@@ -46,9 +49,11 @@ func SetupKey(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.Key{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.KeyGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.KeyGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -81,6 +86,15 @@ func postObserve(_ context.Context, cr *svcapitypes.Key, obj *svcsdk.DescribeKey
 	return obs, nil
 }
 
+func preCreate(_ context.Context, cr *svcapitypes.Key, obj *svcsdk.CreateKeyInput) error {
+	policy, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+	if err != nil {
+		return err
+	}
+	obj.Policy = policy
+	return nil
+}
+
 func postCreate(_ context.Context, cr *svcapitypes.Key, obj *svcsdk.CreateKeyOutput, creation managed.ExternalCreation, err error) (managed.ExternalCreation, error) {
 	if err != nil {
 		return creation, err
@@ -109,10 +123,14 @@ func (u *updater) update(ctx context.Context, mg resource.Managed) (managed.Exte
 	}
 
 	// Policy
+	policy, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
 	if _, err := u.client.PutKeyPolicyWithContext(ctx, &svcsdk.PutKeyPolicyInput{
 		KeyId:      awsclients.String(meta.GetExternalName(cr)),
 		PolicyName: awsclients.String("default"),
-		Policy:     cr.Spec.ForProvider.Policy,
+		Policy:     policy,
 	}); err != nil {
 		return managed.ExternalUpdate{}, awsclients.Wrap(err, errUpdate)
 	}
@@ -215,11 +233,12 @@ func (d *deleter) delete(ctx context.Context, mg resource.Managed) error {
 
 type observer struct {
 	client svcsdkapi.KMSAPI
+	diff   string
 }
 
 func (o *observer) lateInitialize(in *svcapitypes.KeyParameters, obj *svcsdk.DescribeKeyOutput) error {
 	// Policy
-	if in.Policy == nil {
+	if in.Policy == nil && in.PolicyDocument == nil {
 		resPolicy, err := o.client.GetKeyPolicy(&svcsdk.GetKeyPolicyInput{
 			KeyId:      obj.KeyMetadata.KeyId,
 			PolicyName: awsclients.String("default"),
@@ -257,16 +276,33 @@ func (o *observer) lateInitialize(in *svcapitypes.KeyParameters, obj *svcsdk.Des
 }
 
 func (o *observer) isUpToDate(cr *svcapitypes.Key, obj *svcsdk.DescribeKeyOutput) (bool, error) {
+	observed, err := o.observedParameters(cr, obj)
+	if err != nil {
+		return false, err
+	}
+	o.diff, err = awsclients.DiffJSON(observed, cr.Spec.ForProvider)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot diff Key")
+	}
+	return o.diff == "", nil
+}
+
+// observedParameters returns a copy of the desired parameters of the
+// supplied Key in which the fields that differ from the observed Key are
+// replaced by their observed values.
+func (o *observer) observedParameters(cr *svcapitypes.Key, obj *svcsdk.DescribeKeyOutput) (*svcapitypes.KeyParameters, error) {
+	observed := cr.Spec.ForProvider.DeepCopy()
+
 	// Description
 	if obj.KeyMetadata.Description != nil &&
 		cr.Spec.ForProvider.Description != nil &&
 		awsclients.StringValue(obj.KeyMetadata.Description) != awsclients.StringValue(cr.Spec.ForProvider.Description) {
-		return false, nil
+		observed.Description = obj.KeyMetadata.Description
 	}
 
 	// Enabled
 	if !isUpToDateEnableDisable(cr) {
-		return false, nil
+		observed.Enabled = cr.Status.AtProvider.Enabled
 	}
 
 	// KeyPolicy
@@ -275,10 +311,15 @@ func (o *observer) isUpToDate(cr *svcapitypes.Key, obj *svcsdk.DescribeKeyOutput
 		PolicyName: awsclients.String("default"),
 	})
 	if err != nil {
-		return false, awsclients.Wrap(err, "cannot get key policy")
+		return nil, awsclients.Wrap(err, "cannot get key policy")
+	}
+	policy, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+	if err != nil {
+		return nil, err
 	}
-	if awsclients.StringValue(cr.Spec.ForProvider.Policy) != awsclients.StringValue(resPolicy.Policy) {
-		return false, nil
+	if awsclients.StringValue(policy) != awsclients.StringValue(resPolicy.Policy) && !awsclients.IsPolicyUpToDate(policy, resPolicy.Policy) {
+		observed.Policy = resPolicy.Policy
+		observed.PolicyDocument = nil
 	}
 
 	// Tags
@@ -286,10 +327,20 @@ func (o *observer) isUpToDate(cr *svcapitypes.Key, obj *svcsdk.DescribeKeyOutput
 		KeyId: awsclients.String(meta.GetExternalName(cr)),
 	})
 	if err != nil {
-		return false, awsclients.Wrap(err, "cannot list tags")
+		return nil, awsclients.Wrap(err, "cannot list tags")
+	}
+	if addTags, removeTags := diffTags(cr.Spec.ForProvider.Tags, resTags.Tags); len(addTags) != 0 || len(removeTags) != 0 {
+		observed.Tags = make([]*svcapitypes.Tag, len(resTags.Tags))
+		for i, t := range resTags.Tags {
+			observed.Tags[i] = &svcapitypes.Tag{TagKey: t.TagKey, TagValue: t.TagValue}
+		}
 	}
-	addTags, removeTags := diffTags(cr.Spec.ForProvider.Tags, resTags.Tags)
-	return len(addTags) == 0 && len(removeTags) == 0, nil
+	return observed, nil
+}
+
+func (o *observer) postObserve(ctx context.Context, cr *svcapitypes.Key, obj *svcsdk.DescribeKeyOutput, obs managed.ExternalObservation, err error) (managed.ExternalObservation, error) {
+	obs.Diff = o.diff
+	return postObserve(ctx, cr, obj, obs, err)
 }
 
 // returns which AWS Tags exist in the resource tags and which are outdated and should be removed
@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -24,6 +25,7 @@ import (
 	"github.com/crossplane/provider-aws/apis/lambda/v1alpha1"
 	svcapitypes "github.com/crossplane/provider-aws/apis/lambda/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupFunction adds a controller that reconciles Function.
@@ -47,9 +49,11 @@ func SetupFunction(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter,
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.Function{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.FunctionGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.FunctionGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -101,6 +105,12 @@ func postObserve(_ context.Context, cr *svcapitypes.Function, resp *svcsdk.GetFu
 	case string(svcapitypes.State_Failed), string(svcapitypes.State_Inactive):
 		cr.SetConditions(xpv1.Unavailable())
 	}
+	if !obs.ResourceUpToDate {
+		obs.Diff, err = aws.DiffJSON(observedParameters(cr, resp), cr.Spec.ForProvider)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot diff Function")
+		}
+	}
 	return obs, nil
 }
 
@@ -179,6 +189,63 @@ func isUpToDate(cr *svcapitypes.Function, obj *svcsdk.GetFunctionOutput) (bool,
 
 }
 
+// observedParameters returns a copy of the desired parameters of the supplied
+// Function in which the fields that isUpToDate found to differ from the
+// observed Function are replaced by their observed values.
+func observedParameters(cr *svcapitypes.Function, obj *svcsdk.GetFunctionOutput) *svcapitypes.FunctionParameters { // nolint:gocyclo
+	observed := cr.Spec.ForProvider.DeepCopy()
+	c := obj.Configuration
+	if aws.StringValue(observed.Description) != aws.StringValue(c.Description) {
+		observed.Description = c.Description
+	}
+	if !isUpToDateEnvironment(cr, obj) {
+		observed.Environment = nil
+		if c.Environment != nil {
+			observed.Environment = &svcapitypes.Environment{Variables: c.Environment.Variables}
+		}
+	}
+	if !isUpToDateFileSystemConfigs(cr, obj) {
+		observed.FileSystemConfigs = make([]*svcapitypes.FileSystemConfig, len(c.FileSystemConfigs))
+		for i, f := range c.FileSystemConfigs {
+			observed.FileSystemConfigs[i] = &svcapitypes.FileSystemConfig{ARN: f.Arn, LocalMountPath: f.LocalMountPath}
+		}
+	}
+	if aws.StringValue(observed.Handler) != aws.StringValue(c.Handler) {
+		observed.Handler = c.Handler
+	}
+	if aws.StringValue(observed.KMSKeyARN) != aws.StringValue(c.KMSKeyArn) {
+		observed.KMSKeyARN = c.KMSKeyArn
+	}
+	if aws.Int64Value(observed.MemorySize) != aws.Int64Value(c.MemorySize) {
+		observed.MemorySize = c.MemorySize
+	}
+	if aws.StringValue(observed.Role) != aws.StringValue(c.Role) {
+		observed.Role = c.Role
+	}
+	if aws.StringValue(observed.Runtime) != aws.StringValue(c.Runtime) {
+		observed.Runtime = c.Runtime
+	}
+	if aws.Int64Value(observed.Timeout) != aws.Int64Value(c.Timeout) {
+		observed.Timeout = c.Timeout
+	}
+	if c.TracingConfig != nil && !isUpToDateTracingConfig(cr, obj) {
+		observed.TracingConfig = &svcapitypes.TracingConfig{Mode: c.TracingConfig.Mode}
+	}
+	if !isUpToDateSecurityGroupIDs(cr, obj) {
+		if observed.CustomFunctionVPCConfigParameters == nil {
+			observed.CustomFunctionVPCConfigParameters = &svcapitypes.CustomFunctionVPCConfigParameters{}
+		}
+		observed.CustomFunctionVPCConfigParameters.SecurityGroupIDs = nil
+		if c.VpcConfig != nil {
+			observed.CustomFunctionVPCConfigParameters.SecurityGroupIDs = c.VpcConfig.SecurityGroupIds
+		}
+	}
+	if addTags, removeTags := diffTags(observed.Tags, obj.Tags); len(addTags) != 0 || len(removeTags) != 0 {
+		observed.Tags = obj.Tags
+	}
+	return observed
+}
+
 // isUpToDateEnvironment checks if FunctionConfiguration EnvironmentResponse Variables are up to date
 func isUpToDateEnvironment(cr *svcapitypes.Function, obj *svcsdk.GetFunctionOutput) bool {
 	// Handle nil pointer refs
@@ -1,6 +1,7 @@
 package function
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -158,6 +159,47 @@ func TestIsUpToDateEnvironment(t *testing.T) {
 	}
 }
 
+func TestPostObserveDiff(t *testing.T) {
+	cases := map[string]struct {
+		args
+		upToDate bool
+		want     string
+	}{
+		"UpToDate": {
+			args: args{
+				cr:  function(withSpec(v1alpha1.FunctionParameters{Handler: aws.String("index.handler")})),
+				obj: &svcsdk.GetFunctionOutput{Configuration: &svcsdk.FunctionConfiguration{Handler: aws.String("index.handler")}},
+			},
+			upToDate: true,
+		},
+		"Differs": {
+			args: args{
+				cr: function(withSpec(v1alpha1.FunctionParameters{
+					Handler:    aws.String("index.handler"),
+					MemorySize: aws.Int64(256),
+					Tags:       map[string]*string{"team": aws.String("a")},
+				})),
+				obj: &svcsdk.GetFunctionOutput{
+					Configuration: &svcsdk.FunctionConfiguration{Handler: aws.String("main.handler"), MemorySize: aws.Int64(256)},
+					Tags:          map[string]*string{"team": aws.String("b")},
+				},
+			},
+			want: `{"handler":"index.handler","tags":{"team":"a"}}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obs, err := postObserve(context.Background(), tc.args.cr, tc.args.obj, managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: tc.upToDate}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, obs.Diff); diff != "" {
+				t.Errorf("r: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestIsUpToDateFileSystemConfigs(t *testing.T) {
 	type want struct {
 		result bool
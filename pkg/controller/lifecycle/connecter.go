@@ -0,0 +1,208 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lifecycle wraps the external clients and reconcilers of every
+// managed resource controller to apply the provider-wide lifecycle policies,
+// such as the import of existing external resources, the default tags, the
+// observe-only management policy, the dry-run mode, the reporting of drift,
+// the final snapshots of stateful resources, deletion protection, the
+// replacement of external resources whose immutable fields changed, the
+// classification of AWS errors, poll intervals and sharding, regardless of
+// whether the controller is hand-written or generated.
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// A Connecter applies the lifecycle policies of the provider to the external
+// clients produced by the ExternalConnecter it wraps.
+type Connecter struct {
+	connecter       managed.ExternalConnecter
+	record          event.Recorder
+	externalName    ExternalNameFn
+	replacementName ReplacementNameFn
+	kube            client.Client
+}
+
+// A ConnecterOption configures a Connecter.
+type ConnecterOption func(*Connecter)
+
+// WithRecorder configures the recorder of the events emitted by the
+// lifecycle policies.
+func WithRecorder(r event.Recorder) ConnecterOption {
+	return func(c *Connecter) {
+		c.record = r
+	}
+}
+
+// WithExternalName configures how the external name of an imported resource
+// is derived from its ARN. The resource ID of the ARN is used by default.
+func WithExternalName(fn ExternalNameFn) ConnecterOption {
+	return func(c *Connecter) {
+		c.externalName = fn
+	}
+}
+
+// WithReplacementName configures the external name the replacement of an
+// external resource is created under. It is assigned on creation by default.
+func WithReplacementName(fn ReplacementNameFn) ConnecterOption {
+	return func(c *Connecter) {
+		c.replacementName = fn
+	}
+}
+
+// WithKubeClient configures the client used to persist the changes the
+// lifecycle policies make to the spec of a managed resource, such as its
+// native deletion protection, and to read its ProviderConfig, e.g. for its
+// default tags. It is the same client WithDefaultTags configures. These
+// policies, and the replacement of external resources, are not applied unless
+// this option is given.
+func WithKubeClient(kube client.Client) ConnecterOption {
+	return func(c *Connecter) {
+		c.kube = kube
+	}
+}
+
+// Options configures a Connecter with the options shared by every
+// controller, i.e. the recorder of the controller with the supplied name and
+// the client of the supplied manager.
+func Options(mgr ctrl.Manager, name string) ConnecterOption {
+	return func(c *Connecter) {
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))(c)
+		WithKubeClient(mgr.GetClient())(c)
+	}
+}
+
+// NewConnecter returns a Connecter that wraps the supplied ExternalConnecter.
+func NewConnecter(c managed.ExternalConnecter, o ...ConnecterOption) *Connecter {
+	lc := &Connecter{
+		connecter:       c,
+		record:          event.NewNopRecorder(),
+		externalName:    ResourceID,
+		replacementName: AssignedName,
+	}
+	for _, fn := range o {
+		fn(lc)
+	}
+	return lc
+}
+
+// Connect to the provider specified by the supplied managed resource and
+// produce an ExternalClient that applies the lifecycle policies.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if err := c.setImportExternalName(mg); err != nil {
+		return nil, err
+	}
+	if err := c.addDefaultTags(ctx, mg); err != nil {
+		return nil, err
+	}
+	if err := c.setDeletionProtection(ctx, mg); err != nil {
+		return nil, err
+	}
+	ec, err := c.connecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, record: c.record, gk: c.groupKind(mg), replacementName: c.replacementName}, nil
+}
+
+// groupKind returns the kind of the supplied managed resource, or an empty
+// kind if it cannot be determined without a kube client.
+func (c *Connecter) groupKind(mg resource.Managed) schema.GroupKind {
+	if c.kube == nil || c.kube.Scheme() == nil {
+		return schema.GroupKind{}
+	}
+	gvk, err := apiutil.GVKForObject(mg, c.kube.Scheme())
+	if err != nil {
+		return schema.GroupKind{}
+	}
+	return gvk.GroupKind()
+}
+
+type external struct {
+	managed.ExternalClient
+	record          event.Recorder
+	gk              schema.GroupKind
+	replacementName ReplacementNameFn
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err = reportError(mg, err); err != nil {
+		return o, err
+	}
+	e.observeDrift(mg, o)
+	if o, err = e.observeImport(mg, o); err != nil {
+		return o, err
+	}
+	if o, err = e.observeReplacement(ctx, mg, o); err != nil {
+		return o, err
+	}
+	if o, err = observeOnly(mg, o); err != nil {
+		return o, err
+	}
+	return e.observeDryRun(mg, o), nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if err := refuseImportCreate(mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := refuseObserveOnly(mg, "create"); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, reportError(mg, err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if err := refuseObserveOnly(mg, "update"); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, reportError(mg, err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	if err := refuseObserveOnly(mg, "delete"); err != nil {
+		return err
+	}
+	if e.deleteDryRun(mg) {
+		return nil
+	}
+	if err := e.refuseDeletionProtected(mg); err != nil {
+		return err
+	}
+	if err := e.clearDeletionProtection(ctx, mg); err != nil {
+		return err
+	}
+	if ok, err := e.snapshotBeforeDelete(ctx, mg); !ok || err != nil {
+		return err
+	}
+	if err := reportError(mg, e.ExternalClient.Delete(ctx, mg)); err != nil {
+		return err
+	}
+	return e.deleteReplacedWith(ctx, mg)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+const (
+	errGetDefaultTags     = "cannot get default tags"
+	errPersistDefaultTags = "cannot persist default tags"
+)
+
+// WithDefaultTags configures the client used to read the default tags of the
+// ProviderConfig of a managed resource and to persist them in its spec.
+// Default tags are not applied unless this option is given.
+func WithDefaultTags(kube client.Client) ConnecterOption {
+	return func(c *Connecter) {
+		c.kube = kube
+	}
+}
+
+// addDefaultTags adds the default tags of the ProviderConfig of the supplied
+// managed resource to its tags and persists them, so that the controller
+// treats them as desired state. The tags of the managed resource take
+// precedence. Default tags that are removed from the ProviderConfig are not
+// removed from the managed resources they were added to.
+func (c *Connecter) addDefaultTags(ctx context.Context, mg resource.Managed) error {
+	if c.kube == nil || meta.WasDeleted(mg) || IsObserveOnly(mg) {
+		return nil
+	}
+	tags, err := awsclient.GetDefaultTags(ctx, c.kube, mg)
+	if err != nil {
+		return errors.Wrap(err, errGetDefaultTags)
+	}
+	if !awsclient.AddDefaultTags(mg, tags) {
+		return nil
+	}
+	return errors.Wrap(c.kube.Update(ctx, mg), errPersistDefaultTags)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+)
+
+type taggedManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			Tags map[string]string `json:"tags,omitempty"`
+		}
+	}
+}
+
+func newTaggedManaged(tags map[string]string, a map[string]string) *taggedManaged {
+	mg := &taggedManaged{}
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+	mg.Spec.ForProvider.Tags = tags
+	meta.AddAnnotations(mg, a)
+	return mg
+}
+
+func TestDefaultTags(t *testing.T) {
+	errBoom := errors.New("boom")
+	defaults := map[string]string{"owner": "platform", "cost-center": "42"}
+
+	type want struct {
+		err     error
+		tags    map[string]string
+		updated bool
+	}
+
+	cases := map[string]struct {
+		mg     *taggedManaged
+		getErr error
+		noKube bool
+		want   want
+	}{
+		"Added": {
+			mg: newTaggedManaged(map[string]string{"owner": "team-a"}, nil),
+			want: want{
+				tags:    map[string]string{"owner": "team-a", "cost-center": "42"},
+				updated: true,
+			},
+		},
+		"AlreadyTagged": {
+			mg: newTaggedManaged(map[string]string{"owner": "team-a", "cost-center": "1"}, nil),
+			want: want{
+				tags: map[string]string{"owner": "team-a", "cost-center": "1"},
+			},
+		},
+		"ObserveOnly": {
+			mg: newTaggedManaged(nil, map[string]string{AnnotationKeyManagementPolicy: string(ManagementPolicyObserveOnly)}),
+		},
+		"NotConfigured": {
+			mg:     newTaggedManaged(nil, nil),
+			noKube: true,
+		},
+		"GetProviderConfigError": {
+			mg:     newTaggedManaged(nil, nil),
+			getErr: errBoom,
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get referenced ProviderConfig"), errGetDefaultTags),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.getErr != nil {
+						return tc.getErr
+					}
+					obj.(*v1beta1.ProviderConfig).Spec.DefaultTags = defaults
+					return nil
+				},
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return nil
+				},
+			}
+			o := []ConnecterOption{}
+			if !tc.noKube {
+				o = append(o, WithDefaultTags(kube))
+			}
+			_, err := NewConnecter(recordingClient(managed.ExternalObservation{}, &[]string{}), o...).Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Connect(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.tags, tc.mg.Spec.ForProvider.Tags); diff != "" {
+				t.Errorf("tags: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("updated: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
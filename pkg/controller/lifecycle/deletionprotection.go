@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"strconv"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// AnnotationKeyDeletionProtection is the key of the annotation that protects
+// the external resource of a managed resource from deletion when set to
+// "true".
+const AnnotationKeyDeletionProtection = "aws.crossplane.io/deletion-protection"
+
+// AnnotationKeyDeletionProtectionSet is the key of the annotation that records
+// that the provider enabled the native deletion protection flag of a managed
+// resource, so that it disables the flag again once the deletion protection
+// annotation is removed.
+const AnnotationKeyDeletionProtectionSet = "aws.crossplane.io/deletion-protection-set"
+
+// TypeDeletionProtection is the type of the condition that reports that the
+// deletion of the external resource of a managed resource was refused.
+const TypeDeletionProtection xpv1.ConditionType = "DeletionProtection"
+
+// ReasonDeletionProtected is the reason of the deletion protection condition.
+const ReasonDeletionProtected xpv1.ConditionReason = "DeletionProtected"
+
+const (
+	msgDeletionProtected = "The external resource will not be deleted until the " + AnnotationKeyDeletionProtection + " annotation is removed or set to false"
+
+	errDeletionProtected         = "refusing to delete a deletion-protected external resource"
+	errPersistDeletionProtection = "cannot persist deletion protection"
+	errClearDeletionProtection   = "cannot disable deletion protection before deletion"
+)
+
+// IsDeletionProtected returns whether the external resource of the supplied
+// managed resource is protected from deletion.
+func IsDeletionProtected(mg resource.Managed) bool {
+	v, err := strconv.ParseBool(mg.GetAnnotations()[AnnotationKeyDeletionProtection])
+	return err == nil && v
+}
+
+// DeletionProtectionCondition returns a condition that reports that the
+// deletion of an external resource was refused.
+func DeletionProtectionCondition() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtection,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionProtected,
+		Message:            msgDeletionProtected,
+	}
+}
+
+// refuseDeletionProtected returns an error if the external resource of the
+// supplied managed resource is protected from deletion. The refusal is
+// reported in the deletion protection condition and in an event.
+func (e *external) refuseDeletionProtected(mg resource.Managed) error {
+	if !IsDeletionProtected(mg) {
+		return nil
+	}
+	e.report(mg, DeletionProtectionCondition())
+	return errors.New(errDeletionProtected)
+}
+
+// setDeletionProtection propagates the deletion protection annotation of the
+// supplied managed resource to its native deletion protection flag, if AWS
+// supports one for its kind, and persists it, so that the external resource
+// is also protected from deletion outside of the provider. The flag is left
+// alone if the annotation is absent, unless the provider enabled it.
+func (c *Connecter) setDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	if c.kube == nil || meta.WasDeleted(mg) || IsObserveOnly(mg) {
+		return nil
+	}
+	v, ok := nativeDeletionProtection(mg)
+	if !ok {
+		return nil
+	}
+	changed := awsclient.SetDeletionProtection(mg, v)
+	_, set := mg.GetAnnotations()[AnnotationKeyDeletionProtectionSet]
+	switch {
+	case v && changed:
+		meta.AddAnnotations(mg, map[string]string{AnnotationKeyDeletionProtectionSet: "true"})
+	case !v && set:
+		meta.RemoveAnnotations(mg, AnnotationKeyDeletionProtectionSet)
+	case !changed:
+		return nil
+	}
+	return errors.Wrap(c.kube.Update(ctx, mg), errPersistDeletionProtection)
+}
+
+// nativeDeletionProtection returns the value of the native deletion
+// protection flag of the supplied managed resource, and whether it should be
+// set at all. An absent deletion protection annotation disables the flag only
+// if the provider enabled it.
+func nativeDeletionProtection(mg resource.Managed) (bool, bool) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyDeletionProtection]
+	if !ok {
+		_, set := mg.GetAnnotations()[AnnotationKeyDeletionProtectionSet]
+		return false, set
+	}
+	b, err := strconv.ParseBool(v)
+	return b, err == nil
+}
+
+// A DeletionProtectionDisabler disables the native deletion protection of
+// the external resource of a managed resource, and nothing else, so that it
+// can be deleted. It is implemented by the external clients of the kinds
+// whose native deletion protection the provider can enable.
+type DeletionProtectionDisabler interface {
+	DisableDeletionProtection(ctx context.Context, mg resource.Managed) error
+}
+
+// clearDeletionProtection disables the native deletion protection flag that
+// the provider enabled on the external resource of the supplied managed
+// resource, so that AWS does not refuse to delete it once its deletion
+// protection annotation was removed.
+func (e *external) clearDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	d, ok := e.ExternalClient.(DeletionProtectionDisabler)
+	if !ok {
+		return nil
+	}
+	if _, set := mg.GetAnnotations()[AnnotationKeyDeletionProtectionSet]; !set || !awsclient.SetDeletionProtection(mg, false) {
+		return nil
+	}
+	return errors.Wrap(d.DisableDeletionProtection(ctx, mg), errClearDeletionProtection)
+}
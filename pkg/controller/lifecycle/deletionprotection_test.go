@@ -0,0 +1,321 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type protectableManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider struct {
+			DeletionProtection *bool `json:"deletionProtection,omitempty"`
+		}
+	}
+}
+
+func TestRefuseDeletionProtected(t *testing.T) {
+	type want struct {
+		err    error
+		calls  []string
+		cond   xpv1.Condition
+		events []event.Reason
+	}
+
+	cases := map[string]struct {
+		mg   *fake.Managed
+		want want
+	}{
+		"Protected": {
+			mg: withAnnotations(map[string]string{AnnotationKeyDeletionProtection: "true"}),
+			want: want{
+				err:    errors.New(errDeletionProtected),
+				cond:   DeletionProtectionCondition(),
+				events: []event.Reason{event.Reason(ReasonDeletionProtected)},
+			},
+		},
+		"Unprotected": {
+			mg: withAnnotations(map[string]string{AnnotationKeyDeletionProtection: "false"}),
+			want: want{
+				calls: []string{"delete"},
+				cond:  xpv1.Condition{Type: TypeDeletionProtection, Status: "Unknown"},
+			},
+		},
+		"NoAnnotation": {
+			mg: withAnnotations(nil),
+			want: want{
+				calls: []string{"delete"},
+				cond:  xpv1.Condition{Type: TypeDeletionProtection, Status: "Unknown"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := []string{}
+			rec := &eventRecorder{}
+			e, err := NewConnecter(recordingClient(managed.ExternalObservation{}, &calls), WithRecorder(rec)).Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if len(calls) == 0 {
+				calls = nil
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeDeletionProtection), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, rec.reasons); diff != "" {
+				t.Errorf("events: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetDeletionProtection(t *testing.T) {
+	errBoom := errors.New("boom")
+	enabled, disabled := true, false
+
+	newManaged := func(flag *bool, a map[string]string) *protectableManaged {
+		mg := &protectableManaged{}
+		mg.Spec.ForProvider.DeletionProtection = flag
+		meta.AddAnnotations(mg, a)
+		return mg
+	}
+
+	recorded := map[string]string{AnnotationKeyDeletionProtectionSet: "true"}
+
+	type want struct {
+		err     error
+		flag    *bool
+		set     bool
+		updated bool
+	}
+
+	cases := map[string]struct {
+		mg        *protectableManaged
+		updateErr error
+		want      want
+	}{
+		"Enabled": {
+			mg: newManaged(nil, map[string]string{AnnotationKeyDeletionProtection: "true"}),
+			want: want{
+				flag:    &enabled,
+				set:     true,
+				updated: true,
+			},
+		},
+		"Disabled": {
+			mg: newManaged(&enabled, map[string]string{AnnotationKeyDeletionProtection: "false"}),
+			want: want{
+				flag:    &disabled,
+				updated: true,
+			},
+		},
+		"AlreadySet": {
+			mg: newManaged(&enabled, map[string]string{AnnotationKeyDeletionProtection: "true"}),
+			want: want{
+				flag: &enabled,
+			},
+		},
+		"NoAnnotation": {
+			mg: newManaged(&enabled, nil),
+			want: want{
+				flag: &enabled,
+			},
+		},
+		"AnnotationRemoved": {
+			mg: newManaged(&enabled, recorded),
+			want: want{
+				flag:    &disabled,
+				updated: true,
+			},
+		},
+		"DisabledAfterEnabled": {
+			mg: newManaged(&enabled, map[string]string{
+				AnnotationKeyDeletionProtection:    "false",
+				AnnotationKeyDeletionProtectionSet: "true",
+			}),
+			want: want{
+				flag:    &disabled,
+				updated: true,
+			},
+		},
+		"ObserveOnly": {
+			mg: newManaged(nil, map[string]string{
+				AnnotationKeyDeletionProtection: "true",
+				AnnotationKeyManagementPolicy:   string(ManagementPolicyObserveOnly),
+			}),
+		},
+		"UpdateError": {
+			mg:        newManaged(nil, map[string]string{AnnotationKeyDeletionProtection: "true"}),
+			updateErr: errBoom,
+			want: want{
+				err:     errors.Wrap(errBoom, errPersistDeletionProtection),
+				flag:    &enabled,
+				set:     true,
+				updated: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return tc.updateErr
+				},
+			}
+			err := NewConnecter(recordingClient(managed.ExternalObservation{}, &[]string{}), WithKubeClient(kube)).setDeletionProtection(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("setDeletionProtection(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.flag, tc.mg.Spec.ForProvider.DeletionProtection); diff != "" {
+				t.Errorf("deletionProtection: -want, +got:\n%s", diff)
+			}
+			if _, set := tc.mg.GetAnnotations()[AnnotationKeyDeletionProtectionSet]; set != tc.want.set {
+				t.Errorf("%s annotation: want %t, got %t", AnnotationKeyDeletionProtectionSet, tc.want.set, set)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("updated: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// disablingClient is an external client that can disable the native deletion
+// protection of external resources.
+type disablingClient struct {
+	managed.ExternalClient
+	calls *[]string
+}
+
+func (c *disablingClient) DisableDeletionProtection(_ context.Context, _ resource.Managed) error {
+	*c.calls = append(*c.calls, "disableDeletionProtection")
+	return nil
+}
+
+func TestClearDeletionProtection(t *testing.T) {
+	enabled, disabled := true, false
+	now := metav1.Now()
+
+	newManaged := func(a map[string]string) *protectableManaged {
+		mg := &protectableManaged{}
+		mg.Spec.ForProvider.DeletionProtection = &enabled
+		meta.AddAnnotations(mg, a)
+		mg.SetDeletionTimestamp(&now)
+		return mg
+	}
+
+	type want struct {
+		err   error
+		calls []string
+		flag  *bool
+	}
+
+	cases := map[string]struct {
+		mg          *protectableManaged
+		unsupported bool
+		want        want
+	}{
+		"RemovedThenDeleted": {
+			mg: newManaged(map[string]string{AnnotationKeyDeletionProtectionSet: "true"}),
+			want: want{
+				calls: []string{"disableDeletionProtection", "delete"},
+				flag:  &disabled,
+			},
+		},
+		"Unsupported": {
+			mg:          newManaged(map[string]string{AnnotationKeyDeletionProtectionSet: "true"}),
+			unsupported: true,
+			want: want{
+				calls: []string{"delete"},
+				flag:  &enabled,
+			},
+		},
+		"StillProtected": {
+			mg: newManaged(map[string]string{
+				AnnotationKeyDeletionProtection:    "true",
+				AnnotationKeyDeletionProtectionSet: "true",
+			}),
+			want: want{
+				err:  errors.New(errDeletionProtected),
+				flag: &enabled,
+			},
+		},
+		"NotSetByProvider": {
+			mg: newManaged(nil),
+			want: want{
+				calls: []string{"delete"},
+				flag:  &enabled,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := []string{}
+			rc := recordingClient(managed.ExternalObservation{}, &calls)
+			c := managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+				ec, err := rc.Connect(ctx, mg)
+				if tc.unsupported {
+					return ec, err
+				}
+				return &disablingClient{ExternalClient: ec, calls: &calls}, err
+			})
+			e, err := NewConnecter(c).Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if len(calls) == 0 {
+				calls = nil
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.flag, tc.mg.Spec.ForProvider.DeletionProtection); diff != "" {
+				t.Errorf("deletionProtection: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// TypeDrift is the type of the condition that reports whether the external
+// resource of a managed resource differs from its desired state.
+const TypeDrift xpv1.ConditionType = "Drift"
+
+// Reasons of the drift condition.
+const (
+	ReasonDriftDetected xpv1.ConditionReason = "DriftDetected"
+	ReasonNoDrift       xpv1.ConditionReason = "NoDrift"
+)
+
+const (
+	msgDriftDetected = "The external resource differs from the desired state"
+	msgDriftFields   = "Fields differ from the desired state: "
+)
+
+// DriftCondition returns a drift condition with the supplied reason and
+// message.
+func DriftCondition(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	s := corev1.ConditionTrue
+	if r == ReasonNoDrift {
+		s = corev1.ConditionFalse
+	}
+	return xpv1.Condition{
+		Type:               TypeDrift,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// DriftMessage returns the message of the drift condition for the supplied
+// diff. The paths of the differing fields are listed if the diff is a JSON
+// merge patch, such as the one returned by awsclient.DiffJSON.
+func DriftMessage(diff string) string {
+	paths := awsclient.DiffPaths(diff)
+	if len(paths) == 0 {
+		return msgDriftDetected
+	}
+	return msgDriftFields + strings.Join(paths, ", ")
+}
+
+// observeDrift reports whether the existing external resource of the supplied
+// managed resource is up to date in the drift condition, and emits an event
+// when drift is detected. The external resource of a managed resource that
+// was never observed before, e.g. because it was just imported, is expected
+// to differ, so it is not emitted as an event. It must be called with the
+// observation of the controller, before any lifecycle policy adjusts it.
+func (e *external) observeDrift(mg resource.Managed, o managed.ExternalObservation) {
+	if !o.ResourceExists || meta.WasDeleted(mg) {
+		return
+	}
+	if o.ResourceUpToDate {
+		mg.SetConditions(DriftCondition(ReasonNoDrift, ""))
+		return
+	}
+	c := DriftCondition(ReasonDriftDetected, DriftMessage(o.Diff))
+	if mg.GetCondition(TypeDrift).Reason == "" {
+		mg.SetConditions(c)
+		return
+	}
+	e.report(mg, c)
+}
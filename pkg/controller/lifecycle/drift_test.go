@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+// upToDate returns the supplied managed resource after its external resource
+// was observed to be up to date.
+func upToDate(mg *fake.Managed) *fake.Managed {
+	mg.SetConditions(DriftCondition(ReasonNoDrift, ""))
+	return mg
+}
+
+func TestDrift(t *testing.T) {
+	type want struct {
+		cond   xpv1.Condition
+		events []event.Reason
+	}
+
+	cases := map[string]struct {
+		mg   *fake.Managed
+		obs  managed.ExternalObservation
+		want want
+	}{
+		"NotExists": {
+			mg:  &fake.Managed{},
+			obs: managed.ExternalObservation{},
+			want: want{
+				cond: xpv1.Condition{Type: TypeDrift, Status: "Unknown"},
+			},
+		},
+		"NoDrift": {
+			mg:  &fake.Managed{},
+			obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				cond: DriftCondition(ReasonNoDrift, ""),
+			},
+		},
+		"DriftDetected": {
+			mg:  upToDate(&fake.Managed{}),
+			obs: managed.ExternalObservation{ResourceExists: true, Diff: `{"size":2,"tags":{"team":"a"}}`},
+			want: want{
+				cond:   DriftCondition(ReasonDriftDetected, msgDriftFields+"size, tags.team"),
+				events: []event.Reason{event.Reason(ReasonDriftDetected)},
+			},
+		},
+		"FirstObservation": {
+			mg:  &fake.Managed{},
+			obs: managed.ExternalObservation{ResourceExists: true, Diff: `{"size":2}`},
+			want: want{
+				cond: DriftCondition(ReasonDriftDetected, msgDriftFields+"size"),
+			},
+		},
+		"DriftAlreadyReported": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(DriftCondition(ReasonDriftDetected, msgDriftDetected))
+				return mg
+			}(),
+			obs: managed.ExternalObservation{ResourceExists: true, Diff: "-size: 1\n+size: 2"},
+			want: want{
+				cond: DriftCondition(ReasonDriftDetected, msgDriftDetected),
+			},
+		},
+		"DryRun": {
+			mg:  upToDate(withAnnotations(map[string]string{AnnotationKeyDryRun: "true"})),
+			obs: managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				cond: DriftCondition(ReasonDriftDetected, msgDriftDetected),
+				events: []event.Reason{
+					event.Reason(ReasonDriftDetected),
+					event.Reason(ReasonPendingUpdate),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			ec, err := NewConnecter(recordingClient(tc.obs, &[]string{}), WithRecorder(rec)).Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ec.Observe(context.Background(), tc.mg); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeDrift), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, rec.reasons); diff != "" {
+				t.Errorf("events: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// AnnotationKeyDryRun is the key of the annotation that enables or disables
+// the dry-run mode of a managed resource, overriding the provider-wide
+// setting.
+const AnnotationKeyDryRun = "aws.crossplane.io/dry-run"
+
+// TypeDryRun is the type of the condition that reports the changes the
+// provider would make to the external resource of a managed resource in
+// dry-run mode.
+const TypeDryRun xpv1.ConditionType = "DryRun"
+
+// Reasons of the dry-run condition.
+const (
+	ReasonNoChanges     xpv1.ConditionReason = "NoChanges"
+	ReasonPendingCreate xpv1.ConditionReason = "PendingCreate"
+	ReasonPendingUpdate xpv1.ConditionReason = "PendingUpdate"
+	ReasonPendingDelete xpv1.ConditionReason = "PendingDelete"
+)
+
+const (
+	msgPendingCreate = "The external resource would be created"
+	msgPendingUpdate = "The external resource would be updated"
+	msgPendingDelete = "The external resource would be deleted"
+)
+
+// dryRun is the provider-wide dry-run setting.
+var dryRun bool
+
+// SetDryRun enables or disables the dry-run mode of every managed resource
+// that does not override it with its annotation.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// IsDryRun returns whether the supplied managed resource is in dry-run mode.
+func IsDryRun(mg resource.Managed) bool {
+	if v, err := strconv.ParseBool(mg.GetAnnotations()[AnnotationKeyDryRun]); err == nil {
+		return v
+	}
+	return dryRun
+}
+
+// DryRunCondition returns a dry-run condition with the supplied reason and
+// message.
+func DryRunCondition(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	s := corev1.ConditionTrue
+	if r == ReasonNoChanges {
+		s = corev1.ConditionFalse
+	}
+	return xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// observeDryRun adjusts the supplied observation of a managed resource in
+// dry-run mode so that the reconciler never creates or updates its external
+// resource. The changes that would have been made are reported in the
+// dry-run condition and in an event instead. The paths of the fields in the
+// diff of the observation are reported as the changes of an update.
+//
+// Once the deletion of a managed resource has been reported, its external
+// resource is observed as deleted so that the managed resource can be
+// deleted while its external resource is left alone.
+func (e *external) observeDryRun(mg resource.Managed, o managed.ExternalObservation) managed.ExternalObservation {
+	if !IsDryRun(mg) {
+		return o
+	}
+	if meta.WasDeleted(mg) {
+		if mg.GetCondition(TypeDryRun).Reason == ReasonPendingDelete {
+			return managed.ExternalObservation{}
+		}
+		return o
+	}
+	switch {
+	case !o.ResourceExists:
+		e.report(mg, DryRunCondition(ReasonPendingCreate, msgPendingCreate))
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	case !o.ResourceUpToDate:
+		msg := msgPendingUpdate
+		if paths := awsclient.DiffPaths(o.Diff); len(paths) != 0 {
+			msg += ": " + strings.Join(paths, ", ")
+		}
+		e.report(mg, DryRunCondition(ReasonPendingUpdate, msg))
+		o.ResourceUpToDate = true
+	default:
+		mg.SetConditions(DryRunCondition(ReasonNoChanges, ""))
+	}
+	return o
+}
+
+// deleteDryRun reports the deletion of the supplied managed resource if it is
+// in dry-run mode, and returns whether it is. The external resource of a
+// managed resource in dry-run mode must not be deleted.
+func (e *external) deleteDryRun(mg resource.Managed) bool {
+	if !IsDryRun(mg) {
+		return false
+	}
+	e.report(mg, DryRunCondition(ReasonPendingDelete, msgPendingDelete))
+	return true
+}
+
+// report sets the supplied condition and emits it as an event unless the
+// managed resource already reported the same condition.
+func (e *external) report(mg resource.Managed, c xpv1.Condition) {
+	if !mg.GetCondition(c.Type).Equal(c) {
+		e.record.Event(mg, event.Normal(event.Reason(c.Reason), c.Message))
+	}
+	mg.SetConditions(c)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// eventRecorder records the reasons of the events it is sent.
+type eventRecorder struct {
+	reasons []event.Reason
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.reasons = append(r.reasons, e.Reason)
+}
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestDryRun(t *testing.T) {
+	enabled := map[string]string{AnnotationKeyDryRun: "true"}
+	now := metav1.Now()
+
+	type want struct {
+		obs    managed.ExternalObservation
+		cond   xpv1.Condition
+		calls  []string
+		events []event.Reason
+	}
+
+	cases := map[string]struct {
+		mg     *fake.Managed
+		global bool
+		obs    managed.ExternalObservation
+		want   want
+	}{
+		"Disabled": {
+			mg:     withAnnotations(map[string]string{AnnotationKeyDryRun: "false"}),
+			global: true,
+			obs:    managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				obs:  managed.ExternalObservation{ResourceExists: true},
+				cond: xpv1.Condition{Type: TypeDryRun, Status: "Unknown"},
+			},
+		},
+		"PendingCreate": {
+			mg:  withAnnotations(enabled),
+			obs: managed.ExternalObservation{},
+			want: want{
+				obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond:   DryRunCondition(ReasonPendingCreate, msgPendingCreate),
+				events: []event.Reason{event.Reason(ReasonPendingCreate)},
+			},
+		},
+		"PendingUpdate": {
+			mg:     &fake.Managed{},
+			global: true,
+			obs:    managed.ExternalObservation{ResourceExists: true, Diff: `{"size":2}`},
+			want: want{
+				obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, Diff: `{"size":2}`},
+				cond:   DryRunCondition(ReasonPendingUpdate, msgPendingUpdate+": size"),
+				events: []event.Reason{event.Reason(ReasonPendingUpdate)},
+			},
+		},
+		"NoChanges": {
+			mg:  withAnnotations(enabled),
+			obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				obs:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				cond: DryRunCondition(ReasonNoChanges, ""),
+			},
+		},
+		"PendingDelete": {
+			mg: func() *fake.Managed {
+				mg := withAnnotations(enabled)
+				mg.SetDeletionTimestamp(&now)
+				return mg
+			}(),
+			obs: managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				obs:    managed.ExternalObservation{ResourceExists: true},
+				cond:   DryRunCondition(ReasonPendingDelete, msgPendingDelete),
+				events: []event.Reason{event.Reason(ReasonPendingDelete)},
+			},
+		},
+		"DeleteReported": {
+			mg: func() *fake.Managed {
+				mg := withAnnotations(enabled)
+				mg.SetDeletionTimestamp(&now)
+				mg.SetConditions(DryRunCondition(ReasonPendingDelete, msgPendingDelete))
+				return mg
+			}(),
+			obs: managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				obs:  managed.ExternalObservation{},
+				cond: DryRunCondition(ReasonPendingDelete, msgPendingDelete),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetDryRun(tc.global)
+			defer SetDryRun(false)
+
+			rec := &eventRecorder{}
+			calls := []string{}
+			ec, err := NewConnecter(recordingClient(tc.obs, &calls), WithRecorder(rec)).Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obs, err := ec.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if meta.WasDeleted(tc.mg) {
+				if err := ec.Delete(context.Background(), tc.mg); err != nil {
+					t.Errorf("Delete(...): %s", err)
+				}
+			}
+			if diff := cmp.Diff(tc.want.calls, calls, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeDryRun), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, rec.reasons); diff != "" {
+				t.Errorf("events: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
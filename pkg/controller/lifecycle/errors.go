@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// TypeExternalError is the type of the condition that reports the class of
+// the AWS error the external client of a managed resource last returned.
+const TypeExternalError xpv1.ConditionType = "ExternalError"
+
+// Reasons of the external error condition.
+const (
+	ReasonThrottled           xpv1.ConditionReason = "Throttled"
+	ReasonTransientError      xpv1.ConditionReason = "TransientError"
+	ReasonUnauthorized        xpv1.ConditionReason = "Unauthorized"
+	ReasonInvalidParameters   xpv1.ConditionReason = "InvalidParameters"
+	ReasonQuotaExceeded       xpv1.ConditionReason = "QuotaExceeded"
+	ReasonDependencyViolation xpv1.ConditionReason = "DependencyViolation"
+	ReasonNoExternalError     xpv1.ConditionReason = "NoExternalError"
+)
+
+// errorReasons are the reasons of the external error condition of each
+// class of AWS errors. Unclassified errors are not reported.
+var errorReasons = map[awsclient.ErrorClass]xpv1.ConditionReason{
+	awsclient.ErrorClassThrottling:          ReasonThrottled,
+	awsclient.ErrorClassTransient:           ReasonTransientError,
+	awsclient.ErrorClassAuthorization:       ReasonUnauthorized,
+	awsclient.ErrorClassValidation:          ReasonInvalidParameters,
+	awsclient.ErrorClassQuotaExceeded:       ReasonQuotaExceeded,
+	awsclient.ErrorClassDependencyViolation: ReasonDependencyViolation,
+}
+
+// errorRetries are how long a managed resource is not reconciled after its
+// external client returned an error of the reason, because retrying sooner
+// does not fix it. A change to the managed resource is still reconciled
+// right away. Errors of the other reasons are retried with a backoff.
+var errorRetries = map[xpv1.ConditionReason]time.Duration{
+	ReasonUnauthorized:      10 * time.Minute,
+	ReasonInvalidParameters: 10 * time.Minute,
+	ReasonQuotaExceeded:     5 * time.Minute,
+}
+
+// ExternalErrorCondition returns a condition that reports the supplied AWS
+// error, or that no AWS error was returned if it is not classified. Its
+// message omits the request ID of the error, so that the condition does not
+// change when the same error is returned again.
+func ExternalErrorCondition(err error) xpv1.Condition {
+	r, ok := errorReasons[awsclient.ClassifyError(err)]
+	if !ok {
+		return xpv1.Condition{
+			Type:               TypeExternalError,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonNoExternalError,
+		}
+	}
+	return xpv1.Condition{
+		Type:               TypeExternalError,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            awsclient.CleanError(err).Error(),
+	}
+}
+
+// reportError reports the class of the supplied error of the external
+// client in the external error condition of the supplied managed resource,
+// and returns the error without its request ID, like the condition. The
+// condition is only added once an AWS error is returned.
+func reportError(mg resource.Managed, err error) error {
+	c := ExternalErrorCondition(err)
+	if c.Status == corev1.ConditionTrue || mg.GetCondition(TypeExternalError).Status == corev1.ConditionTrue {
+		mg.SetConditions(c)
+	}
+	return awsclient.CleanError(err)
+}
+
+// ErrorRetryInterval returns how long the supplied managed resource should
+// not be reconciled after its last reconcile failed, and whether the error
+// it failed with is one that retrying sooner does not fix.
+func ErrorRetryInterval(mg resource.Managed) (time.Duration, bool) {
+	c := mg.GetCondition(TypeExternalError)
+	d, ok := errorRetries[c.Reason]
+	if !ok || c.Status != corev1.ConditionTrue {
+		return 0, false
+	}
+	// NOTE: the reconcile may have failed before the external client was
+	// called, e.g. while resolving references, in which case the external
+	// error condition is stale.
+	s := mg.GetCondition(xpv1.TypeSynced)
+	if s.Reason != xpv1.ReasonReconcileError || !strings.HasSuffix(s.Message, c.Message) {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+var errAccessDenied = &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"}
+
+// accessDenied returns errAccessDenied as returned by the AWS SDK with the
+// supplied request ID.
+func accessDenied(requestID string) error {
+	return fmt.Errorf("https response error StatusCode: 403, RequestID: %s, %w", requestID, errAccessDenied)
+}
+
+func withConditions(c ...xpv1.Condition) *fake.Managed {
+	mg := &fake.Managed{}
+	mg.SetConditions(c...)
+	return mg
+}
+
+func TestReportError(t *testing.T) {
+	errThrottled := &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"}
+	errBoom := errors.New("boom")
+
+	type want struct {
+		err  error
+		cond xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		mg   *fake.Managed
+		err  error
+		want want
+	}{
+		"NoError": {
+			mg: &fake.Managed{},
+			want: want{
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionUnknown},
+			},
+		},
+		"Unclassified": {
+			mg:  &fake.Managed{},
+			err: errBoom,
+			want: want{
+				err:  errBoom,
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionUnknown},
+			},
+		},
+		"Throttled": {
+			mg:  &fake.Managed{},
+			err: errThrottled,
+			want: want{
+				err:  errThrottled,
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionTrue, Reason: ReasonThrottled, Message: errThrottled.Error()},
+			},
+		},
+		"Unauthorized": {
+			mg:  withConditions(ExternalErrorCondition(errThrottled)),
+			err: errors.Wrap(errAccessDenied, "cannot describe"),
+			want: want{
+				err:  errors.Wrap(errAccessDenied, "cannot describe"),
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionTrue, Reason: ReasonUnauthorized, Message: "cannot describe: " + errAccessDenied.Error()},
+			},
+		},
+		"RequestID": {
+			mg:  &fake.Managed{},
+			err: accessDenied("abc"),
+			want: want{
+				err:  awsclient.CleanError(accessDenied("abc")),
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionTrue, Reason: ReasonUnauthorized, Message: "https response error StatusCode: 403, " + errAccessDenied.Error()},
+			},
+		},
+		"Recovered": {
+			mg: withConditions(ExternalErrorCondition(errAccessDenied)),
+			want: want{
+				cond: xpv1.Condition{Type: TypeExternalError, Status: corev1.ConditionFalse, Reason: ReasonNoExternalError},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, tc.err
+					},
+				}, nil
+			}))
+			ec, err := c.Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = ec.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeExternalError), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorRetryInterval(t *testing.T) {
+	errQuota := &smithy.GenericAPIError{Code: "VpcLimitExceeded", Message: "too many VPCs"}
+	errThrottled := &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"}
+
+	type want struct {
+		d  time.Duration
+		ok bool
+	}
+
+	cases := map[string]struct {
+		mg   resource.Managed
+		want want
+	}{
+		"NoCondition": {
+			mg: &fake.Managed{},
+		},
+		"Unauthorized": {
+			mg: withConditions(
+				ExternalErrorCondition(errAccessDenied),
+				xpv1.ReconcileError(errors.Wrap(errAccessDenied, "observe failed")),
+			),
+			want: want{d: 10 * time.Minute, ok: true},
+		},
+		"QuotaExceeded": {
+			mg: withConditions(
+				ExternalErrorCondition(errQuota),
+				xpv1.ReconcileError(errors.Wrap(errQuota, "create failed")),
+			),
+			want: want{d: 5 * time.Minute, ok: true},
+		},
+		"Throttled": {
+			mg: withConditions(
+				ExternalErrorCondition(errThrottled),
+				xpv1.ReconcileError(errors.Wrap(errThrottled, "observe failed")),
+			),
+		},
+		"Stale": {
+			mg: withConditions(
+				ExternalErrorCondition(errAccessDenied),
+				xpv1.ReconcileError(errors.New("cannot resolve references")),
+			),
+		},
+		"Synced": {
+			mg: withConditions(
+				ExternalErrorCondition(errAccessDenied),
+				xpv1.ReconcileSuccess(),
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, ok := ErrorRetryInterval(tc.mg)
+			if diff := cmp.Diff(tc.want, want{d: d, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("ErrorRetryInterval(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorNotRetriedRightAway(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(fakeKind, &fake.Managed{})
+
+	stored := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Generation: 1}}
+	var updates []event.UpdateEvent
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			*obj.(*fake.Managed) = *stored.DeepCopyObject().(*fake.Managed)
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			updated := obj.DeepCopyObject().(*fake.Managed)
+			updates = append(updates, event.UpdateEvent{ObjectOld: stored, ObjectNew: updated})
+			stored = updated
+			return nil
+		},
+	}
+
+	// AWS returns the same error with a new request ID every time.
+	calls := 0
+	c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				calls++
+				return managed.ExternalObservation{}, accessDenied(fmt.Sprint(calls))
+			},
+		}, nil
+	}))
+	mr := managed.NewReconciler(&fake.Manager{Client: kube, Scheme: s}, resource.ManagedKind(fakeKind),
+		managed.WithExternalConnecter(c),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })))
+	r := NewPollReconciler(kube, s, resource.ManagedKind(fakeKind), mr)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}
+
+	got, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile(...): %s", err)
+	}
+	if diff := cmp.Diff(reconcile.Result{RequeueAfter: errorRetries[ReasonUnauthorized]}, got); diff != "" {
+		t.Errorf("Reconcile(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, len(updates)); diff != "" {
+		t.Fatalf("Status().Update(...): -want calls, +got calls:\n%s", diff)
+	}
+
+	// The managed resource is reconciled again right away if the status
+	// update of the failed reconcile is queued.
+	if EventFilter().Update(updates[0]) {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(1, calls); diff != "" {
+		t.Errorf("Observe(...): -want calls, +got calls:\n%s", diff)
+	}
+}
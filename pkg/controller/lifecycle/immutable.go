@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cachev1beta1 "github.com/crossplane/provider-aws/apis/cache/v1beta1"
+	databasev1beta1 "github.com/crossplane/provider-aws/apis/database/v1beta1"
+	dynamodbv1alpha1 "github.com/crossplane/provider-aws/apis/dynamodb/v1alpha1"
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+	eksv1alpha1 "github.com/crossplane/provider-aws/apis/eks/v1alpha1"
+	eksv1beta1 "github.com/crossplane/provider-aws/apis/eks/v1beta1"
+	identityv1alpha1 "github.com/crossplane/provider-aws/apis/identity/v1alpha1"
+	identityv1beta1 "github.com/crossplane/provider-aws/apis/identity/v1beta1"
+	kmsv1alpha1 "github.com/crossplane/provider-aws/apis/kms/v1alpha1"
+	rdsv1alpha1 "github.com/crossplane/provider-aws/apis/rds/v1alpha1"
+	s3v1beta1 "github.com/crossplane/provider-aws/apis/s3/v1beta1"
+	sqsv1beta1 "github.com/crossplane/provider-aws/apis/sqs/v1beta1"
+)
+
+// forProvider is the path of the parameters of a managed resource.
+const forProvider = "spec.forProvider."
+
+// ImmutableFields are the paths, relative to spec.forProvider, of the fields
+// of each kind that AWS does not allow to be changed after the external
+// resource was created.
+var ImmutableFields = map[schema.GroupKind][]string{
+	{Group: cachev1beta1.Group, Kind: cachev1beta1.ReplicationGroupKind}:  {"engine", "atRestEncryptionEnabled", "cacheSubnetGroupName"},
+	{Group: databasev1beta1.Group, Kind: databasev1beta1.RDSInstanceKind}: {"engine", "masterUsername", "dbName", "storageEncrypted", "kmsKeyId"},
+	{Group: dynamodbv1alpha1.Group, Kind: dynamodbv1alpha1.TableKind}:     {"keySchema", "localSecondaryIndexes"},
+	{Group: ec2v1beta1.Group, Kind: ec2v1beta1.SecurityGroupKind}:         {"groupName", "description", "vpcId"},
+	{Group: ec2v1beta1.Group, Kind: ec2v1beta1.SubnetKind}:                {"cidrBlock", "availabilityZone", "vpcId"},
+	{Group: ec2v1beta1.Group, Kind: ec2v1beta1.VPCKind}:                   {"cidrBlock"},
+	{Group: eksv1beta1.Group, Kind: eksv1beta1.ClusterKind}:               {"resourcesVpcConfig.subnetIds", "resourcesVpcConfig.securityGroupIds"},
+	{Group: eksv1alpha1.Group, Kind: eksv1alpha1.NodeGroupKind}:           {"amiType", "diskSize", "instanceTypes", "subnets"},
+	{Group: identityv1alpha1.Group, Kind: identityv1alpha1.IAMPolicyKind}: {"path"},
+	{Group: identityv1beta1.Group, Kind: identityv1beta1.IAMRoleKind}:     {"path"},
+	{Group: kmsv1alpha1.Group, Kind: kmsv1alpha1.KeyKind}:                 {"keyUsage", "customerMasterKeySpec"},
+	{Group: rdsv1alpha1.Group, Kind: rdsv1alpha1.DBClusterKind}:           {"engine", "masterUsername", "databaseName", "storageEncrypted", "kmsKeyID"},
+	{Group: s3v1beta1.Group, Kind: s3v1beta1.BucketKind}:                  {"locationConstraint"},
+	{Group: sqsv1beta1.Group, Kind: sqsv1beta1.QueueKind}:                 {"fifoQueue"},
+}
+
+// ChangedImmutableFields returns the paths of the immutable fields of the
+// supplied kind whose values differ between the supplied previous and
+// desired object contents. Fields that are unset in either are not
+// considered changed, since unset fields are late-initialized from the
+// external resource.
+func ChangedImmutableFields(gk schema.GroupKind, previous, desired map[string]interface{}) []string {
+	var changed []string
+	prev, des := fieldpath.Pave(previous), fieldpath.Pave(desired)
+	for _, f := range ImmutableFields[gk] {
+		p, err := prev.GetValue(forProvider + f)
+		if err != nil || p == nil {
+			continue
+		}
+		d, err := des.GetValue(forProvider + f)
+		if err != nil || d == nil {
+			continue
+		}
+		if !cmp.Equal(p, d) {
+			changed = append(changed, forProvider+f)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+	eksv1beta1 "github.com/crossplane/provider-aws/apis/eks/v1beta1"
+)
+
+func forProviderOf(params map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"spec": map[string]interface{}{"forProvider": params}}
+}
+
+func TestChangedImmutableFields(t *testing.T) {
+	sg := schema.GroupKind{Group: ec2v1beta1.Group, Kind: ec2v1beta1.SecurityGroupKind}
+	cluster := schema.GroupKind{Group: eksv1beta1.Group, Kind: eksv1beta1.ClusterKind}
+
+	cases := map[string]struct {
+		gk       schema.GroupKind
+		previous map[string]interface{}
+		desired  map[string]interface{}
+		want     []string
+	}{
+		"Unchanged": {
+			gk:       sg,
+			previous: forProviderOf(map[string]interface{}{"groupName": "web", "vpcId": "vpc-1"}),
+			desired:  forProviderOf(map[string]interface{}{"groupName": "web", "vpcId": "vpc-1"}),
+		},
+		"MutableFieldChanged": {
+			gk:       sg,
+			previous: forProviderOf(map[string]interface{}{"groupName": "web", "tags": []interface{}{"a"}}),
+			desired:  forProviderOf(map[string]interface{}{"groupName": "web", "tags": []interface{}{"b"}}),
+		},
+		"LateInitialized": {
+			gk:       sg,
+			previous: forProviderOf(map[string]interface{}{"groupName": "web"}),
+			desired:  forProviderOf(map[string]interface{}{"groupName": "web", "vpcId": "vpc-1"}),
+		},
+		"Changed": {
+			gk:       sg,
+			previous: forProviderOf(map[string]interface{}{"groupName": "web", "vpcId": "vpc-1"}),
+			desired:  forProviderOf(map[string]interface{}{"groupName": "api", "vpcId": "vpc-2"}),
+			want:     []string{"spec.forProvider.groupName", "spec.forProvider.vpcId"},
+		},
+		"NestedChanged": {
+			gk: cluster,
+			previous: forProviderOf(map[string]interface{}{
+				"resourcesVpcConfig": map[string]interface{}{"subnetIds": []interface{}{"subnet-1", "subnet-2"}},
+			}),
+			desired: forProviderOf(map[string]interface{}{
+				"resourcesVpcConfig": map[string]interface{}{"subnetIds": []interface{}{"subnet-1", "subnet-3"}},
+			}),
+			want: []string{"spec.forProvider.resourcesVpcConfig.subnetIds"},
+		},
+		"UnknownKind": {
+			gk:       schema.GroupKind{Group: ec2v1beta1.Group, Kind: ec2v1beta1.AddressKind},
+			previous: forProviderOf(map[string]interface{}{"domain": "vpc"}),
+			desired:  forProviderOf(map[string]interface{}{"domain": "standard"}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ChangedImmutableFields(tc.gk, tc.previous, tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ChangedImmutableFields(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
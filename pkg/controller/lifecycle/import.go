@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+const (
+	// AnnotationKeyImportFrom is the key of the annotation that holds the ARN
+	// of the existing external resource a managed resource is imported from.
+	// The spec of the managed resource is late-initialized by its controller,
+	// which fills every field of forProvider that AWS returns for the
+	// hand-written kinds but only some of them for most generated kinds. The
+	// fields left unset are listed in a warning event, as an update sets
+	// them to their spec values once they are specified.
+	AnnotationKeyImportFrom = "aws.crossplane.io/import-from"
+
+	// AnnotationKeyImportedFrom is the key of the annotation that records the
+	// ARN a managed resource was imported from once the import succeeded.
+	AnnotationKeyImportedFrom = "aws.crossplane.io/imported-from"
+)
+
+const (
+	errImport         = "cannot import external resource"
+	errImportNotFound = "cannot import external resource %s: it does not exist"
+	errImportCreate   = "refusing to create an external resource while importing %s"
+
+	reasonImported          event.Reason = "ImportedExternalResource"
+	reasonImportedPartially event.Reason = "ImportedExternalResourcePartially"
+)
+
+// An ExternalNameFn derives the external name of a managed resource from the
+// ARN of the external resource it is imported from.
+type ExternalNameFn func(a awsclient.ARN) string
+
+// ResourceID uses the resource ID of the ARN, such as the name of a bucket,
+// the name of a queue or the ID of a VPC, as the external name.
+func ResourceID(a awsclient.ARN) string {
+	return a.ResourceID
+}
+
+// ResourceName uses the last segment of the resource ID of the ARN as the
+// external name. It suits the kinds whose ARN contains a path, such as the
+// IAM roles and users.
+func ResourceName(a awsclient.ARN) string {
+	return a.ResourceName()
+}
+
+// FullARN uses the ARN itself as the external name.
+func FullARN(a awsclient.ARN) string {
+	return a.String()
+}
+
+// Segment uses the i-th "/" separated segment of the resource ID of the ARN
+// as the external name, such as the name of an EKS node group in
+// nodegroup/cluster/name/uuid.
+func Segment(i int) ExternalNameFn {
+	return func(a awsclient.ARN) string {
+		s := strings.Split(a.ResourceID, "/")
+		if i >= len(s) {
+			return a.ResourceID
+		}
+		return s[i]
+	}
+}
+
+// importPending returns the ARN the supplied managed resource should be
+// imported from, if it was not imported from it yet.
+func importPending(mg resource.Managed) (string, bool) {
+	a := mg.GetAnnotations()
+	from := a[AnnotationKeyImportFrom]
+	if from == "" || a[AnnotationKeyImportedFrom] == from {
+		return "", false
+	}
+	return from, true
+}
+
+// setImportExternalName sets the external name of the supplied managed
+// resource to the one derived from the ARN it is imported from. The external
+// name is persisted together with the late-initialized spec once the external
+// resource is observed.
+func (c *Connecter) setImportExternalName(mg resource.Managed) error {
+	from, ok := importPending(mg)
+	if !ok {
+		return nil
+	}
+	a, err := awsclient.ParseARN(from)
+	if err != nil {
+		return errors.Wrap(err, errImport)
+	}
+	meta.SetExternalName(mg, c.externalName(a))
+	return nil
+}
+
+// observeImport completes the import of the supplied managed resource. The
+// external resource must exist; it is never created. Its spec is reported as
+// late-initialized and up to date so that the reconciler persists the state
+// observed from AWS before any update is allowed to happen.
+func (e *external) observeImport(mg resource.Managed, o managed.ExternalObservation) (managed.ExternalObservation, error) {
+	from, ok := importPending(mg)
+	if !ok || meta.WasDeleted(mg) {
+		return o, nil
+	}
+	if !o.ResourceExists {
+		return o, errors.Errorf(errImportNotFound, from)
+	}
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyImportedFrom: from})
+	if unset := unsetParameters(mg); len(unset) > 0 {
+		e.record.Event(mg, event.Warning(reasonImportedPartially, errors.Errorf("Imported external resource %s without late-initializing spec.forProvider fields %s", from, strings.Join(unset, ", "))))
+	} else {
+		e.record.Event(mg, event.Normal(reasonImported, "Imported external resource "+from))
+	}
+	o.ResourceLateInitialized = true
+	o.ResourceUpToDate = true
+	return o, nil
+}
+
+// refuseImportCreate returns an error if the supplied managed resource is
+// still being imported.
+func refuseImportCreate(mg resource.Managed) error {
+	if from, ok := importPending(mg); ok {
+		return errors.Errorf(errImportCreate, from)
+	}
+	return nil
+}
+
+// unsetParameters returns the JSON names of the fields of the forProvider
+// parameters of the supplied managed resource that are not set. References,
+// selectors and the region are not settings of the external resource, so
+// they are not reported.
+func unsetParameters(mg resource.Managed) []string {
+	v := reflect.Indirect(reflect.ValueOf(mg))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	spec := v.FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return nil
+	}
+	fp := spec.FieldByName("ForProvider")
+	if !fp.IsValid() || fp.Kind() != reflect.Struct {
+		return nil
+	}
+	res := unsetFields(fp, nil)
+	sort.Strings(res)
+	return res
+}
+
+func unsetFields(v reflect.Value, res []string) []string {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" {
+			if fv := reflect.Indirect(v.Field(i)); fv.Kind() == reflect.Struct {
+				res = unsetFields(fv, res)
+			}
+			continue
+		}
+		if name == "" || name == "-" || name == "region" ||
+			strings.HasSuffix(name, "Ref") || strings.HasSuffix(name, "Refs") || strings.HasSuffix(name, "Selector") {
+			continue
+		}
+		if v.Field(i).IsZero() {
+			res = append(res, name)
+		}
+	}
+	return res
+}
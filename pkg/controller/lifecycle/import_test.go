@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+const (
+	roleARN  = "arn:aws:iam::123456789012:role/team/my-role"
+	roleName = "my-role"
+)
+
+func withAnnotations(a map[string]string) *fake.Managed {
+	mg := &fake.Managed{}
+	meta.AddAnnotations(mg, a)
+	return mg
+}
+
+func newConnecter(exists bool) *Connecter {
+	return NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{ResourceExists: exists}, nil
+			},
+			CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+				return managed.ExternalCreation{}, nil
+			},
+		}, nil
+	}), WithExternalName(ResourceName))
+}
+
+func TestImport(t *testing.T) {
+	type want struct {
+		externalName string
+		importedFrom string
+		obs          managed.ExternalObservation
+		connectErr   error
+		observeErr   error
+		createErr    error
+	}
+
+	cases := map[string]struct {
+		mg     *fake.Managed
+		exists bool
+		want   want
+	}{
+		"NotImported": {
+			mg:     withAnnotations(map[string]string{meta.AnnotationKeyExternalName: "name"}),
+			exists: true,
+			want: want{
+				externalName: "name",
+				obs:          managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"Imported": {
+			mg:     withAnnotations(map[string]string{meta.AnnotationKeyExternalName: "name", AnnotationKeyImportFrom: roleARN}),
+			exists: true,
+			want: want{
+				externalName: roleName,
+				importedFrom: roleARN,
+				obs:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"AlreadyImported": {
+			mg:     withAnnotations(map[string]string{meta.AnnotationKeyExternalName: roleName, AnnotationKeyImportFrom: roleARN, AnnotationKeyImportedFrom: roleARN}),
+			exists: true,
+			want: want{
+				externalName: roleName,
+				importedFrom: roleARN,
+				obs:          managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"NotFound": {
+			mg: withAnnotations(map[string]string{AnnotationKeyImportFrom: roleARN}),
+			want: want{
+				externalName: roleName,
+				observeErr:   errors.Errorf(errImportNotFound, roleARN),
+				createErr:    errors.Errorf(errImportCreate, roleARN),
+			},
+		},
+		"InvalidARN": {
+			mg: withAnnotations(map[string]string{AnnotationKeyImportFrom: "my-role"}),
+			want: want{
+				connectErr: errors.Wrap(errors.New(`cannot parse ARN "my-role": arn: invalid prefix`), errImport),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ec, err := newConnecter(tc.exists).Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.connectErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Connect(...): -want error, +got error:\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			obs, err := ec.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.observeErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			_, err = ec.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.createErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Create(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("external name: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.importedFrom, tc.mg.GetAnnotations()[AnnotationKeyImportedFrom]); diff != "" {
+				t.Errorf("imported from: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnsetParameters(t *testing.T) {
+	cases := map[string]struct {
+		mg   resource.Managed
+		want []string
+	}{
+		"NoParameters": {
+			mg: &fake.Managed{},
+		},
+		"Unset": {
+			mg: &ec2v1beta1.VPC{Spec: ec2v1beta1.VPCSpec{ForProvider: ec2v1beta1.VPCParameters{
+				Region:    awsclient.String("us-east-1"),
+				CIDRBlock: "10.0.0.0/16",
+			}}},
+			want: []string{"enableDnsHostNames", "enableDnsSupport", "instanceTenancy", "tags"},
+		},
+		"FullyLateInitialized": {
+			mg: &ec2v1beta1.VPC{Spec: ec2v1beta1.VPCSpec{ForProvider: ec2v1beta1.VPCParameters{
+				CIDRBlock:          "10.0.0.0/16",
+				EnableDNSSupport:   awsclient.Bool(true),
+				EnableDNSHostNames: awsclient.Bool(false, awsclient.FieldRequired),
+				InstanceTenancy:    awsclient.String("default"),
+				Tags:               []ec2v1beta1.Tag{{Key: "k", Value: "v"}},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := unsetParameters(tc.mg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unsetParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
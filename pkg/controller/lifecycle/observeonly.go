@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeyManagementPolicy is the key of the annotation that holds the
+// management policy of a managed resource.
+const AnnotationKeyManagementPolicy = "aws.crossplane.io/management-policy"
+
+// A ManagementPolicy determines which operations the provider may perform on
+// the external resource of a managed resource.
+type ManagementPolicy string
+
+// Management policies.
+const (
+	// ManagementPolicyDefault allows the provider to create, update and
+	// delete the external resource.
+	ManagementPolicyDefault ManagementPolicy = "Default"
+
+	// ManagementPolicyObserveOnly allows the provider to observe the external
+	// resource, and to publish its connection details, but never to create,
+	// update or delete it.
+	ManagementPolicyObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
+const (
+	errObserveOnlyNotFound = "observe-only external resource does not exist"
+	errObserveOnly         = "refusing to %s an observe-only external resource"
+)
+
+// GetManagementPolicy returns the management policy of the supplied managed
+// resource.
+func GetManagementPolicy(mg resource.Managed) ManagementPolicy {
+	if p := ManagementPolicy(mg.GetAnnotations()[AnnotationKeyManagementPolicy]); p != "" {
+		return p
+	}
+	return ManagementPolicyDefault
+}
+
+// IsObserveOnly returns whether the supplied managed resource is observe-only.
+func IsObserveOnly(mg resource.Managed) bool {
+	return GetManagementPolicy(mg) == ManagementPolicyObserveOnly
+}
+
+// observeOnly adjusts the supplied observation of an observe-only managed
+// resource so that the reconciler never creates, updates or deletes its
+// external resource. A missing external resource is reported as an error
+// rather than created. A deleted managed resource is reported as having no
+// external resource, so that it is finalized while the external resource is
+// left intact.
+func observeOnly(mg resource.Managed, o managed.ExternalObservation) (managed.ExternalObservation, error) {
+	if !IsObserveOnly(mg) {
+		return o, nil
+	}
+	if meta.WasDeleted(mg) {
+		o.ResourceExists = false
+		return o, nil
+	}
+	if !o.ResourceExists {
+		mg.SetConditions(xpv1.Unavailable().WithMessage(errObserveOnlyNotFound))
+		return o, errors.New(errObserveOnlyNotFound)
+	}
+	o.ResourceUpToDate = true
+	return o, nil
+}
+
+// refuseObserveOnly returns an error if the supplied managed resource is
+// observe-only.
+func refuseObserveOnly(mg resource.Managed, op string) error {
+	if IsObserveOnly(mg) {
+		return errors.Errorf(errObserveOnly, op)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordingClient returns an ExternalClient that observes the supplied
+// observation and records the mutating calls made to it.
+func recordingClient(o managed.ExternalObservation, calls *[]string) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				return o, nil
+			},
+			CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+				*calls = append(*calls, "create")
+				return managed.ExternalCreation{}, nil
+			},
+			UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+				*calls = append(*calls, "update")
+				return managed.ExternalUpdate{}, nil
+			},
+			DeleteFn: func(_ context.Context, _ resource.Managed) error {
+				*calls = append(*calls, "delete")
+				return nil
+			},
+		}, nil
+	})
+}
+
+func TestObserveOnly(t *testing.T) {
+	observeOnly := map[string]string{AnnotationKeyManagementPolicy: string(ManagementPolicyObserveOnly)}
+	now := metav1.Now()
+	details := managed.ConnectionDetails{"endpoint": []byte("example.com")}
+	unknown := xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown}
+
+	type want struct {
+		obs   managed.ExternalObservation
+		err   error
+		cond  xpv1.Condition
+		calls []string
+	}
+
+	cases := map[string]struct {
+		mg   *fake.Managed
+		obs  managed.ExternalObservation
+		want want
+	}{
+		"Default": {
+			mg:  &fake.Managed{},
+			obs: managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				obs:   managed.ExternalObservation{ResourceExists: true},
+				cond:  unknown,
+				calls: []string{"create", "update", "delete"},
+			},
+		},
+		"Exists": {
+			mg:  withAnnotations(observeOnly),
+			obs: managed.ExternalObservation{ResourceExists: true, ConnectionDetails: details},
+			want: want{
+				obs:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: details},
+				cond: unknown,
+			},
+		},
+		"NotFound": {
+			mg:  withAnnotations(observeOnly),
+			obs: managed.ExternalObservation{},
+			want: want{
+				err:  errors.New(errObserveOnlyNotFound),
+				cond: xpv1.Unavailable().WithMessage(errObserveOnlyNotFound),
+			},
+		},
+		"Deleted": {
+			mg: func() *fake.Managed {
+				mg := withAnnotations(observeOnly)
+				mg.SetDeletionTimestamp(&now)
+				return mg
+			}(),
+			obs: managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				obs:  managed.ExternalObservation{},
+				cond: unknown,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			ec, err := NewConnecter(recordingClient(tc.obs, &calls)).Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			obs, err := ec.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+			_, _ = ec.Create(context.Background(), tc.mg)
+			_, _ = ec.Update(context.Background(), tc.mg)
+			_ = ec.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("calls: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// AnnotationKeyPollInterval is the key of the annotation that overrides how
+// often the external resource of a managed resource is checked for drift,
+// e.g. 10m. It takes precedence over the poll interval of its kind.
+const AnnotationKeyPollInterval = "aws.crossplane.io/poll-interval"
+
+const (
+	errParsePollInterval   = "cannot parse the poll interval of %s"
+	errReadPollIntervals   = "cannot read poll intervals"
+	errDecodePollIntervals = "cannot decode poll intervals"
+)
+
+// pollIntervals are the provider-wide poll intervals of managed resource
+// kinds, keyed by Kind or Kind.group in lower case.
+var pollIntervals map[string]time.Duration
+
+// SetPollIntervals sets the poll intervals of the managed resources of the
+// supplied kinds that do not override it with their annotation. The kinds
+// are either a Kind, e.g. IAMPolicy, or a Kind qualified with its group,
+// e.g. Cluster.eks.aws.crossplane.io, which takes precedence.
+func SetPollIntervals(intervals map[string]time.Duration) {
+	pollIntervals = make(map[string]time.Duration, len(intervals))
+	for k, d := range intervals {
+		pollIntervals[strings.ToLower(k)] = d
+	}
+}
+
+// ReadPollIntervals reads the durations keyed by kind from the supplied YAML
+// file, e.g. a mounted ConfigMap:
+//
+//	IAMPolicy: 1h
+//	Table.dynamodb.aws.crossplane.io: 30s
+func ReadPollIntervals(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, errReadPollIntervals)
+	}
+	res := map[string]string{}
+	return res, errors.Wrap(yaml.Unmarshal(b, &res), errDecodePollIntervals)
+}
+
+// ParsePollIntervals parses the supplied durations keyed by kind, as
+// accepted by SetPollIntervals.
+func ParsePollIntervals(intervals map[string]string) (map[string]time.Duration, error) {
+	res := make(map[string]time.Duration, len(intervals))
+	for k, v := range intervals {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errParsePollInterval, k)
+		}
+		if d <= 0 {
+			return nil, errors.Errorf(errParsePollInterval+": must be positive", k)
+		}
+		res[k] = d
+	}
+	return res, nil
+}
+
+// PollInterval returns how often the external resource of the supplied
+// managed resource of the supplied kind is checked for drift, falling back
+// to the supplied poll interval.
+func PollInterval(mg resource.Managed, gk schema.GroupKind, poll time.Duration) time.Duration {
+	if d, err := time.ParseDuration(mg.GetAnnotations()[AnnotationKeyPollInterval]); err == nil && d > 0 {
+		return d
+	}
+	if d, ok := pollIntervals[strings.ToLower(gk.Kind+"."+gk.Group)]; ok {
+		return d
+	}
+	if d, ok := pollIntervals[strings.ToLower(gk.Kind)]; ok {
+		return d
+	}
+	return poll
+}
+
+// A PollReconciler requeues the managed resources reconciled by the
+// Reconciler it wraps after their poll interval, rather than the one of the
+// wrapped Reconciler, and those whose reconcile failed with an AWS error that
+// retrying does not fix after the retry interval of the error, rather than
+// with a backoff.
+type PollReconciler struct {
+	reconcile.Reconciler
+
+	kube   client.Reader
+	scheme *runtime.Scheme
+	of     resource.ManagedKind
+}
+
+// NewPollReconciler returns a PollReconciler that wraps the supplied
+// Reconciler of the managed resources of the supplied kind.
+func NewPollReconciler(kube client.Reader, s *runtime.Scheme, of resource.ManagedKind, r reconcile.Reconciler) *PollReconciler {
+	return &PollReconciler{Reconciler: r, kube: kube, scheme: s, of: of}
+}
+
+// NewReconciler returns a managed resource Reconciler of the supplied kind
+// that honors the poll intervals and the shard of the provider.
+func NewReconciler(mgr ctrl.Manager, of resource.ManagedKind, o ...managed.ReconcilerOption) reconcile.Reconciler {
+	r := NewPollReconciler(mgr.GetClient(), mgr.GetScheme(), of, managed.NewReconciler(mgr, of, o...))
+	return NewShardReconciler(mgr.GetClient(), mgr.GetScheme(), of, r)
+}
+
+// Reconcile the supplied managed resource with the wrapped Reconciler. The
+// managed reconciler only requeues after a delay once the external resource
+// is up to date, which is when the poll interval applies, and requeues with
+// a backoff when the reconcile failed.
+func (r *PollReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.Reconciler.Reconcile(ctx, req)
+	if err != nil || (res.RequeueAfter == 0 && !res.Requeue) {
+		return res, err
+	}
+	o, err := r.scheme.New(schema.GroupVersionKind(r.of))
+	if err != nil {
+		return res, nil
+	}
+	mg, ok := o.(resource.Managed)
+	if !ok {
+		return res, nil
+	}
+	// NOTE: the resource was just read by the wrapped Reconciler, so it is
+	// in the cache unless it was deleted meanwhile.
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		return res, nil
+	}
+	if res.RequeueAfter == 0 {
+		if d, ok := ErrorRetryInterval(mg); ok {
+			return reconcile.Result{RequeueAfter: d}, nil
+		}
+		return res, nil
+	}
+	res.RequeueAfter = PollInterval(mg, schema.GroupVersionKind(r.of).GroupKind(), res.RequeueAfter)
+	return res, nil
+}
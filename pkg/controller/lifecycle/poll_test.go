@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var fakeKind = schema.GroupVersionKind{Group: "eks.aws.crossplane.io", Version: "v1beta1", Kind: "Cluster"}
+
+type reconcilerFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcilerFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestPollInterval(t *testing.T) {
+	gk := fakeKind.GroupKind()
+
+	cases := map[string]struct {
+		intervals map[string]time.Duration
+		mg        resource.Managed
+		want      time.Duration
+	}{
+		"Default": {
+			mg:   &fake.Managed{},
+			want: time.Minute,
+		},
+		"Kind": {
+			intervals: map[string]time.Duration{"cluster": time.Hour},
+			mg:        &fake.Managed{},
+			want:      time.Hour,
+		},
+		"QualifiedKind": {
+			intervals: map[string]time.Duration{"Cluster": time.Hour, "Cluster.eks.aws.crossplane.io": 30 * time.Second},
+			mg:        &fake.Managed{},
+			want:      30 * time.Second,
+		},
+		"OtherKind": {
+			intervals: map[string]time.Duration{"Cluster.redshift.aws.crossplane.io": time.Hour},
+			mg:        &fake.Managed{},
+			want:      time.Minute,
+		},
+		"Annotation": {
+			intervals: map[string]time.Duration{"Cluster": time.Hour},
+			mg:        withAnnotations(map[string]string{AnnotationKeyPollInterval: "5m"}),
+			want:      5 * time.Minute,
+		},
+		"InvalidAnnotation": {
+			intervals: map[string]time.Duration{"Cluster": time.Hour},
+			mg:        withAnnotations(map[string]string{AnnotationKeyPollInterval: "often"}),
+			want:      time.Hour,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetPollIntervals(tc.intervals)
+			defer SetPollIntervals(nil)
+
+			got := PollInterval(tc.mg, gk, time.Minute)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PollInterval(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePollIntervals(t *testing.T) {
+	type want struct {
+		intervals map[string]time.Duration
+		err       error
+	}
+
+	cases := map[string]struct {
+		intervals map[string]string
+		want      want
+	}{
+		"Valid": {
+			intervals: map[string]string{"IAMPolicy": "1h"},
+			want:      want{intervals: map[string]time.Duration{"IAMPolicy": time.Hour}},
+		},
+		"NotPositive": {
+			intervals: map[string]string{"IAMPolicy": "0s"},
+			want:      want{err: errors.Errorf(errParsePollInterval+": must be positive", "IAMPolicy")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePollIntervals(tc.intervals)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ParsePollIntervals(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.intervals, got); diff != "" {
+				t.Errorf("ParsePollIntervals(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPollReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(fakeKind, &fake.Managed{})
+
+	type args struct {
+		result reconcile.Result
+		err    error
+		kube   client.Reader
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Error": {
+			args: args{
+				result: reconcile.Result{Requeue: true},
+				err:    errBoom,
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true},
+				err:    errBoom,
+			},
+		},
+		"Requeue": {
+			args: args{
+				result: reconcile.Result{Requeue: true},
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"TerminalError": {
+			args: args{
+				result: reconcile.Result{Requeue: true},
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(resource.Managed).SetConditions(
+							ExternalErrorCondition(errAccessDenied),
+							xpv1.ReconcileError(errors.Wrap(errAccessDenied, "observe failed")),
+						)
+						return nil
+					}),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: errorRetries[ReasonUnauthorized]},
+			},
+		},
+		"Poll": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.SetAnnotations(map[string]string{AnnotationKeyPollInterval: "1h"})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Hour},
+			},
+		},
+		"Deleted": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wrapped := reconcilerFn(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return tc.args.result, tc.args.err
+			})
+			r := NewPollReconciler(tc.args.kube, s, resource.ManagedKind(fakeKind), wrapped)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Reconcile(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("Reconcile(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
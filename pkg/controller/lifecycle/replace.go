@@ -0,0 +1,398 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// AnnotationKeyReplacementPolicy is the key of the annotation that holds
+	// the replacement policy of a managed resource.
+	AnnotationKeyReplacementPolicy = "aws.crossplane.io/replacement-policy"
+
+	// AnnotationKeyImmutableFields is the key of the annotation that records
+	// the values of the immutable fields of the external resource of a
+	// managed resource, so that changes to them can be detected.
+	AnnotationKeyImmutableFields = "aws.crossplane.io/immutable-fields"
+
+	// AnnotationKeyReplacement is the key of the annotation that records the
+	// progress of the replacement of the external resource of a managed
+	// resource.
+	AnnotationKeyReplacement = "aws.crossplane.io/replacement"
+)
+
+// A ReplacementPolicy determines what happens when an immutable field of a
+// managed resource, i.e. one that AWS does not allow to be changed after the
+// external resource was created, is changed.
+type ReplacementPolicy string
+
+// Replacement policies.
+const (
+	// ReplacementPolicyNever refuses changes to immutable fields.
+	ReplacementPolicyNever ReplacementPolicy = "Never"
+
+	// ReplacementPolicyCreateBeforeDestroy replaces the external resource by
+	// creating the new one before deleting the old one.
+	ReplacementPolicyCreateBeforeDestroy ReplacementPolicy = "CreateBeforeDestroy"
+
+	// ReplacementPolicyDestroyBeforeCreate replaces the external resource by
+	// deleting the old one before creating the new one, for kinds whose
+	// external resources cannot coexist, e.g. because their names must be
+	// unique.
+	ReplacementPolicyDestroyBeforeCreate ReplacementPolicy = "DestroyBeforeCreate"
+)
+
+// TypeReplacement is the type of the condition that reports the replacement
+// of the external resource of a managed resource.
+const TypeReplacement xpv1.ConditionType = "Replacement"
+
+// Reasons of the replacement condition.
+const (
+	ReasonReplacing xpv1.ConditionReason = "Replacing"
+	ReasonReplaced  xpv1.ConditionReason = "Replaced"
+)
+
+const (
+	msgReplacing             = "Replacing external resource %s because %s changed"
+	msgDeletingReplaced      = "Deleting external resource %s replaced by %s"
+	msgDeletingBeforeReplace = "Deleting external resource %s before creating its replacement"
+	msgCreatingReplacement   = "Creating the replacement of deleted external resource %s"
+	msgReplaced              = "Replaced external resource %s with %s"
+
+	errReplaceDeletionProtected = "refusing to replace a deletion-protected external resource"
+	errGetReplacement           = "cannot parse replacement annotation"
+	errImmutableFields          = "cannot determine immutable fields"
+	errObserveReplaced          = "cannot observe replaced external resource"
+	errDeleteReplaced           = "cannot delete replaced external resource"
+)
+
+// GetReplacementPolicy returns the replacement policy of the supplied object.
+func GetReplacementPolicy(o metav1.Object) ReplacementPolicy {
+	switch p := ReplacementPolicy(o.GetAnnotations()[AnnotationKeyReplacementPolicy]); p {
+	case ReplacementPolicyCreateBeforeDestroy, ReplacementPolicyDestroyBeforeCreate:
+		return p
+	}
+	return ReplacementPolicyNever
+}
+
+// ReplacesOnChange returns whether the supplied object opted into the
+// replacement of its external resource when an immutable field changes.
+func ReplacesOnChange(o metav1.Object) bool {
+	return GetReplacementPolicy(o) != ReplacementPolicyNever
+}
+
+// ReplacementCondition returns a replacement condition with the supplied
+// reason and message.
+func ReplacementCondition(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	s := corev1.ConditionTrue
+	if r == ReasonReplacing {
+		s = corev1.ConditionFalse
+	}
+	return xpv1.Condition{
+		Type:               TypeReplacement,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// A ReplacementNameFn returns the external name the replacement of the
+// external resource of a managed resource is created under, given the
+// replacement policy.
+type ReplacementNameFn func(mg resource.Managed, p ReplacementPolicy) string
+
+// AssignedName leaves the external name of the replacement empty, so that it
+// is assigned when the replacement is created. It suits the kinds whose
+// external name is assigned by AWS, such as the ID of a security group or the
+// ARN of an IAM policy.
+func AssignedName(_ resource.Managed, _ ReplacementPolicy) string {
+	return ""
+}
+
+// replacementSuffixLength is the length of the random suffix of the names of
+// replacements.
+const replacementSuffixLength = 5
+
+// replacementSuffix matches the random suffix of the name of a replacement,
+// made of the characters of rand.String.
+var replacementSuffix = regexp.MustCompile(`-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+
+// SuffixedName returns a ReplacementNameFn that names the replacement after
+// the external name of the managed resource with a random suffix if it must
+// coexist with the replaced external resource, and reuses the external name
+// of the replaced external resource otherwise. It suits the kinds whose
+// external name is their name, such as buckets and DB instances. Names are
+// truncated to the supplied maximum length of the names of the kind.
+func SuffixedName(maxLength int) ReplacementNameFn {
+	return func(mg resource.Managed, p ReplacementPolicy) string {
+		if p == ReplacementPolicyDestroyBeforeCreate {
+			return meta.GetExternalName(mg)
+		}
+		name := meta.GetExternalName(mg)
+		if name == "" {
+			name = mg.GetName()
+		}
+		return Suffix(name, maxLength)
+	}
+}
+
+// Suffix returns the supplied name with a random suffix, truncated to the
+// supplied maximum length. The suffix of a previous replacement is replaced
+// rather than appended to, so that names do not grow with every replacement.
+func Suffix(name string, maxLength int) string {
+	name = replacementSuffix.ReplaceAllString(name, "")
+	if max := maxLength - replacementSuffixLength - 1; len(name) > max && max > 0 {
+		name = strings.TrimRight(name[:max], "-")
+	}
+	return name + "-" + rand.String(replacementSuffixLength)
+}
+
+// replacement is the progress of a replacement, recorded in the replacement
+// annotation.
+type replacement struct {
+	// From is the external name of the replaced external resource.
+	From string `json:"from"`
+
+	// Policy the external resource is replaced with.
+	Policy ReplacementPolicy `json:"policy"`
+
+	// StartedAt is the time the replacement started at, which names the
+	// final snapshot of the replaced external resource.
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Deleted is true once the replaced external resource no longer exists.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+func getReplacement(mg resource.Managed) (*replacement, error) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyReplacement]
+	if !ok {
+		return nil, nil
+	}
+	r := &replacement{}
+	return r, errors.Wrap(json.Unmarshal([]byte(v), r), errGetReplacement)
+}
+
+func setReplacement(mg resource.Managed, r *replacement) {
+	b, _ := json.Marshal(r)
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyReplacement: string(b)})
+}
+
+// immutableFields returns the values of the immutable fields of the supplied
+// managed resource, both as JSON and as object contents that can be compared
+// with ChangedImmutableFields.
+func (e *external) immutableFields(mg resource.Managed) (string, map[string]interface{}, error) {
+	b, err := json.Marshal(mg)
+	if err != nil {
+		return "", nil, errors.Wrap(err, errImmutableFields)
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(b, &content); err != nil {
+		return "", nil, errors.Wrap(err, errImmutableFields)
+	}
+	in, out := fieldpath.Pave(content), fieldpath.Pave(map[string]interface{}{})
+	for _, f := range ImmutableFields[e.gk] {
+		if v, err := in.GetValue(forProvider + f); err == nil && v != nil {
+			_ = out.SetValue(f, v)
+		}
+	}
+	b, err = json.Marshal(out)
+	return string(b), forProviderContent(out.UnstructuredContent()), errors.Wrap(err, errImmutableFields)
+}
+
+func forProviderContent(params map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"spec": map[string]interface{}{"forProvider": params}}
+}
+
+// observeReplacement replaces the external resource of the supplied managed
+// resource if it opted into replacement and an immutable field changed since
+// the external resource was created. The values of the immutable fields are
+// recorded in an annotation whenever the external resource is observed, so
+// that the change can be detected.
+func (e *external) observeReplacement(ctx context.Context, mg resource.Managed, o managed.ExternalObservation) (managed.ExternalObservation, error) {
+	if _, ok := ImmutableFields[e.gk]; !ok || meta.WasDeleted(mg) || IsObserveOnly(mg) || IsDryRun(mg) {
+		return o, nil
+	}
+	r, err := getReplacement(mg)
+	if err != nil {
+		return o, err
+	}
+	if r != nil {
+		return e.continueReplacement(ctx, mg, o, r)
+	}
+	if !o.ResourceExists {
+		return o, nil
+	}
+	current, desired, err := e.immutableFields(mg)
+	if err != nil {
+		return o, err
+	}
+	recorded, ok := mg.GetAnnotations()[AnnotationKeyImmutableFields]
+	if ok {
+		params := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(recorded), &params); err != nil {
+			return o, errors.Wrap(err, errImmutableFields)
+		}
+		if changed := ChangedImmutableFields(e.gk, forProviderContent(params), desired); len(changed) > 0 {
+			if !ReplacesOnChange(mg) {
+				return o, nil
+			}
+			return e.startReplacement(mg, changed)
+		}
+	}
+	if recorded != current {
+		meta.AddAnnotations(mg, map[string]string{AnnotationKeyImmutableFields: current})
+		o.ResourceLateInitialized = true
+	}
+	return o, nil
+}
+
+// startReplacement records the start of the replacement of the external
+// resource of the supplied managed resource. The reconciler persists it
+// before the replacement is created or the replaced external resource is
+// deleted.
+func (e *external) startReplacement(mg resource.Managed, changed []string) (managed.ExternalObservation, error) {
+	if IsDeletionProtected(mg) {
+		e.report(mg, DeletionProtectionCondition())
+		return managed.ExternalObservation{}, errors.New(errReplaceDeletionProtected)
+	}
+	r := &replacement{From: meta.GetExternalName(mg), Policy: GetReplacementPolicy(mg), StartedAt: metav1.Now()}
+	name := e.replacementName(mg, r.Policy)
+	if name != "" && name == r.From {
+		// The replacement cannot coexist with the external resource it
+		// replaces if it has the same name.
+		r.Policy = ReplacementPolicyDestroyBeforeCreate
+	}
+	setReplacement(mg, r)
+	e.report(mg, ReplacementCondition(ReasonReplacing, fmt.Sprintf(msgReplacing, r.From, strings.Join(changed, ", "))))
+	if r.Policy == ReplacementPolicyDestroyBeforeCreate {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true}, nil
+	}
+	meta.SetExternalName(mg, name)
+	return managed.ExternalObservation{}, nil
+}
+
+// continueReplacement advances the supplied replacement of the external
+// resource of the supplied managed resource, whose observation is of the
+// replacement unless the replaced external resource is deleted first.
+func (e *external) continueReplacement(ctx context.Context, mg resource.Managed, o managed.ExternalObservation, r *replacement) (managed.ExternalObservation, error) {
+	if !r.Deleted {
+		if r.Policy == ReplacementPolicyCreateBeforeDestroy && (!o.ResourceExists || mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue) {
+			// The replacement is created and becomes ready before the
+			// replaced external resource is deleted.
+			return o, nil
+		}
+		deleted, err := e.deleteReplaced(ctx, mg, r)
+		if !deleted || err != nil {
+			if r.Policy == ReplacementPolicyCreateBeforeDestroy {
+				return o, err
+			}
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, err
+		}
+		r.Deleted = true
+		setReplacement(mg, r)
+		if r.Policy == ReplacementPolicyDestroyBeforeCreate {
+			meta.SetExternalName(mg, e.replacementName(mg, r.Policy))
+			e.report(mg, ReplacementCondition(ReasonReplacing, fmt.Sprintf(msgCreatingReplacement, r.From)))
+			return managed.ExternalObservation{}, nil
+		}
+	}
+	if !o.ResourceExists {
+		return o, nil
+	}
+	current, _, err := e.immutableFields(mg)
+	if err != nil {
+		return o, err
+	}
+	meta.RemoveAnnotations(mg, AnnotationKeyReplacement)
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyImmutableFields: current})
+	e.report(mg, ReplacementCondition(ReasonReplaced, fmt.Sprintf(msgReplaced, r.From, meta.GetExternalName(mg))))
+	o.ResourceLateInitialized = true
+	return o, nil
+}
+
+// deleteReplaced deletes the replaced external resource of the supplied
+// managed resource, after taking its final snapshot, and returns true once
+// it no longer exists. It is observed and deleted through a copy of the
+// managed resource with its external name.
+func (e *external) deleteReplaced(ctx context.Context, mg resource.Managed, r *replacement) (bool, error) {
+	old, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return false, errors.New(errDeleteReplaced)
+	}
+	meta.SetExternalName(old, r.From)
+	o, err := e.ExternalClient.Observe(ctx, old)
+	if err != nil {
+		return false, errors.Wrap(err, errObserveReplaced)
+	}
+	if !o.ResourceExists {
+		return true, nil
+	}
+	msg := fmt.Sprintf(msgDeletingBeforeReplace, r.From)
+	if r.Policy == ReplacementPolicyCreateBeforeDestroy {
+		msg = fmt.Sprintf(msgDeletingReplaced, r.From, meta.GetExternalName(mg))
+	}
+	e.report(mg, ReplacementCondition(ReasonReplacing, msg))
+	if old.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonDeleting {
+		return false, nil
+	}
+
+	// The final snapshot is named after the start of the replacement, so
+	// that its name does not change while it is being taken.
+	old.SetDeletionTimestamp(&r.StartedAt)
+	done, err := e.snapshotBeforeDelete(ctx, old)
+	if c := old.GetCondition(TypeFinalSnapshot); c.Reason != "" {
+		mg.SetConditions(c)
+	}
+	if !done || err != nil {
+		return false, err
+	}
+	return false, errors.Wrap(e.ExternalClient.Delete(ctx, old), errDeleteReplaced)
+}
+
+// deleteReplacedWith deletes the replaced external resource of the supplied
+// managed resource, if its replacement is in progress, when the managed
+// resource is deleted. The replaced external resource is superseded by the
+// replacement, so it is deleted without a final snapshot.
+func (e *external) deleteReplacedWith(ctx context.Context, mg resource.Managed) error {
+	r, err := getReplacement(mg)
+	if r == nil || err != nil || r.Deleted || r.From == meta.GetExternalName(mg) {
+		return err
+	}
+	old, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return errors.New(errDeleteReplaced)
+	}
+	meta.SetExternalName(old, r.From)
+	return errors.Wrap(e.ExternalClient.Delete(ctx, old), errDeleteReplaced)
+}
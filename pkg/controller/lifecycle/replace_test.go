@@ -0,0 +1,327 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+)
+
+func replacementState(r replacement) string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+func newSecurityGroup(groupName, externalName string, a map[string]string) *ec2v1beta1.SecurityGroup {
+	sg := &ec2v1beta1.SecurityGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: a},
+		Spec: ec2v1beta1.SecurityGroupSpec{ForProvider: ec2v1beta1.SecurityGroupParameters{
+			GroupName:   groupName,
+			Description: "web servers",
+		}},
+	}
+	meta.SetExternalName(sg, externalName)
+	return sg
+}
+
+func TestReplacement(t *testing.T) {
+	gk := schema.GroupKind{Group: ec2v1beta1.Group, Kind: ec2v1beta1.SecurityGroupKind}
+	recorded := `{"description":"web servers","groupName":"web"}`
+	started := metav1.Now()
+	cbd := map[string]string{AnnotationKeyReplacementPolicy: string(ReplacementPolicyCreateBeforeDestroy)}
+	dbc := map[string]string{AnnotationKeyReplacementPolicy: string(ReplacementPolicyDestroyBeforeCreate)}
+	with := func(a map[string]string, kv ...string) map[string]string {
+		res := map[string]string{}
+		for k, v := range a {
+			res[k] = v
+		}
+		for i := 0; i+1 < len(kv); i += 2 {
+			res[kv[i]] = kv[i+1]
+		}
+		return res
+	}
+
+	type want struct {
+		o            managed.ExternalObservation
+		err          error
+		externalName string
+		annotations  map[string]string
+		message      string
+		deleted      []string
+	}
+
+	cases := map[string]struct {
+		mg       *ec2v1beta1.SecurityGroup
+		existing map[string]bool
+		want     want
+	}{
+		"RecordImmutableFields": {
+			mg:       newSecurityGroup("web", "sg-1", nil),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "sg-1",
+				annotations:  map[string]string{AnnotationKeyImmutableFields: recorded},
+			},
+		},
+		"Unchanged": {
+			mg:       newSecurityGroup("web", "sg-1", map[string]string{AnnotationKeyImmutableFields: recorded}),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: "sg-1",
+				annotations:  map[string]string{AnnotationKeyImmutableFields: recorded},
+			},
+		},
+		"ChangedWithoutPolicy": {
+			mg:       newSecurityGroup("api", "sg-1", map[string]string{AnnotationKeyImmutableFields: recorded}),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: "sg-1",
+				annotations:  map[string]string{AnnotationKeyImmutableFields: recorded},
+			},
+		},
+		"DeletionProtected": {
+			mg:       newSecurityGroup("api", "sg-1", with(cbd, AnnotationKeyImmutableFields, recorded, AnnotationKeyDeletionProtection, "true")),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				err:          errors.New(errReplaceDeletionProtected),
+				externalName: "sg-1",
+				annotations:  with(cbd, AnnotationKeyImmutableFields, recorded, AnnotationKeyDeletionProtection, "true"),
+			},
+		},
+		"StartCreateBeforeDestroy": {
+			mg:       newSecurityGroup("api", "sg-1", with(cbd, AnnotationKeyImmutableFields, recorded)),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{},
+				externalName: "",
+				annotations: with(cbd, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started})),
+				message: "Replacing external resource sg-1 because spec.forProvider.groupName changed",
+			},
+		},
+		"WaitForReplacement": {
+			mg: newSecurityGroup("api", "", with(cbd, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started}))),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{},
+				externalName: "",
+				annotations: with(cbd, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started})),
+			},
+		},
+		"DeleteReplaced": {
+			mg: newSecurityGroup("api", "sg-2", with(cbd, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started}))),
+			existing: map[string]bool{"sg-1": true, "sg-2": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: "sg-2",
+				annotations: with(cbd, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started})),
+				message: "Deleting external resource sg-1 replaced by sg-2",
+				deleted: []string{"sg-1"},
+			},
+		},
+		"CompleteCreateBeforeDestroy": {
+			mg: newSecurityGroup("api", "sg-2", with(cbd, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyCreateBeforeDestroy, StartedAt: started}))),
+			existing: map[string]bool{"sg-2": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "sg-2",
+				annotations:  with(cbd, AnnotationKeyImmutableFields, `{"description":"web servers","groupName":"api"}`),
+				message:      "Replaced external resource sg-1 with sg-2",
+			},
+		},
+		"StartDestroyBeforeCreate": {
+			mg:       newSecurityGroup("api", "sg-1", with(dbc, AnnotationKeyImmutableFields, recorded)),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "sg-1",
+				annotations: with(dbc, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started})),
+				message: "Replacing external resource sg-1 because spec.forProvider.groupName changed",
+			},
+		},
+		"DeleteBeforeCreate": {
+			mg: newSecurityGroup("api", "sg-1", with(dbc, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started}))),
+			existing: map[string]bool{"sg-1": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: "sg-1",
+				annotations: with(dbc, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started})),
+				message: "Deleting external resource sg-1 before creating its replacement",
+				deleted: []string{"sg-1"},
+			},
+		},
+		"CreateAfterDestroy": {
+			mg: newSecurityGroup("api", "sg-1", with(dbc, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started}))),
+			want: want{
+				o:            managed.ExternalObservation{},
+				externalName: "",
+				annotations: with(dbc, AnnotationKeyImmutableFields, recorded,
+					AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started, Deleted: true})),
+				message: "Creating the replacement of deleted external resource sg-1",
+			},
+		},
+		"CompleteDestroyBeforeCreate": {
+			mg: newSecurityGroup("api", "sg-2", with(dbc, AnnotationKeyImmutableFields, recorded,
+				AnnotationKeyReplacement, replacementState(replacement{From: "sg-1", Policy: ReplacementPolicyDestroyBeforeCreate, StartedAt: started, Deleted: true}))),
+			existing: map[string]bool{"sg-2": true},
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "sg-2",
+				annotations:  with(dbc, AnnotationKeyImmutableFields, `{"description":"web servers","groupName":"api"}`),
+				message:      "Replaced external resource sg-1 with sg-2",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			e := &external{
+				ExternalClient: managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+						if !tc.existing[meta.GetExternalName(mg)] {
+							return managed.ExternalObservation{}, nil
+						}
+						mg.SetConditions(xpv1.Available())
+						return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+					},
+					DeleteFn: func(_ context.Context, mg resource.Managed) error {
+						deleted = append(deleted, meta.GetExternalName(mg))
+						return nil
+					},
+				},
+				record:          &eventRecorder{},
+				gk:              gk,
+				replacementName: AssignedName,
+			}
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("external name: -want, +got:\n%s", diff)
+			}
+			a := tc.mg.GetAnnotations()
+			delete(a, meta.AnnotationKeyExternalName)
+			if diff := cmp.Diff(tc.want.annotations, a, cmpopts.EquateEmpty(), cmp.Comparer(equalReplacements)); diff != "" {
+				t.Errorf("annotations: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.message, tc.mg.GetCondition(TypeReplacement).Message); diff != "" {
+				t.Errorf("replacement condition: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("deleted: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// equalReplacements compares annotation values, ignoring the start time of
+// replacements.
+func equalReplacements(a, b string) bool {
+	ra, rb := replacement{}, replacement{}
+	if json.Unmarshal([]byte(a), &ra) != nil || json.Unmarshal([]byte(b), &rb) != nil || ra.From == "" {
+		return a == b
+	}
+	ra.StartedAt, rb.StartedAt = metav1.Time{}, metav1.Time{}
+	return ra == rb
+}
+
+func TestSuffixedName(t *testing.T) {
+	long := strings.Repeat("a", 70)
+
+	cases := map[string]struct {
+		externalName string
+		policy       ReplacementPolicy
+		maxLength    int
+		want         *regexp.Regexp
+	}{
+		"DestroyBeforeCreate": {
+			externalName: "web-b2x4z",
+			policy:       ReplacementPolicyDestroyBeforeCreate,
+			maxLength:    63,
+			want:         regexp.MustCompile(`^web-b2x4z$`),
+		},
+		"CustomExternalName": {
+			externalName: "custom",
+			policy:       ReplacementPolicyCreateBeforeDestroy,
+			maxLength:    63,
+			want:         regexp.MustCompile(`^custom-[a-z0-9]{5}$`),
+		},
+		"PreviousSuffixReplaced": {
+			externalName: "custom-b2x4z",
+			policy:       ReplacementPolicyCreateBeforeDestroy,
+			maxLength:    63,
+			want:         regexp.MustCompile(`^custom-[a-z0-9]{5}$`),
+		},
+		"Truncated": {
+			externalName: long,
+			policy:       ReplacementPolicyCreateBeforeDestroy,
+			maxLength:    63,
+			want:         regexp.MustCompile(`^a{57}-[a-z0-9]{5}$`),
+		},
+		"TruncatedAtHyphen": {
+			externalName: strings.Repeat("a", 56) + "-" + long,
+			policy:       ReplacementPolicyCreateBeforeDestroy,
+			maxLength:    63,
+			want:         regexp.MustCompile(`^a{56}-[a-z0-9]{5}$`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SuffixedName(tc.maxLength)(newSecurityGroup("web", tc.externalName, nil), tc.policy)
+			if !tc.want.MatchString(got) {
+				t.Errorf("SuffixedName(...): want a name matching %s, got %s", tc.want, got)
+			}
+			if got == tc.externalName && tc.policy != ReplacementPolicyDestroyBeforeCreate {
+				t.Errorf("SuffixedName(...): want a new name, got %s", got)
+			}
+		})
+	}
+}
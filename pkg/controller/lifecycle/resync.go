@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// resyncBufferSize is the number of resync requests of a kind that can wait
+// to be queued by its controller.
+const resyncBufferSize = 1024
+
+// resyncs are the channels of the resync requests of each kind.
+var resyncs = struct {
+	sync.Mutex
+	channels map[schema.GroupVersionKind]chan event.GenericEvent
+}{channels: map[schema.GroupVersionKind]chan event.GenericEvent{}}
+
+func resyncChannel(of resource.ManagedKind) chan event.GenericEvent {
+	resyncs.Lock()
+	defer resyncs.Unlock()
+	ch, ok := resyncs.channels[schema.GroupVersionKind(of)]
+	if !ok {
+		ch = make(chan event.GenericEvent, resyncBufferSize)
+		resyncs.channels[schema.GroupVersionKind(of)] = ch
+	}
+	return ch
+}
+
+// ResyncSource returns the source of the resync requests of the managed
+// resources of the supplied kind, which the controller of the kind watches.
+func ResyncSource(of resource.ManagedKind) source.Source {
+	return &source.Channel{Source: resyncChannel(of)}
+}
+
+// RequestResync queues the supplied managed resource of the supplied kind for
+// reconciliation by the controller that watches the ResyncSource of the kind.
+// It returns false if the request was dropped because too many requests of
+// the kind are already waiting.
+func RequestResync(of resource.ManagedKind, o client.Object) bool {
+	select {
+	case resyncChannel(of) <- event.GenericEvent{Object: o}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRequestResync(t *testing.T) {
+	of := resource.ManagedKind(schema.GroupVersionKind{Group: "test.aws.crossplane.io", Version: "v1", Kind: "Resync"})
+	mg := &fake.Managed{}
+	mg.SetName("cool")
+
+	ch := resyncChannel(of)
+	for i := 0; i < resyncBufferSize; i++ {
+		if !RequestResync(of, mg) {
+			t.Fatalf("RequestResync(...): request %d dropped", i)
+		}
+	}
+	if RequestResync(of, mg) {
+		t.Errorf("RequestResync(...): want request dropped once the buffer is full")
+	}
+	e := <-ch
+	if diff := cmp.Diff("cool", e.Object.GetName()); diff != "" {
+		t.Errorf("RequestResync(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errParseShardSelector = "cannot parse the shard selector"
+	errShardIndex         = "shard index %d must be at least 0 and less than the number of shards %d"
+)
+
+// A Shard selects the managed resources this provider instance reconciles,
+// so that several instances can split the managed resources between them.
+type Shard struct {
+	// Selector selects the managed resources by their labels. All managed
+	// resources are selected when it is nil.
+	Selector labels.Selector
+
+	// Count is the number of shards the managed resources are split into by
+	// the hash of their name. They are not split when it is less than 2.
+	Count int
+
+	// Index is the shard of the managed resources whose name hashes to it,
+	// from 0 to Count-1.
+	Index int
+}
+
+// shard is the provider-wide shard of managed resources.
+var shard Shard
+
+// SetShard sets the shard of the managed resources reconciled by this
+// provider instance.
+func SetShard(s Shard) {
+	shard = s
+}
+
+// ParseShard returns the shard of the managed resources that match the
+// supplied label selector, if any, and whose name hashes to the supplied
+// index out of the supplied number of shards.
+func ParseShard(selector string, count, index int) (Shard, error) {
+	s := Shard{Count: count, Index: index}
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return Shard{}, errors.Wrap(err, errParseShardSelector)
+		}
+		s.Selector = sel
+	}
+	if count > 1 && (index < 0 || index >= count) {
+		return Shard{}, errors.Errorf(errShardIndex, index, count)
+	}
+	return s, nil
+}
+
+// Owns returns true if the supplied object belongs to the shard.
+func (s Shard) Owns(o metav1.Object) bool {
+	if s.Selector != nil && !s.Selector.Matches(labels.Set(o.GetLabels())) {
+		return false
+	}
+	if s.Count < 2 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(o.GetName()))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// sharded returns true if the shard does not own every managed resource.
+func (s Shard) sharded() bool {
+	return (s.Selector != nil && !s.Selector.Empty()) || s.Count > 1
+}
+
+// EventFilter returns a predicate that drops the events of the managed
+// resources that do not belong to the provider-wide shard, so that they are
+// never queued by this provider instance. They are still cached, since the
+// managed resources of the shard may reference them. It also drops the
+// updates that only changed the status of a managed resource. The managed
+// reconciler updates the status on every reconcile, and queueing that update
+// would reconcile the managed resource again right away instead of after its
+// poll interval or the retry interval of its error.
+func EventFilter() predicate.Predicate {
+	return predicate.And(predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return shard.Owns(o)
+	}), specChangedPredicate)
+}
+
+// specChangedPredicate passes the updates of managed resources that changed
+// more than their status.
+var specChangedPredicate = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+		return !e.ObjectNew.GetDeletionTimestamp().Equal(e.ObjectOld.GetDeletionTimestamp())
+	}},
+)
+
+// A ShardReconciler only passes the managed resources of the provider-wide
+// shard to the Reconciler it wraps. Those of other shards are left to the
+// provider instances that own them. It catches the requeues of managed
+// resources that left the shard after their events passed the EventFilter.
+type ShardReconciler struct {
+	reconcile.Reconciler
+
+	kube   client.Reader
+	scheme *runtime.Scheme
+	of     resource.ManagedKind
+}
+
+// NewShardReconciler returns a ShardReconciler that wraps the supplied
+// Reconciler of the managed resources of the supplied kind.
+func NewShardReconciler(kube client.Reader, s *runtime.Scheme, of resource.ManagedKind, r reconcile.Reconciler) *ShardReconciler {
+	return &ShardReconciler{Reconciler: r, kube: kube, scheme: s, of: of}
+}
+
+// Reconcile the supplied managed resource with the wrapped Reconciler if it
+// belongs to the shard.
+func (r *ShardReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if !shard.sharded() {
+		return r.Reconciler.Reconcile(ctx, req)
+	}
+	o, err := r.scheme.New(schema.GroupVersionKind(r.of))
+	if err != nil {
+		return r.Reconciler.Reconcile(ctx, req)
+	}
+	mg, ok := o.(resource.Managed)
+	if !ok {
+		return r.Reconciler.Reconcile(ctx, req)
+	}
+	// NOTE: a resource that cannot be read is passed on, so that the wrapped
+	// Reconciler handles its deletion or the error.
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		return r.Reconciler.Reconcile(ctx, req)
+	}
+	if !shard.Owns(mg) {
+		// The resource is reconciled again when its labels change.
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(ctx, req)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestParseShard(t *testing.T) {
+	_, errSelector := labels.Parse("team in (")
+
+	cases := map[string]struct {
+		selector     string
+		count, index int
+		err          error
+	}{
+		"Unsharded": {
+			count: 1,
+		},
+		"Valid": {
+			selector: "team=payments",
+			count:    3,
+			index:    2,
+		},
+		"InvalidSelector": {
+			selector: "team in (",
+			count:    1,
+			err:      errors.Wrap(errSelector, errParseShardSelector),
+		},
+		"IndexOutOfRange": {
+			count: 3,
+			index: 3,
+			err:   errors.Errorf(errShardIndex, 3, 3),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseShard(tc.selector, tc.count, tc.index)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ParseShard(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	// Every name is owned by exactly one shard.
+	for i := 0; i < 100; i++ {
+		o := &metav1.ObjectMeta{Name: fmt.Sprintf("resource-%d", i)}
+		owners := 0
+		for index := 0; index < 3; index++ {
+			if (Shard{Count: 3, Index: index}).Owns(o) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Errorf("Owns(%s): owned by %d shards, want 1", o.Name, owners)
+		}
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	labeled := func(l map[string]string) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: l}}
+	}
+
+	cases := map[string]struct {
+		selector string
+		old, new *fake.Managed
+		want     bool
+	}{
+		"Unsharded": {
+			old:  labeled(nil),
+			new:  labeled(nil),
+			want: true,
+		},
+		"Owned": {
+			selector: "team=payments",
+			old:      labeled(map[string]string{"team": "payments"}),
+			new:      labeled(map[string]string{"team": "payments"}),
+			want:     true,
+		},
+		"NotOwned": {
+			selector: "team=payments",
+			old:      labeled(map[string]string{"team": "orders"}),
+			new:      labeled(map[string]string{"team": "orders"}),
+			want:     false,
+		},
+		"JoinedShard": {
+			selector: "team=payments",
+			old:      labeled(map[string]string{"team": "orders"}),
+			new:      labeled(map[string]string{"team": "payments"}),
+			want:     true,
+		},
+		"LeftShard": {
+			selector: "team=payments",
+			old:      labeled(map[string]string{"team": "payments"}),
+			new:      labeled(map[string]string{"team": "orders"}),
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sh, err := ParseShard(tc.selector, 1, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			SetShard(sh)
+			defer SetShard(Shard{})
+
+			p := EventFilter()
+			if diff := cmp.Diff(tc.want, p.Create(event.CreateEvent{Object: tc.new})); diff != "" {
+				t.Errorf("Create(...): -want, +got:\n%s", diff)
+			}
+			// Change the spec, so that only the shard decides whether the
+			// update is queued.
+			tc.new.SetGeneration(tc.old.GetGeneration() + 1)
+			if diff := cmp.Diff(tc.want, p.Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, p.Generic(event.GenericEvent{Object: tc.new})); diff != "" {
+				t.Errorf("Generic(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEventFilterUpdate(t *testing.T) {
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		old, new *fake.Managed
+		want     bool
+	}{
+		"StatusChanged": {
+			old: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: 1}},
+			new: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: 1, ResourceVersion: "2"}},
+		},
+		"SpecChanged": {
+			old:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: 1}},
+			new:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: 2}},
+			want: true,
+		},
+		"LabelsChanged": {
+			old:  &fake.Managed{},
+			new:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments"}}},
+			want: true,
+		},
+		"AnnotationsChanged": {
+			old:  &fake.Managed{},
+			new:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"crossplane.io/paused": "true"}}},
+			want: true,
+		},
+		"Deleted": {
+			old:  &fake.Managed{},
+			new:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := EventFilter().Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShardReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(fakeKind, &fake.Managed{})
+	labeled := func(l map[string]string) client.Reader {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+			o.SetLabels(l)
+			return nil
+		})}
+	}
+
+	cases := map[string]struct {
+		selector string
+		kube     client.Reader
+		want     bool
+	}{
+		"Unsharded": {
+			want: true,
+		},
+		"Owned": {
+			selector: "team=payments",
+			kube:     labeled(map[string]string{"team": "payments"}),
+			want:     true,
+		},
+		"NotOwned": {
+			selector: "team=payments",
+			kube:     labeled(map[string]string{"team": "orders"}),
+			want:     false,
+		},
+		"NotFound": {
+			selector: "team=payments",
+			kube:     &test.MockClient{MockGet: test.NewMockGetFn(errors.New("boom"))},
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sh, err := ParseShard(tc.selector, 1, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			SetShard(sh)
+			defer SetShard(Shard{})
+
+			reconciled := false
+			wrapped := reconcilerFn(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				reconciled = true
+				return reconcile.Result{}, nil
+			})
+			r := NewShardReconciler(tc.kube, s, resource.ManagedKind(fakeKind), wrapped)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, reconciled); diff != "" {
+				t.Errorf("Reconcile(...): -want reconciled, +got reconciled:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeySnapshotBeforeDelete is the key of the annotation that
+// disables the final snapshot of a stateful managed resource when set to
+// "false". A final snapshot is taken by default.
+const AnnotationKeySnapshotBeforeDelete = "aws.crossplane.io/snapshot-before-delete"
+
+// TypeFinalSnapshot is the type of the condition that reports the final
+// snapshot taken before the external resource of a managed resource is
+// deleted.
+const TypeFinalSnapshot xpv1.ConditionType = "FinalSnapshot"
+
+// Reasons of the final snapshot condition.
+const (
+	ReasonSnapshotPending   xpv1.ConditionReason = "FinalSnapshotPending"
+	ReasonSnapshotCompleted xpv1.ConditionReason = "FinalSnapshotCompleted"
+)
+
+const (
+	msgSnapshotPending   = "Waiting for final snapshot %s to complete before deletion"
+	msgSnapshotCompleted = "Took final snapshot %s before deletion"
+
+	errSnapshot = "cannot take final snapshot before deletion"
+
+	// snapshotTimeFormat is the format of the time in the names of the
+	// final snapshots. It only uses the characters that every service
+	// allows in snapshot names.
+	snapshotTimeFormat = "20060102-150405"
+
+	// maxSnapshotNameLength is the length of the longest snapshot name that
+	// every service accepts; DocumentDB limits identifiers to 63 characters.
+	maxSnapshotNameLength = 63
+
+	// snapshotHashLength is the number of hexadecimal digits of the hash of
+	// the external name that keeps truncated snapshot names distinct.
+	snapshotHashLength = 8
+)
+
+// A Snapshot of an external resource.
+type Snapshot struct {
+	// ID of the snapshot; its ARN, or its name if the service does not
+	// return an ARN.
+	ID string
+
+	// Completed is true if the snapshot can be restored.
+	Completed bool
+}
+
+// A Snapshotter takes snapshots of external resources. The external clients
+// of the stateful kinds of managed resources implement it so that a final
+// snapshot of their external resource is taken before it is deleted. The kinds
+// whose services take a final snapshot themselves when they delete a resource
+// use FinalSnapshotParameters instead.
+type Snapshotter interface {
+	// Snapshot starts taking the snapshot with the supplied name of the
+	// external resource of the supplied managed resource unless it already
+	// exists, and returns it.
+	Snapshot(ctx context.Context, mg resource.Managed, name string) (Snapshot, error)
+}
+
+// SnapshotBeforeDelete returns whether a final snapshot of the external
+// resource of the supplied managed resource should be taken before it is
+// deleted.
+func SnapshotBeforeDelete(mg resource.Managed) bool {
+	if v, err := strconv.ParseBool(mg.GetAnnotations()[AnnotationKeySnapshotBeforeDelete]); err == nil {
+		return v
+	}
+	return true
+}
+
+// FinalSnapshotName returns the name of the final snapshot of the external
+// resource of the supplied managed resource. The name is derived from its
+// external name and deletion time so that it does not change while the
+// snapshot is being taken. External names that would make it longer than 63
+// characters are truncated and suffixed with a hash of the full name.
+func FinalSnapshotName(mg resource.Managed) string {
+	t := time.Now()
+	if dt := mg.GetDeletionTimestamp(); dt != nil {
+		t = dt.Time
+	}
+	name := meta.GetExternalName(mg)
+	suffix := "-final-" + t.UTC().Format(snapshotTimeFormat)
+	if len(name)+len(suffix) > maxSnapshotNameLength {
+		h := sha256.Sum256([]byte(name))
+		prefix := strings.TrimRight(name[:maxSnapshotNameLength-len(suffix)-snapshotHashLength-1], "-")
+		name = prefix + "-" + hex.EncodeToString(h[:])[:snapshotHashLength]
+	}
+	return name + suffix
+}
+
+// FinalSnapshotParameters returns the values of the native parameters with
+// which services such as Redshift and DocumentDB skip or name the final
+// snapshot they take when they delete the external resource of the supplied
+// managed resource. The supplied values from its spec take precedence. When
+// neither is set the final snapshot is named after FinalSnapshotName, or
+// skipped if SnapshotBeforeDelete is false.
+func FinalSnapshotParameters(mg resource.Managed, skip *bool, id *string) (*bool, *string) {
+	switch {
+	case id != nil || (skip != nil && *skip):
+		return skip, id
+	case skip == nil && !SnapshotBeforeDelete(mg):
+		t := true
+		return &t, nil
+	}
+	f, name := false, FinalSnapshotName(mg)
+	return &f, &name
+}
+
+// FinalSnapshotCondition returns a final snapshot condition with the supplied
+// reason and message.
+func FinalSnapshotCondition(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	s := corev1.ConditionTrue
+	if r == ReasonSnapshotPending {
+		s = corev1.ConditionFalse
+	}
+	return xpv1.Condition{
+		Type:               TypeFinalSnapshot,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// snapshotBeforeDelete takes the final snapshot of the external resource of
+// the supplied managed resource if its external client is a Snapshotter. It
+// returns true once the snapshot is completed and the external resource can
+// be deleted. The snapshot is reported in the final snapshot condition and in
+// an event.
+func (e *external) snapshotBeforeDelete(ctx context.Context, mg resource.Managed) (bool, error) {
+	s, ok := e.ExternalClient.(Snapshotter)
+	if !ok || !SnapshotBeforeDelete(mg) {
+		return true, nil
+	}
+	snap, err := s.Snapshot(ctx, mg, FinalSnapshotName(mg))
+	if err != nil {
+		return false, errors.Wrap(err, errSnapshot)
+	}
+	if !snap.Completed {
+		e.report(mg, FinalSnapshotCondition(ReasonSnapshotPending, fmt.Sprintf(msgSnapshotPending, snap.ID)))
+		return false, nil
+	}
+	e.report(mg, FinalSnapshotCondition(ReasonSnapshotCompleted, fmt.Sprintf(msgSnapshotCompleted, snap.ID)))
+	return true, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// snapshotClient is an ExternalClient that is a Snapshotter.
+type snapshotClient struct {
+	managed.ExternalClientFns
+	snapshot func(name string) (Snapshot, error)
+}
+
+func (c *snapshotClient) Snapshot(_ context.Context, _ resource.Managed, name string) (Snapshot, error) {
+	return c.snapshot(name)
+}
+
+func TestSnapshotBeforeDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+	deleted := metav1.NewTime(time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC))
+	name := "cool-final-20211018-153045"
+
+	newManaged := func(a map[string]string) *fake.Managed {
+		mg := withAnnotations(a)
+		meta.SetExternalName(mg, "cool")
+		mg.SetDeletionTimestamp(&deleted)
+		return mg
+	}
+
+	type want struct {
+		err     error
+		deleted bool
+		cond    xpv1.Condition
+		events  []event.Reason
+	}
+
+	cases := map[string]struct {
+		mg       *fake.Managed
+		snapshot func(name string) (Snapshot, error)
+		want     want
+	}{
+		"Disabled": {
+			mg: newManaged(map[string]string{AnnotationKeySnapshotBeforeDelete: "false"}),
+			want: want{
+				deleted: true,
+				cond:    xpv1.Condition{Type: TypeFinalSnapshot, Status: "Unknown"},
+			},
+		},
+		"Pending": {
+			mg: newManaged(nil),
+			snapshot: func(n string) (Snapshot, error) {
+				if n != name {
+					return Snapshot{}, errBoom
+				}
+				return Snapshot{ID: "arn:snapshot"}, nil
+			},
+			want: want{
+				cond:   FinalSnapshotCondition(ReasonSnapshotPending, "Waiting for final snapshot arn:snapshot to complete before deletion"),
+				events: []event.Reason{event.Reason(ReasonSnapshotPending)},
+			},
+		},
+		"Completed": {
+			mg: newManaged(nil),
+			snapshot: func(_ string) (Snapshot, error) {
+				return Snapshot{ID: "arn:snapshot", Completed: true}, nil
+			},
+			want: want{
+				deleted: true,
+				cond:    FinalSnapshotCondition(ReasonSnapshotCompleted, "Took final snapshot arn:snapshot before deletion"),
+				events:  []event.Reason{event.Reason(ReasonSnapshotCompleted)},
+			},
+		},
+		"Failed": {
+			mg: newManaged(nil),
+			snapshot: func(_ string) (Snapshot, error) {
+				return Snapshot{}, errBoom
+			},
+			want: want{
+				err:  errors.Wrap(errBoom, errSnapshot),
+				cond: xpv1.Condition{Type: TypeFinalSnapshot, Status: "Unknown"},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			deleted := false
+			ec := &snapshotClient{
+				ExternalClientFns: managed.ExternalClientFns{
+					DeleteFn: func(_ context.Context, _ resource.Managed) error {
+						deleted = true
+						return nil
+					},
+				},
+				snapshot: tc.snapshot,
+			}
+			rec := &eventRecorder{}
+			c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}), WithRecorder(rec))
+			e, err := c.Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("deleted: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.mg.GetCondition(TypeFinalSnapshot), test.EquateConditions()); diff != "" {
+				t.Errorf("condition: -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, rec.reasons); diff != "" {
+				t.Errorf("events: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFinalSnapshotName(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC))
+
+	cases := map[string]struct {
+		name string
+		want string
+	}{
+		"Short": {
+			name: "cool",
+			want: "cool-final-20211018-153045",
+		},
+		"Long": {
+			name: "a-very-long-external-name-that-does-not-fit-in-a-snapshot-identifier",
+			want: "a-very-long-external-name-that-d-e8fef021-final-20211018-153045",
+		},
+		"TrailingHyphen": {
+			name: "a-very-long-external-name-that--does-not-fit-in-a-snapshot-identifier",
+			want: "a-very-long-external-name-that-6a3506cf-final-20211018-153045",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			meta.SetExternalName(mg, tc.name)
+			mg.SetDeletionTimestamp(&deleted)
+			got := FinalSnapshotName(mg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FinalSnapshotName(...): -want, +got:\n%s", diff)
+			}
+			if len(got) > maxSnapshotNameLength {
+				t.Errorf("FinalSnapshotName(...): %q is longer than %d characters", got, maxSnapshotNameLength)
+			}
+		})
+	}
+}
+
+func TestFinalSnapshotParameters(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC))
+	yes, no := true, false
+	custom, name := "custom", "cool-final-20211018-153045"
+
+	type want struct {
+		skip *bool
+		id   *string
+	}
+
+	cases := map[string]struct {
+		annotations map[string]string
+		skip        *bool
+		id          *string
+		want        want
+	}{
+		"Default": {
+			want: want{skip: &no, id: &name},
+		},
+		"Disabled": {
+			annotations: map[string]string{AnnotationKeySnapshotBeforeDelete: "false"},
+			want:        want{skip: &yes},
+		},
+		"Skipped": {
+			skip: &yes,
+			want: want{skip: &yes},
+		},
+		"NotSkipped": {
+			annotations: map[string]string{AnnotationKeySnapshotBeforeDelete: "false"},
+			skip:        &no,
+			want:        want{skip: &no, id: &name},
+		},
+		"Named": {
+			id:   &custom,
+			want: want{id: &custom},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			mg := withAnnotations(tc.annotations)
+			meta.SetExternalName(mg, "cool")
+			mg.SetDeletionTimestamp(&deleted)
+			skip, id := FinalSnapshotParameters(mg, tc.skip, tc.id)
+			if diff := cmp.Diff(tc.want, want{skip: skip, id: id}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("FinalSnapshotParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
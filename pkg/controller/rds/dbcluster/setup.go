@@ -11,6 +11,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -22,9 +23,14 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/rds/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/rds"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
+// maxNameLength is the maximum length of the name of a DB cluster identifier.
+const maxNameLength = 63
+
 // SetupDBCluster adds a controller that reconciles DbCluster.
 func SetupDBCluster(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll time.Duration) error {
 	name := managed.ControllerName(svcapitypes.DBClusterGroupKind)
@@ -45,9 +51,12 @@ func SetupDBCluster(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.DBCluster{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.DBClusterGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.DBClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name), lifecycle.WithReplacementName(lifecycle.SuffixedName(maxNameLength)))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -134,3 +143,18 @@ func filterList(cr *svcapitypes.DBCluster, obj *svcsdk.DescribeDBClustersOutput)
 	}
 	return resp
 }
+
+// DisableDeletionProtection disables the deletion protection of the supplied
+// DBCluster, and nothing else, so that it can be deleted.
+func (e *external) DisableDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*svcapitypes.DBCluster)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	_, err := e.client.ModifyDBClusterWithContext(ctx, &svcsdk.ModifyDBClusterInput{
+		DBClusterIdentifier: aws.String(meta.GetExternalName(cr)),
+		DeletionProtection:  aws.Bool(false, aws.FieldRequired),
+		ApplyImmediately:    aws.Bool(true),
+	})
+	return aws.Wrap(err, errUpdate)
+}
@@ -18,6 +18,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -30,7 +31,9 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/rds/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/rds"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // error constants
@@ -67,9 +70,12 @@ func SetupDBInstance(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimite
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.DBInstance{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.DBInstanceGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.DBInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -387,3 +393,18 @@ func (e *custom) savePasswordSecret(ctx context.Context, cr *svcapitypes.DBInsta
 	}
 	return patcher.Apply(ctx, sc)
 }
+
+// DisableDeletionProtection disables the deletion protection of the supplied
+// DBInstance, and nothing else, so that it can be deleted.
+func (e *external) DisableDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*svcapitypes.DBInstance)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	_, err := e.client.ModifyDBInstanceWithContext(ctx, &svcsdk.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(meta.GetExternalName(cr)),
+		DeletionProtection:   aws.Bool(false, aws.FieldRequired),
+		ApplyImmediately:     aws.Bool(true),
+	})
+	return aws.Wrap(err, errUpdate)
+}
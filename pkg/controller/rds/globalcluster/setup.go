@@ -5,9 +5,11 @@ import (
 	"time"
 
 	svcsdk "github.com/aws/aws-sdk-go/service/rds"
+	"github.com/pkg/errors"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -19,6 +21,7 @@ import (
 
 	svcapitypes "github.com/crossplane/provider-aws/apis/rds/v1alpha1"
 	aws "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupGlobalCluster adds a controller that reconciles GlobalCluster.
@@ -39,9 +42,11 @@ func SetupGlobalCluster(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLim
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&svcapitypes.GlobalCluster{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(svcapitypes.GlobalClusterGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(svcapitypes.GlobalClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
@@ -87,3 +92,17 @@ func filterList(cr *svcapitypes.GlobalCluster, obj *svcsdk.DescribeGlobalCluster
 	}
 	return resp
 }
+
+// DisableDeletionProtection disables the deletion protection of the supplied
+// GlobalCluster, and nothing else, so that it can be deleted.
+func (e *external) DisableDeletionProtection(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*svcapitypes.GlobalCluster)
+	if !ok {
+		return errors.New(errUnexpectedObject)
+	}
+	_, err := e.client.ModifyGlobalClusterWithContext(ctx, &svcsdk.ModifyGlobalClusterInput{
+		GlobalClusterIdentifier: aws.String(meta.GetExternalName(cr)),
+		DeletionProtection:      aws.Bool(false, aws.FieldRequired),
+	})
+	return aws.Wrap(err, errUpdate)
+}
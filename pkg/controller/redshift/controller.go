@@ -28,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -40,7 +41,9 @@ import (
 
 	"github.com/crossplane/provider-aws/apis/redshift/v1alpha1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/redshift"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -63,9 +66,12 @@ func SetupCluster(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter,
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.Cluster{}).
-		Complete(managed.NewReconciler(
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1alpha1.ClusterGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(
 			mgr, resource.ManagedKind(v1alpha1.ClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: redshift.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: redshift.NewClient}, lifecycle.Options(mgr, name))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
@@ -115,7 +121,9 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	instance := rsp.Clusters[0]
 	current := cr.Spec.ForProvider.DeepCopy()
-	redshift.LateInitialize(&cr.Spec.ForProvider, &instance)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		redshift.LateInitialize(p.(*v1alpha1.ClusterParameters), &instance)
+	})
 	if !reflect.DeepEqual(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errKubeUpdateFailed)
@@ -143,6 +151,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		ResourceUpToDate:  updated,
 		ResourceExists:    true,
 		ConnectionDetails: redshift.GetConnectionDetails(*cr),
+		Diff:              diff,
 	}, nil
 }
 
@@ -213,7 +222,10 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return nil
 	}
 
-	_, err := e.client.DeleteCluster(ctx, redshift.GenerateDeleteClusterInput(&cr.Spec.ForProvider, aws.String(meta.GetExternalName(cr))))
+	input := redshift.GenerateDeleteClusterInput(&cr.Spec.ForProvider, aws.String(meta.GetExternalName(cr)))
+	skip, id := lifecycle.FinalSnapshotParameters(cr, cr.Spec.ForProvider.SkipFinalClusterSnapshot, cr.Spec.ForProvider.FinalClusterSnapshotIdentifier)
+	input.SkipFinalClusterSnapshot, input.FinalClusterSnapshotIdentifier = aws.ToBool(skip), id
+	_, err := e.client.DeleteCluster(ctx, input)
 
 	return awsclient.Wrap(resource.Ignore(redshift.IsNotFound, err), errDeleteFailed)
 }
@@ -18,12 +18,14 @@ package redshift
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsredshift "github.com/aws/aws-sdk-go-v2/service/redshift"
 	awsredshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -35,6 +37,7 @@ import (
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/redshift"
 	"github.com/crossplane/provider-aws/pkg/clients/redshift/fake"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 var (
@@ -75,6 +78,14 @@ func withNewExternalName(s string) redshiftModifier {
 	return func(r *v1alpha1.Cluster) { meta.SetExternalName(r, s) }
 }
 
+func withDeletionTimestamp(t time.Time) redshiftModifier {
+	return func(r *v1alpha1.Cluster) { r.SetDeletionTimestamp(&metav1.Time{Time: t}) }
+}
+
+func withAnnotations(a map[string]string) redshiftModifier {
+	return func(r *v1alpha1.Cluster) { meta.AddAnnotations(r, a) }
+}
+
 func cluster(m ...redshiftModifier) *v1alpha1.Cluster {
 	cr := &v1alpha1.Cluster{
 		Spec: v1alpha1.ClusterSpec{
@@ -463,6 +474,8 @@ func TestUpdate(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
+	deleted := time.Date(2021, 10, 18, 15, 30, 45, 0, time.UTC)
+
 	type want struct {
 		cr  *v1alpha1.Cluster
 		err error
@@ -525,6 +538,38 @@ func TestDelete(t *testing.T) {
 				cr: cluster(withConditions(xpv1.Deleting())),
 			},
 		},
+		"FinalSnapshot": {
+			args: args{
+				redshift: &fake.MockRedshiftClient{
+					MockDelete: func(ctx context.Context, input *awsredshift.DeleteClusterInput, opts []func(*awsredshift.Options)) (*awsredshift.DeleteClusterOutput, error) {
+						if input.SkipFinalClusterSnapshot || aws.ToString(input.FinalClusterSnapshotIdentifier) != "redshift-test-final-20211018-153045" {
+							return nil, errBoom
+						}
+						return &awsredshift.DeleteClusterOutput{}, nil
+					},
+				},
+				cr: cluster(withNewExternalName(name), withDeletionTimestamp(deleted)),
+			},
+			want: want{
+				cr: cluster(withNewExternalName(name), withDeletionTimestamp(deleted), withConditions(xpv1.Deleting())),
+			},
+		},
+		"FinalSnapshotDisabled": {
+			args: args{
+				redshift: &fake.MockRedshiftClient{
+					MockDelete: func(ctx context.Context, input *awsredshift.DeleteClusterInput, opts []func(*awsredshift.Options)) (*awsredshift.DeleteClusterOutput, error) {
+						if !input.SkipFinalClusterSnapshot || input.FinalClusterSnapshotIdentifier != nil {
+							return nil, errBoom
+						}
+						return &awsredshift.DeleteClusterOutput{}, nil
+					},
+				},
+				cr: cluster(withNewExternalName(name), withAnnotations(map[string]string{lifecycle.AnnotationKeySnapshotBeforeDelete: "false"})),
+			},
+			want: want{
+				cr: cluster(withNewExternalName(name), withAnnotations(map[string]string{lifecycle.AnnotationKeySnapshotBeforeDelete: "false"}), withConditions(xpv1.Deleting())),
+			},
+		},
 		"Failed": {
 			args: args{
 				redshift: &fake.MockRedshiftClient{
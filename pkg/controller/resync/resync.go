@@ -0,0 +1,429 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resync requests the reconciliation of managed resources whose
+// external resources are reported changed by the AWS events, e.g. the
+// CloudTrail events matched by an EventBridge rule, delivered to an SQS queue.
+// The ARNs of the resources of the events are matched against the external
+// names and ARNs of the managed resources.
+package resync
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-aws/apis/v1beta1"
+	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/sqs"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+const (
+	errGetProviderConfig = "cannot get ProviderConfig"
+	errGetConfig         = "cannot get AWS config"
+	errParseQueueURL     = "cannot parse the region of queue URL %q"
+	errWatch             = "cannot watch %s"
+	errReceive           = "cannot receive messages"
+	errDelete            = "cannot delete message"
+	errParseEvent        = "cannot parse event"
+
+	// groupSuffix is the suffix of the API groups of the managed resources
+	// of this provider.
+	groupSuffix = "aws.crossplane.io"
+
+	// maxMessages is the maximum number of messages received at once.
+	maxMessages = 10
+
+	// waitTimeSeconds is how long receiving messages waits for them to
+	// arrive.
+	waitTimeSeconds = 20
+
+	// retryWait is how long to wait before receiving messages again after
+	// a failure.
+	retryWait = 10 * time.Second
+)
+
+// Options configure the event-driven resync.
+type Options struct {
+	// QueueURL is the URL of the SQS queue the events are received from.
+	QueueURL string
+
+	// ProviderConfig is the name of the ProviderConfig whose credentials are
+	// used to receive the events.
+	ProviderConfig string
+
+	// Enabled returns true if the managed resources of the supplied kind are
+	// reconciled by this provider instance. All kinds are resynced when it is
+	// nil.
+	Enabled func(schema.GroupKind) bool
+}
+
+// An Event is an EventBridge event.
+type Event struct {
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Account    string          `json:"account"`
+	Region     string          `json:"region"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// EventARNs returns the ARNs of the resources the supplied event reports
+// changed.
+func EventARNs(e Event) []string {
+	arns := make([]string, 0, len(e.Resources))
+	for _, r := range e.Resources {
+		if strings.HasPrefix(r, "arn:") {
+			arns = append(arns, r)
+		}
+	}
+	return arns
+}
+
+// ManagedIdentifiers returns the identifiers of the external resource of the
+// supplied managed resource, i.e. its external name and the ARN in its
+// observation, if any.
+func ManagedIdentifiers(o client.Object) []string {
+	mg, ok := o.(resource.Managed)
+	if !ok {
+		return nil
+	}
+	ids := []string{}
+	if en := meta.GetExternalName(mg); en != "" {
+		ids = append(ids, en)
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return ids
+	}
+	status, _ := u["status"].(map[string]interface{})
+	obs, _ := status["atProvider"].(map[string]interface{})
+	// NOTE: the kind is taken from the type since the objects of the cache
+	// do not have their TypeMeta set.
+	arnKey := strings.ToLower(reflect.Indirect(reflect.ValueOf(mg)).Type().Name()) + "arn"
+	for k, v := range obs {
+		s, ok := v.(string)
+		if !ok || s == "" || s == meta.GetExternalName(mg) {
+			continue
+		}
+		if l := strings.ToLower(k); l == "arn" || l == arnKey {
+			ids = append(ids, s)
+		}
+	}
+	return ids
+}
+
+// ManagedKinds returns the kinds of the managed resources of this provider
+// that are registered with the supplied scheme.
+func ManagedKinds(s *runtime.Scheme) []schema.GroupVersionKind {
+	kinds := []schema.GroupVersionKind{}
+	for gvk := range s.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, groupSuffix) || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		if !s.Recognizes(gvk.GroupVersion().WithKind(gvk.Kind + "List")) {
+			continue
+		}
+		o, err := s.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, ok := o.(resource.Managed); ok {
+			kinds = append(kinds, gvk)
+		}
+	}
+	return kinds
+}
+
+// objectKey identifies a managed resource of a kind.
+type objectKey struct {
+	kind schema.GroupVersionKind
+	name string
+}
+
+// An Index holds the managed resources by the identifiers of their external
+// resources. It is kept up to date by the informers of their kinds.
+type Index struct {
+	mu      sync.RWMutex
+	objects map[string]map[objectKey]client.Object
+	ids     map[objectKey][]string
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		objects: map[string]map[objectKey]client.Object{},
+		ids:     map[objectKey][]string{},
+	}
+}
+
+// Set the supplied managed resource of the supplied kind, replacing its
+// previous identifiers.
+func (i *Index) Set(of schema.GroupVersionKind, o client.Object) {
+	k := objectKey{kind: of, name: o.GetName()}
+	ids := ManagedIdentifiers(o)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(k)
+	for _, id := range ids {
+		if i.objects[id] == nil {
+			i.objects[id] = map[objectKey]client.Object{}
+		}
+		i.objects[id][k] = o
+	}
+	i.ids[k] = ids
+}
+
+// Remove the supplied managed resource of the supplied kind.
+func (i *Index) Remove(of schema.GroupVersionKind, o client.Object) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(objectKey{kind: of, name: o.GetName()})
+}
+
+func (i *Index) remove(k objectKey) {
+	for _, id := range i.ids[k] {
+		delete(i.objects[id], k)
+		if len(i.objects[id]) == 0 {
+			delete(i.objects, id)
+		}
+	}
+	delete(i.ids, k)
+}
+
+// Get calls the supplied function with the managed resources whose external
+// resource has the supplied identifier, and their kind.
+func (i *Index) Get(id string, fn func(of schema.GroupVersionKind, o client.Object)) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for k, o := range i.objects[id] {
+		fn(k.kind, o)
+	}
+}
+
+// Watch the managed resources of the supplied kinds with the supplied
+// informers, so that the Index holds them.
+func (i *Index) Watch(ctx context.Context, s *runtime.Scheme, c cache.Informers, kinds []schema.GroupVersionKind) error {
+	for _, gvk := range kinds {
+		o, err := s.New(gvk)
+		if err != nil {
+			return errors.Wrapf(err, errWatch, gvk)
+		}
+		inf, err := c.GetInformer(ctx, o.(client.Object))
+		if err != nil {
+			return errors.Wrapf(err, errWatch, gvk)
+		}
+		inf.AddEventHandler(i.handler(gvk))
+	}
+	return nil
+}
+
+func (i *Index) handler(of schema.GroupVersionKind) toolscache.ResourceEventHandler {
+	set := func(obj interface{}) {
+		if o, ok := obj.(client.Object); ok {
+			i.Set(of, o)
+		}
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    set,
+		UpdateFunc: func(_, obj interface{}) { set(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if d, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			if o, ok := obj.(client.Object); ok {
+				i.Remove(of, o)
+			}
+		},
+	}
+}
+
+// A Resyncer requests the reconciliation of the managed resources whose
+// external resources are reported changed by events.
+type Resyncer struct {
+	index   *Index
+	request func(of resource.ManagedKind, o client.Object) bool
+	log     logging.Logger
+}
+
+// NewResyncer returns a Resyncer of the managed resources held by the
+// supplied Index.
+func NewResyncer(i *Index, l logging.Logger) *Resyncer {
+	return &Resyncer{index: i, request: lifecycle.RequestResync, log: l}
+}
+
+// Resync requests the reconciliation of the managed resources whose external
+// name or ARN is the ARN of a resource of the supplied event. It returns the
+// number of managed resources whose reconciliation was requested.
+func (r *Resyncer) Resync(e Event) int {
+	requested := map[objectKey]bool{}
+	for _, arn := range EventARNs(e) {
+		r.index.Get(arn, func(of schema.GroupVersionKind, o client.Object) {
+			k := objectKey{kind: of, name: o.GetName()}
+			if requested[k] {
+				return
+			}
+			requested[k] = true
+			if !r.request(resource.ManagedKind(of), o) {
+				r.log.Info("Dropped resync request", "kind", of.Kind, "name", o.GetName(), "event", e.ID)
+				return
+			}
+			r.log.Debug("Requested resync", "kind", of.Kind, "name", o.GetName(), "event", e.ID, "arn", arn)
+		})
+	}
+	return len(requested)
+}
+
+// A ClientFn returns the client of the SQS queue the events are received
+// from.
+type ClientFn func(ctx context.Context) (sqs.Client, error)
+
+// A Consumer receives events from an SQS queue and resyncs the managed
+// resources they report changed.
+type Consumer struct {
+	newClient ClientFn
+	queue     sqs.Client
+	queueURL  string
+	resyncer  *Resyncer
+	log       logging.Logger
+}
+
+// NewConsumer returns a Consumer of the events of the queue with the
+// supplied URL.
+func NewConsumer(newClient ClientFn, queueURL string, r *Resyncer, l logging.Logger) *Consumer {
+	return &Consumer{newClient: newClient, queueURL: queueURL, resyncer: r, log: l}
+}
+
+// Start receiving events until the supplied context is done. Failures are
+// logged and retried, so that they do not stop the manager.
+func (c *Consumer) Start(ctx context.Context) error {
+	for ctx.Err() == nil {
+		if err := c.Consume(ctx); err != nil && ctx.Err() == nil {
+			c.log.Info("Cannot consume events", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryWait):
+			}
+		}
+	}
+	return nil
+}
+
+// Consume receives the available events and resyncs the managed resources
+// they report changed. The messages are deleted once they are handled,
+// including those that are not events.
+func (c *Consumer) Consume(ctx context.Context) error {
+	if c.queue == nil {
+		q, err := c.newClient(ctx)
+		if err != nil {
+			return err
+		}
+		c.queue = q
+	}
+	out, err := c.queue.ReceiveMessage(ctx, &awssqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(c.queueURL),
+		MaxNumberOfMessages: maxMessages,
+		WaitTimeSeconds:     waitTimeSeconds,
+	})
+	if err != nil {
+		return awsclient.Wrap(err, errReceive)
+	}
+	for _, m := range out.Messages {
+		e := Event{}
+		if err := json.Unmarshal([]byte(aws.ToString(m.Body)), &e); err != nil {
+			c.log.Info(errParseEvent, "error", err, "messageID", aws.ToString(m.MessageId))
+		} else {
+			c.resyncer.Resync(e)
+		}
+		if _, err := c.queue.DeleteMessage(ctx, &awssqs.DeleteMessageInput{
+			QueueUrl:      aws.String(c.queueURL),
+			ReceiptHandle: m.ReceiptHandle,
+		}); err != nil {
+			return awsclient.Wrap(err, errDelete)
+		}
+	}
+	return nil
+}
+
+// QueueRegion returns the region of the SQS queue with the supplied URL,
+// e.g. https://sqs.us-east-1.amazonaws.com/123456789012/events.
+func QueueRegion(queueURL string) (string, error) {
+	u, err := url.Parse(queueURL)
+	if err != nil {
+		return "", errors.Wrapf(err, errParseQueueURL, queueURL)
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) < 3 || parts[0] != "sqs" {
+		return "", errors.Errorf(errParseQueueURL, queueURL)
+	}
+	return parts[1], nil
+}
+
+// Setup adds a runnable that resyncs managed resources on the events
+// received from the queue in the supplied options. The managed resources are
+// queued through the ResyncSource of their kind, which their controllers
+// watch.
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
+	region, err := QueueRegion(o.QueueURL)
+	if err != nil {
+		return err
+	}
+	kinds := []schema.GroupVersionKind{}
+	for _, gvk := range ManagedKinds(mgr.GetScheme()) {
+		if o.Enabled == nil || o.Enabled(gvk.GroupKind()) {
+			kinds = append(kinds, gvk)
+		}
+	}
+	i := NewIndex()
+	if err := i.Watch(context.Background(), mgr.GetScheme(), mgr.GetCache(), kinds); err != nil {
+		return err
+	}
+	newClient := func(ctx context.Context) (sqs.Client, error) {
+		pc := &v1beta1.ProviderConfig{}
+		if err := mgr.GetClient().Get(ctx, types.NamespacedName{Name: o.ProviderConfig}, pc); err != nil {
+			return nil, errors.Wrap(err, errGetProviderConfig)
+		}
+		cfg, err := awsclient.GetConfigForProviderConfig(ctx, mgr.GetClient(), pc, region)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetConfig)
+		}
+		return sqs.NewClient(*cfg), nil
+	}
+	log := l.WithValues("runnable", "resync")
+	return mgr.Add(manager.RunnableFunc(NewConsumer(newClient, o.QueueURL, NewResyncer(i, log), log).Start))
+}
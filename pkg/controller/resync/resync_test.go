@@ -0,0 +1,261 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resync
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-aws/apis/sqs/v1beta1"
+	"github.com/crossplane/provider-aws/pkg/clients/sqs"
+	"github.com/crossplane/provider-aws/pkg/clients/sqs/fake"
+)
+
+var (
+	errBoom  = errors.New("boom")
+	queueARN = "arn:aws:sqs:us-east-1:123456789012:orders"
+	queueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/events"
+)
+
+func queue(name string) *v1beta1.Queue {
+	q := &v1beta1.Queue{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}
+	meta.SetExternalName(q, "https://sqs.us-east-1.amazonaws.com/123456789012/"+name)
+	q.Status.AtProvider.ARN = "arn:aws:sqs:us-east-1:123456789012:" + name
+	return q
+}
+
+func event(id string, resources ...string) string {
+	b, _ := json.Marshal(Event{ID: id, Resources: resources})
+	return string(b)
+}
+
+func TestEventARNs(t *testing.T) {
+	cases := map[string]struct {
+		e    Event
+		want []string
+	}{
+		"Resources": {
+			e:    Event{Resources: []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1234"}},
+			want: []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1234"},
+		},
+		"NotARNs": {
+			e:    Event{Resources: []string{"vpc-1234"}},
+			want: []string{},
+		},
+		"DetailIgnored": {
+			e: Event{Detail: json.RawMessage(`{
+				"requestParameters": {"roleName": "admin"},
+				"responseElements": {"role": {"arn": "arn:aws:iam::123456789012:role/admin"}}
+			}`)},
+			want: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := EventARNs(tc.e)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("EventARNs(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestManagedIdentifiers(t *testing.T) {
+	got := ManagedIdentifiers(queue("orders"))
+	sort.Strings(got)
+	want := []string{queueARN, "https://sqs.us-east-1.amazonaws.com/123456789012/orders"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ManagedIdentifiers(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestQueueRegion(t *testing.T) {
+	cases := map[string]struct {
+		url  string
+		want string
+		err  bool
+	}{
+		"Valid": {
+			url:  queueURL,
+			want: "us-east-1",
+		},
+		"Invalid": {
+			url: "https://example.com/events",
+			err: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := QueueRegion(tc.url)
+			if (err != nil) != tc.err {
+				t.Errorf("QueueRegion(...): unexpected error %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("QueueRegion(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	get := func(i *Index, id string) []string {
+		names := []string{}
+		i.Get(id, func(_ schema.GroupVersionKind, o client.Object) { names = append(names, o.GetName()) })
+		sort.Strings(names)
+		return names
+	}
+
+	i := NewIndex()
+	i.Set(v1beta1.QueueGroupVersionKind, queue("orders"))
+	i.Set(v1beta1.QueueGroupVersionKind, queue("payments"))
+	if diff := cmp.Diff([]string{"orders"}, get(i, queueARN)); diff != "" {
+		t.Errorf("Get(...): -want, +got:\n%s", diff)
+	}
+
+	renamed := queue("orders")
+	renamed.Status.AtProvider.ARN = "arn:aws:sqs:us-east-1:123456789012:renamed"
+	i.Set(v1beta1.QueueGroupVersionKind, renamed)
+	if diff := cmp.Diff([]string{}, get(i, queueARN)); diff != "" {
+		t.Errorf("Get(...) after Set: -want, +got:\n%s", diff)
+	}
+
+	i.Remove(v1beta1.QueueGroupVersionKind, renamed)
+	if diff := cmp.Diff([]string{}, get(i, renamed.Status.AtProvider.ARN)); diff != "" {
+		t.Errorf("Get(...) after Remove: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, len(i.ids)); diff != "" {
+		t.Errorf("Remove(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestConsume(t *testing.T) {
+	message := func(body string) *fake.MockSQSClient {
+		return &fake.MockSQSClient{
+			MockReceiveMessage: func(_ context.Context, _ *awssqs.ReceiveMessageInput, _ []func(*awssqs.Options)) (*awssqs.ReceiveMessageOutput, error) {
+				return &awssqs.ReceiveMessageOutput{Messages: []sqstypes.Message{
+					{Body: aws.String(body), ReceiptHandle: aws.String("receipt-1")},
+				}}, nil
+			},
+		}
+	}
+
+	type args struct {
+		queues []*v1beta1.Queue
+		queue  *fake.MockSQSClient
+	}
+	type want struct {
+		err       error
+		requested []string
+		deleted   []string
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ReceiveError": {
+			args: args{
+				queue: &fake.MockSQSClient{
+					MockReceiveMessage: func(_ context.Context, _ *awssqs.ReceiveMessageInput, _ []func(*awssqs.Options)) (*awssqs.ReceiveMessageOutput, error) {
+						return nil, errBoom
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errReceive),
+			},
+		},
+		"ResyncMatchingARN": {
+			args: args{
+				queues: []*v1beta1.Queue{queue("orders"), queue("payments")},
+				queue:  message(event("event-1", queueARN)),
+			},
+			want: want{
+				requested: []string{"orders"},
+				deleted:   []string{"receipt-1"},
+			},
+		},
+		"ResourceIDNotMatched": {
+			args: args{
+				queues: []*v1beta1.Queue{queue("orders")},
+				queue:  message(event("event-1", "orders")),
+			},
+			want: want{
+				deleted: []string{"receipt-1"},
+			},
+		},
+		"InvalidMessageDeleted": {
+			args: args{
+				queue: message("not an event"),
+			},
+			want: want{
+				deleted: []string{"receipt-1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requested, deleted []string
+			tc.args.queue.MockDeleteMessage = func(_ context.Context, input *awssqs.DeleteMessageInput, _ []func(*awssqs.Options)) (*awssqs.DeleteMessageOutput, error) {
+				deleted = append(deleted, aws.ToString(input.ReceiptHandle))
+				return &awssqs.DeleteMessageOutput{}, nil
+			}
+			newClient := func(_ context.Context) (sqs.Client, error) { return tc.args.queue, nil }
+
+			i := NewIndex()
+			for _, q := range tc.args.queues {
+				i.Set(v1beta1.QueueGroupVersionKind, q)
+			}
+			r := NewResyncer(i, logging.NewNopLogger())
+			r.request = func(_ resource.ManagedKind, o client.Object) bool {
+				requested = append(requested, o.GetName())
+				return true
+			}
+
+			err := NewConsumer(newClient, queueURL, r, logging.NewNopLogger()).Consume(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Consume(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.requested, requested); diff != "" {
+				t.Errorf("Consume(...): -want requested, +got requested:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("Consume(...): -want deleted, +got deleted:\n%s", diff)
+			}
+		})
+	}
+}
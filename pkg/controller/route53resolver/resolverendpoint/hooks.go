@@ -18,8 +18,10 @@ import (
 
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/crossplane/provider-aws/apis/route53resolver/v1alpha1"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 // SetupResolverEndpoint adds a controller that reconciles ResolverEndpoints
@@ -40,9 +42,11 @@ func SetupResolverEndpoint(mgr ctrl.Manager, l logging.Logger, rl workqueue.Rate
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1alpha1.ResolverEndpoint{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(cpresource.ManagedKind(v1alpha1.ResolverEndpointGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			cpresource.ManagedKind(v1alpha1.ResolverEndpointGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), opts: opts}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), opts: opts}, lifecycle.Options(mgr, name))),
 			managed.WithInitializers(),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
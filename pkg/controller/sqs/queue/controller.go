@@ -18,6 +18,7 @@ package queue
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -27,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
 	awssqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
@@ -40,7 +42,9 @@ import (
 
 	"github.com/crossplane/provider-aws/apis/sqs/v1beta1"
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/connection"
 	"github.com/crossplane/provider-aws/pkg/clients/sqs"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 const (
@@ -65,14 +69,40 @@ func SetupQueue(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, po
 			RateLimiter: ratelimiter.NewController(rl),
 		}).
 		For(&v1beta1.Queue{}).
-		Complete(managed.NewReconciler(mgr,
+		Watches(lifecycle.ResyncSource(resource.ManagedKind(v1beta1.QueueGroupVersionKind)), &handler.EnqueueRequestForObject{}).
+		WithEventFilter(lifecycle.EventFilter()).
+		Complete(lifecycle.NewReconciler(mgr,
 			resource.ManagedKind(v1beta1.QueueGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: sqs.NewClient}),
+			managed.WithExternalConnecter(lifecycle.NewConnecter(&connector{kube: mgr.GetClient(), newClientFn: sqs.NewClient}, lifecycle.Options(mgr, name), lifecycle.WithReplacementName(replacementName))),
+			managed.WithConnectionPublishers(managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), connection.NewPublisher(mgr.GetClient())),
 			managed.WithPollInterval(poll),
 			managed.WithLogger(l.WithValues("controller", name)),
 			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
 }
 
+// fifoSuffix is the suffix the names of FIFO queues must end with.
+const fifoSuffix = ".fifo"
+
+// maxNameLength is the maximum length of the name of a queue, including the
+// suffix of FIFO queues.
+const maxNameLength = 80
+
+// replacementName names the replacement of a queue like
+// lifecycle.SuffixedName, with the suffix of FIFO queues if it is one.
+func replacementName(mg resource.Managed, p lifecycle.ReplacementPolicy) string {
+	name := strings.TrimSuffix(meta.GetExternalName(mg), fifoSuffix)
+	if p != lifecycle.ReplacementPolicyDestroyBeforeCreate {
+		if name == "" {
+			name = mg.GetName()
+		}
+		name = lifecycle.Suffix(name, maxNameLength-len(fifoSuffix))
+	}
+	if cr, ok := mg.(*v1beta1.Queue); ok && aws.ToBool(cr.Spec.ForProvider.FIFOQueue) {
+		name += fifoSuffix
+	}
+	return name
+}
+
 type connector struct {
 	kube        client.Client
 	newClientFn func(aws.Config) sqs.Client
@@ -125,7 +155,9 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, awsclient.Wrap(err, errListQueueTagsFailed)
 	}
 
-	sqs.LateInitialize(&cr.Spec.ForProvider, resAttributes.Attributes, resTags.Tags)
+	diff := awsclient.LateInitializeDiff(&cr.Spec.ForProvider, func(p interface{}) {
+		sqs.LateInitialize(p.(*v1beta1.QueueParameters), resAttributes.Attributes, resTags.Tags)
+	})
 	current := cr.Spec.ForProvider.DeepCopy()
 	if !cmp.Equal(current, &cr.Spec.ForProvider) {
 		if err := e.kube.Update(ctx, cr); err != nil {
@@ -141,6 +173,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		ResourceExists:    true,
 		ResourceUpToDate:  sqs.IsUpToDate(cr.Spec.ForProvider, resAttributes.Attributes, resTags.Tags),
 		ConnectionDetails: sqs.GetConnectionDetails(*cr),
+		Diff:              diff,
 	}, nil
 }
 
@@ -152,8 +185,12 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.SetConditions(xpv1.Creating())
 
+	attributes, err := sqs.GenerateCreateAttributes(&cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	}
 	resp, err := e.client.CreateQueue(ctx, &awssqs.CreateQueueInput{
-		Attributes: sqs.GenerateCreateAttributes(&cr.Spec.ForProvider),
+		Attributes: attributes,
 		QueueName:  aws.String(meta.GetExternalName(cr)),
 		Tags:       cr.Spec.ForProvider.Tags,
 	})
@@ -176,9 +213,13 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, nil
 	}
 
-	_, err := e.client.SetQueueAttributes(ctx, &awssqs.SetQueueAttributesInput{
+	attributes, err := sqs.GenerateQueueAttributes(&cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	}
+	_, err = e.client.SetQueueAttributes(ctx, &awssqs.SetQueueAttributesInput{
 		QueueUrl:   aws.String(cr.Status.AtProvider.URL),
-		Attributes: sqs.GenerateQueueAttributes(&cr.Spec.ForProvider),
+		Attributes: attributes,
 	})
 	if err != nil {
 		return managed.ExternalUpdate{}, awsclient.Wrap(err, errUpdateFailed)
@@ -17,6 +17,8 @@ package queue
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"testing"
 
 	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
@@ -33,6 +35,7 @@ import (
 	awsclient "github.com/crossplane/provider-aws/pkg/clients"
 	"github.com/crossplane/provider-aws/pkg/clients/sqs"
 	"github.com/crossplane/provider-aws/pkg/clients/sqs/fake"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
 )
 
 var (
@@ -435,3 +438,59 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestReplacementName(t *testing.T) {
+	cases := map[string]struct {
+		cr     *v1beta1.Queue
+		policy lifecycle.ReplacementPolicy
+		want   string
+	}{
+		"StandardToFIFO": {
+			cr:     queue(withExternalName(queueName), withSpec(v1beta1.QueueParameters{FIFOQueue: awsclient.Bool(true)})),
+			policy: lifecycle.ReplacementPolicyDestroyBeforeCreate,
+			want:   queueName + ".fifo",
+		},
+		"FIFOToStandard": {
+			cr:     queue(withExternalName(queueName+".fifo"), withSpec(v1beta1.QueueParameters{FIFOQueue: awsclient.Bool(false)})),
+			policy: lifecycle.ReplacementPolicyDestroyBeforeCreate,
+			want:   queueName,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, replacementName(tc.cr, tc.policy)); diff != "" {
+				t.Errorf("replacementName(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSuffixedReplacementName(t *testing.T) {
+	cases := map[string]struct {
+		cr   *v1beta1.Queue
+		want *regexp.Regexp
+	}{
+		"Standard": {
+			cr:   queue(withExternalName(queueName), withSpec(v1beta1.QueueParameters{})),
+			want: regexp.MustCompile(`^` + queueName + `-[a-z0-9]{5}$`),
+		},
+		"FIFO": {
+			cr:   queue(withExternalName(queueName+".fifo"), withSpec(v1beta1.QueueParameters{FIFOQueue: awsclient.Bool(true)})),
+			want: regexp.MustCompile(`^` + queueName + `-[a-z0-9]{5}\.fifo$`),
+		},
+		"Truncated": {
+			cr:   queue(withExternalName(strings.Repeat("q", 90)+".fifo"), withSpec(v1beta1.QueueParameters{FIFOQueue: awsclient.Bool(true)})),
+			want: regexp.MustCompile(`^q{69}-[a-z0-9]{5}\.fifo$`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := replacementName(tc.cr, lifecycle.ReplacementPolicyCreateBeforeDestroy)
+			if !tc.want.MatchString(got) {
+				t.Errorf("replacementName(...): want a name matching %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
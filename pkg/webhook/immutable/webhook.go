@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package immutable rejects changes to the fields of managed resources that
+// AWS does not allow to be changed after their external resource was created.
+package immutable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+// Path is the path the immutable fields webhook is served at.
+const Path = "/validate-immutable-fields"
+
+const (
+	errDecodeObject    = "cannot decode object"
+	errDecodeOldObject = "cannot decode old object"
+
+	msgImmutable = "cannot change %s of %s %s after its external resource was created; set the %s annotation to %s or %s to replace the external resource instead"
+)
+
+// A Handler rejects updates of managed resources that change immutable
+// fields, unless the managed resource opted into the replacement of its
+// external resource.
+type Handler struct {
+	fields map[schema.GroupKind][]string
+	log    logging.Logger
+}
+
+// NewHandler returns a Handler of the immutable fields of the kinds of
+// lifecycle.ImmutableFields.
+func NewHandler(l logging.Logger) *Handler {
+	return &Handler{fields: lifecycle.ImmutableFields, log: l}
+}
+
+// Handle rejects the admitted update if it changes immutable fields.
+func (h *Handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+	if _, ok := h.fields[gk]; !ok || req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	cr := composed.New()
+	if err := cr.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+	old := composed.New()
+	if err := old.UnmarshalJSON(req.OldObject.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOldObject))
+	}
+	if lifecycle.ReplacesOnChange(cr) || !wasCreated(old) {
+		return admission.Allowed("")
+	}
+	changed := lifecycle.ChangedImmutableFields(gk, old.UnstructuredContent(), cr.UnstructuredContent())
+	if len(changed) == 0 {
+		return admission.Allowed("")
+	}
+	h.log.Debug("Refusing to change immutable fields", "kind", gk, "name", cr.GetName(), "fields", changed)
+	return admission.Denied(fmt.Sprintf(msgImmutable, strings.Join(changed, ", "), gk.Kind, cr.GetName(),
+		lifecycle.AnnotationKeyReplacementPolicy, lifecycle.ReplacementPolicyCreateBeforeDestroy, lifecycle.ReplacementPolicyDestroyBeforeCreate))
+}
+
+// wasCreated returns whether the external resource of the supplied managed
+// resource was created or observed. Managed resources whose external
+// resource was never created may change any field.
+func wasCreated(cr *composed.Unstructured) bool {
+	if !meta.GetExternalCreateSucceeded(cr).IsZero() {
+		return true
+	}
+	// Controllers only report whether the external resource is ready once
+	// they observed it.
+	s := cr.GetCondition(xpv1.TypeReady).Status
+	return s == corev1.ConditionTrue || s == corev1.ConditionFalse
+}
+
+// Setup registers the immutable fields webhook with the webhook server of
+// the supplied manager.
+func Setup(mgr ctrl.Manager, l logging.Logger) error {
+	h := NewHandler(l.WithValues("webhook", "immutable"))
+	mgr.GetWebhookServer().Register(Path, &webhook.Admission{Handler: h})
+	return nil
+}
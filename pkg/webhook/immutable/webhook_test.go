@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ec2v1beta1 "github.com/crossplane/provider-aws/apis/ec2/v1beta1"
+	"github.com/crossplane/provider-aws/pkg/controller/lifecycle"
+)
+
+type sgModifier func(sg map[string]interface{})
+
+func withAnnotations(a map[string]interface{}) sgModifier {
+	return func(sg map[string]interface{}) {
+		sg["metadata"].(map[string]interface{})["annotations"] = a
+	}
+}
+
+func withReady() sgModifier {
+	return func(sg map[string]interface{}) {
+		sg["status"] = map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available", "lastTransitionTime": "2021-10-01T00:00:00Z"},
+		}}
+	}
+}
+
+func securityGroup(groupName string, m ...sgModifier) runtime.RawExtension {
+	sg := map[string]interface{}{
+		"apiVersion": ec2v1beta1.SchemeGroupVersion.String(),
+		"kind":       ec2v1beta1.SecurityGroupKind,
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{"forProvider": map[string]interface{}{
+			"groupName":   groupName,
+			"description": "web servers",
+			"region":      "us-east-1",
+		}},
+	}
+	for _, f := range m {
+		f(sg)
+	}
+	b, _ := json.Marshal(sg)
+	return runtime.RawExtension{Raw: b}
+}
+
+func TestHandle(t *testing.T) {
+	gvk := metav1.GroupVersionKind{Group: ec2v1beta1.Group, Version: ec2v1beta1.Version, Kind: ec2v1beta1.SecurityGroupKind}
+	created := withAnnotations(map[string]interface{}{"crossplane.io/external-create-succeeded": "2021-10-01T00:00:00Z"})
+
+	cases := map[string]struct {
+		req  admissionv1.AdmissionRequest
+		want bool
+	}{
+		"Create": {
+			req:  admissionv1.AdmissionRequest{Operation: admissionv1.Create, Kind: gvk, Object: securityGroup("web")},
+			want: true,
+		},
+		"UnknownKind": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      metav1.GroupVersionKind{Group: ec2v1beta1.Group, Version: ec2v1beta1.Version, Kind: ec2v1beta1.AddressKind},
+				Object:    runtime.RawExtension{Raw: []byte(`{}`)},
+				OldObject: runtime.RawExtension{Raw: []byte(`{}`)},
+			},
+			want: true,
+		},
+		"InvalidObject": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object:    runtime.RawExtension{Raw: []byte(`{`)},
+				OldObject: securityGroup("web", created),
+			},
+			want: false,
+		},
+		"NotCreated": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object:    securityGroup("api"),
+				OldObject: securityGroup("web"),
+			},
+			want: true,
+		},
+		"MutableFieldChanged": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object:    securityGroup("web", created, withAnnotations(map[string]interface{}{"team": "web"})),
+				OldObject: securityGroup("web", created),
+			},
+			want: true,
+		},
+		"ImmutableFieldChanged": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object:    securityGroup("api", created),
+				OldObject: securityGroup("web", created),
+			},
+			want: false,
+		},
+		"ImmutableFieldChangedAfterObserve": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object:    securityGroup("api", withReady()),
+				OldObject: securityGroup("web", withReady()),
+			},
+			want: false,
+		},
+		"ReplacesOnChange": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      gvk,
+				Object: securityGroup("api", withAnnotations(map[string]interface{}{
+					"crossplane.io/external-create-succeeded": "2021-10-01T00:00:00Z",
+					lifecycle.AnnotationKeyReplacementPolicy:  string(lifecycle.ReplacementPolicyDestroyBeforeCreate),
+				})),
+				OldObject: securityGroup("web", created),
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := NewHandler(logging.NewNopLogger()).Handle(context.Background(), admission.Request{AdmissionRequest: tc.req})
+			if diff := cmp.Diff(tc.want, resp.Allowed); diff != "" {
+				t.Errorf("Handle(...): -want allowed, +got allowed:\n%s\n%v", diff, resp.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policylint lints the IAM policy documents of managed resources
+// before they reach AWS.
+package policylint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	awsclients "github.com/crossplane/provider-aws/pkg/clients"
+)
+
+// The rules of the linter.
+const (
+	// RuleWildcardPrincipal reports statements that allow everyone, i.e.
+	// Principal "*", without a condition.
+	RuleWildcardPrincipal = "wildcard-principal"
+
+	// RuleWildcardActionResource reports statements that allow every action
+	// on every resource.
+	RuleWildcardActionResource = "wildcard-action-resource"
+
+	// RuleInvalidARN reports resources and principals that are not valid
+	// ARNs.
+	RuleInvalidARN = "invalid-arn"
+
+	// RuleUnknownConditionOperator reports condition operators that IAM
+	// does not support.
+	RuleUnknownConditionOperator = "unknown-condition-operator"
+
+	// RuleSizeLimit reports documents that exceed the size limit of their
+	// kind.
+	RuleSizeLimit = "size-limit"
+
+	// RuleInvalidDocument reports documents that cannot be parsed as a
+	// policy, which prevents the other rules from being checked.
+	RuleInvalidDocument = "invalid-document"
+)
+
+// sizeLimitPrefix is the prefix of the configuration keys of the size limits
+// of kinds, e.g. size-limit.IAMRole.
+const sizeLimitPrefix = RuleSizeLimit + "."
+
+const (
+	errUnknownRule     = "unknown rule %s"
+	errUnknownSeverity = "unknown severity %s of rule %s"
+	errParseSizeLimit  = "cannot parse the size limit of %s"
+)
+
+// A Severity determines what happens to a resource whose policy violates a
+// rule.
+type Severity string
+
+// Severities of rules.
+const (
+	// SeverityDeny rejects the resource.
+	SeverityDeny Severity = "Deny"
+
+	// SeverityWarn admits the resource with a warning.
+	SeverityWarn Severity = "Warn"
+
+	// SeverityOff disables the rule.
+	SeverityOff Severity = "Off"
+)
+
+// A Config configures the severity of the rules and the size limits of the
+// policies of each kind.
+type Config struct {
+	Severities map[string]Severity
+	SizeLimits map[string]int
+}
+
+// DefaultConfig returns the configuration used for the rules and kinds that
+// are not configured. Size limits are in characters, not counting
+// whitespace outside of strings.
+func DefaultConfig() Config {
+	return Config{
+		Severities: map[string]Severity{
+			RuleWildcardPrincipal:        SeverityDeny,
+			RuleWildcardActionResource:   SeverityDeny,
+			RuleInvalidARN:               SeverityDeny,
+			RuleUnknownConditionOperator: SeverityDeny,
+			RuleSizeLimit:                SeverityDeny,
+			RuleInvalidDocument:          SeverityWarn,
+		},
+		SizeLimits: map[string]int{
+			"IAMPolicy":    6144,
+			"IAMRole":      2048,
+			"BucketPolicy": 20480,
+			"Queue":        20480,
+			"SNSTopic":     30720,
+			"Key":          32768,
+		},
+	}
+}
+
+// ParseConfig returns the default configuration overridden by the supplied
+// data of a rules ConfigMap, e.g.:
+//
+//	wildcard-principal: Warn
+//	invalid-document: Off
+//	size-limit.IAMRole: "4096"
+func ParseConfig(data map[string]string) (Config, error) {
+	c := DefaultConfig()
+	for k, v := range data {
+		if strings.HasPrefix(k, sizeLimitPrefix) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return Config{}, errors.Wrapf(err, errParseSizeLimit, k)
+			}
+			c.SizeLimits[strings.TrimPrefix(k, sizeLimitPrefix)] = n
+			continue
+		}
+		if _, ok := c.Severities[k]; !ok {
+			return Config{}, errors.Errorf(errUnknownRule, k)
+		}
+		s, ok := parseSeverity(v)
+		if !ok {
+			return Config{}, errors.Errorf(errUnknownSeverity, v, k)
+		}
+		c.Severities[k] = s
+	}
+	return c, nil
+}
+
+func parseSeverity(s string) (Severity, bool) {
+	for _, sev := range []Severity{SeverityDeny, SeverityWarn, SeverityOff} {
+		if strings.EqualFold(s, string(sev)) {
+			return sev, true
+		}
+	}
+	return "", false
+}
+
+// A Finding is a violation of a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+// String returns the message of the finding prefixed with its rule.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Rule, f.Message)
+}
+
+// A Linter lints policy documents according to its configuration.
+type Linter struct {
+	config Config
+}
+
+// NewLinter returns a Linter with the supplied configuration.
+func NewLinter(c Config) *Linter {
+	return &Linter{config: c}
+}
+
+func (l *Linter) report(findings []Finding, rule, format string, args ...interface{}) []Finding {
+	s := l.config.Severities[rule]
+	if s == "" || s == SeverityOff {
+		return findings
+	}
+	return append(findings, Finding{Rule: rule, Severity: s, Message: fmt.Sprintf(format, args...)})
+}
+
+// Lint the supplied JSON policy document of a managed resource of the
+// supplied kind.
+func (l *Linter) Lint(kind, doc string) []Finding {
+	var findings []Finding
+
+	compact := &bytes.Buffer{}
+	size := len(doc)
+	if err := json.Compact(compact, []byte(doc)); err == nil {
+		size = compact.Len()
+	}
+	if limit, ok := l.config.SizeLimits[kind]; ok && size > limit {
+		findings = l.report(findings, RuleSizeLimit, "the document is %d characters long, which exceeds the limit of %d of %s", size, limit, kind)
+	}
+
+	p, err := awsclients.ParsePolicy(doc)
+	if err != nil {
+		return l.report(findings, RuleInvalidDocument, "%s", err)
+	}
+	for i, s := range p.Statements {
+		findings = append(findings, l.lintStatement(fmt.Sprintf("statement %d", i), s)...)
+	}
+	return findings
+}
+
+func (l *Linter) lintStatement(name string, s awsclients.PolicyStatement) []Finding { // nolint:gocyclo
+	var findings []Finding
+	if s.SID != "" {
+		name = fmt.Sprintf("statement %q", s.SID)
+	}
+	allow := strings.EqualFold(s.Effect, "Allow")
+
+	if allow && len(s.Condition) == 0 && isEveryone(s.Principal) {
+		findings = l.report(findings, RuleWildcardPrincipal, "%s allows everyone without a condition", name)
+	}
+	if allow && contains(s.Action, "*") && contains(s.Resource, "*") {
+		findings = l.report(findings, RuleWildcardActionResource, "%s allows every action on every resource", name)
+	}
+
+	for _, r := range append(append([]string{}, s.Resource...), s.NotResource...) {
+		if r != "*" && !isARN(r) {
+			findings = l.report(findings, RuleInvalidARN, "%s has resource %q, which is not a valid ARN", name, r)
+		}
+	}
+	for _, p := range []*awsclients.PolicyPrincipal{s.Principal, s.NotPrincipal} {
+		if p == nil {
+			continue
+		}
+		for _, a := range p.AWS {
+			if a != "*" && !accountID.MatchString(a) && !isARN(a) {
+				findings = l.report(findings, RuleInvalidARN, "%s has AWS principal %q, which is neither an account ID nor a valid ARN", name, a)
+			}
+		}
+	}
+
+	for op := range s.Condition {
+		if !IsConditionOperator(op) {
+			findings = l.report(findings, RuleUnknownConditionOperator, "%s has unknown condition operator %q", name, op)
+		}
+	}
+	return findings
+}
+
+func isEveryone(p *awsclients.PolicyPrincipal) bool {
+	return p != nil && (p.AllowAnon || contains(p.AWS, "*"))
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	accountID = regexp.MustCompile(`^\d{12}$`)
+
+	// arn matches arn:partition:service:region:account-id:resource. The
+	// region and account ID are empty for some services, e.g. S3.
+	arn = regexp.MustCompile(`^arn:[a-z*-]+:[a-z0-9*-]+:[a-z0-9*-]*:(\d{12}|\*|aws)?:.+$`)
+)
+
+func isARN(s string) bool {
+	return arn.MatchString(s)
+}
+
+// conditionOperators are the condition operators supported by IAM, without
+// their set operator prefix and IfExists suffix.
+var conditionOperators = map[string]struct{}{
+	"StringEquals": {}, "StringNotEquals": {}, "StringEqualsIgnoreCase": {}, "StringNotEqualsIgnoreCase": {},
+	"StringLike": {}, "StringNotLike": {},
+	"NumericEquals": {}, "NumericNotEquals": {}, "NumericLessThan": {}, "NumericLessThanEquals": {},
+	"NumericGreaterThan": {}, "NumericGreaterThanEquals": {},
+	"DateEquals": {}, "DateNotEquals": {}, "DateLessThan": {}, "DateLessThanEquals": {},
+	"DateGreaterThan": {}, "DateGreaterThanEquals": {},
+	"Bool": {}, "BinaryEquals": {}, "IpAddress": {}, "NotIpAddress": {},
+	"ArnEquals": {}, "ArnLike": {}, "ArnNotEquals": {}, "ArnNotLike": {},
+	"Null": {},
+}
+
+// IsConditionOperator returns true if the supplied condition operator is
+// supported by IAM, e.g. ForAnyValue:StringLikeIfExists.
+func IsConditionOperator(op string) bool {
+	for _, prefix := range []string{"ForAnyValue:", "ForAllValues:"} {
+		op = strings.TrimPrefix(op, prefix)
+	}
+	if base := strings.TrimSuffix(op, "IfExists"); base != op {
+		// Null checks existence itself, so it has no IfExists form.
+		if base == "Null" {
+			return false
+		}
+		op = base
+	}
+	_, ok := conditionOperators[op]
+	return ok
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policylint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestLint(t *testing.T) {
+	cases := map[string]struct {
+		kind   string
+		doc    string
+		config map[string]string
+		want   []string
+	}{
+		"Valid": {
+			kind: "Queue",
+			doc: `{"Statement": [{"Effect": "Allow", "Principal": {"Service": "sns.amazonaws.com"}, "Action": "sqs:SendMessage",
+				"Resource": "arn:aws:sqs:us-east-1:123456789012:orders",
+				"Condition": {"ArnEquals": {"aws:SourceArn": "arn:aws:sns:us-east-1:123456789012:events"}}}]}`,
+		},
+		"WildcardPrincipal": {
+			kind: "BucketPolicy",
+			doc:  `{"Statement": [{"Sid": "public", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}`,
+			want: []string{"Deny " + RuleWildcardPrincipal},
+		},
+		"WildcardPrincipalWithCondition": {
+			kind: "BucketPolicy",
+			doc: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*",
+				"Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-1234"}}}]}`,
+		},
+		"WildcardActionResource": {
+			kind: "IAMPolicy",
+			doc:  `{"Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			want: []string{"Deny " + RuleWildcardActionResource},
+		},
+		"DenyWildcard": {
+			kind: "IAMPolicy",
+			doc:  `{"Statement": {"Effect": "Deny", "Action": "*", "Resource": "*"}}`,
+		},
+		"InvalidARN": {
+			kind: "IAMPolicy",
+			doc:  `{"Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "my-bucket", "NotPrincipal": {"AWS": "12345"}}}`,
+			want: []string{"Deny " + RuleInvalidARN, "Deny " + RuleInvalidARN},
+		},
+		"UnknownConditionOperator": {
+			kind: "Key",
+			doc: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "kms:*", "Resource": "*",
+				"Condition": {"StringEqualz": {"kms:ViaService": "s3.us-east-1.amazonaws.com"}, "ForAnyValue:StringLikeIfExists": {"aws:TagKeys": "team"}}}}`,
+			want: []string{"Deny " + RuleUnknownConditionOperator},
+		},
+		"SizeLimit": {
+			kind:   "IAMRole",
+			doc:    `{"Statement": {"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}}`,
+			config: map[string]string{"size-limit.IAMRole": "10"},
+			want:   []string{"Deny " + RuleSizeLimit},
+		},
+		"InvalidDocument": {
+			kind: "SNSTopic",
+			doc:  `{"Statement": "everything"}`,
+			want: []string{"Warn " + RuleInvalidDocument},
+		},
+		"ConfiguredSeverity": {
+			kind:   "IAMPolicy",
+			doc:    `{"Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			config: map[string]string{RuleWildcardActionResource: "warn"},
+			want:   []string{"Warn " + RuleWildcardActionResource},
+		},
+		"RuleOff": {
+			kind:   "IAMPolicy",
+			doc:    `{"Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			config: map[string]string{RuleWildcardActionResource: "Off"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := ParseConfig(tc.config)
+			if err != nil {
+				t.Fatalf("ParseConfig(...): %s", err)
+			}
+			var got []string
+			for _, f := range NewLinter(c).Lint(tc.kind, tc.doc) {
+				got = append(got, string(f.Severity)+" "+f.Rule)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Lint(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	cases := map[string]struct {
+		data map[string]string
+		err  error
+	}{
+		"UnknownRule": {
+			data: map[string]string{"no-admins": "Deny"},
+			err:  errors.Errorf(errUnknownRule, "no-admins"),
+		},
+		"UnknownSeverity": {
+			data: map[string]string{RuleInvalidARN: "Block"},
+			err:  errors.Errorf(errUnknownSeverity, "Block", RuleInvalidARN),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConfig(tc.data)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ParseConfig(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsConditionOperator(t *testing.T) {
+	for _, op := range []string{"StringEquals", "ForAllValues:StringLike", "ArnLikeIfExists", "Null"} {
+		if !IsConditionOperator(op) {
+			t.Errorf("IsConditionOperator(%s): want true", op)
+		}
+	}
+	for _, op := range []string{"StringEqualz", "NullIfExists", strings.ToLower("StringEquals")} {
+		if IsConditionOperator(op) {
+			t.Errorf("IsConditionOperator(%s): want false", op)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policylint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	identityv1alpha1 "github.com/crossplane/provider-aws/apis/identity/v1alpha1"
+	identityv1beta1 "github.com/crossplane/provider-aws/apis/identity/v1beta1"
+	kmsv1alpha1 "github.com/crossplane/provider-aws/apis/kms/v1alpha1"
+	notificationv1alpha1 "github.com/crossplane/provider-aws/apis/notification/v1alpha1"
+	s3v1alpha3 "github.com/crossplane/provider-aws/apis/s3/v1alpha3"
+	sqsv1beta1 "github.com/crossplane/provider-aws/apis/sqs/v1beta1"
+	awsclients "github.com/crossplane/provider-aws/pkg/clients"
+	"github.com/crossplane/provider-aws/pkg/clients/iam"
+	"github.com/crossplane/provider-aws/pkg/clients/s3"
+)
+
+// Path is the path the policy linter webhook is served at.
+const Path = "/validate-policies"
+
+const (
+	errGetConfig   = "cannot get the policy linter rules ConfigMap"
+	errWatchConfig = "cannot watch the policy linter rules ConfigMap"
+	errParseConfig = "cannot parse the policy linter rules ConfigMap"
+	errDecode      = "cannot decode object"
+)
+
+// A Document is a JSON policy document of a managed resource.
+type Document struct {
+	// Field is the path of the field the document is specified in.
+	Field string
+
+	// Policy is the JSON policy document.
+	Policy string
+}
+
+// A DocumentsFn returns the policy documents of an admitted object.
+type DocumentsFn func(d *admission.Decoder, req admission.Request) ([]Document, error)
+
+// Kinds are the documents of the managed resource kinds that are linted.
+var Kinds = map[schema.GroupKind]DocumentsFn{
+	{Group: identityv1alpha1.Group, Kind: identityv1alpha1.IAMPolicyKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &identityv1alpha1.IAMPolicy{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		doc, err := iam.IAMPolicyDocument(cr.Spec.ForProvider)
+		return documents(err, Document{Field: "spec.forProvider.document", Policy: doc})
+	},
+	{Group: identityv1beta1.Group, Kind: identityv1beta1.IAMRoleKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &identityv1beta1.IAMRole{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		doc, err := iam.AssumeRolePolicyDocument(cr.Spec.ForProvider)
+		return documents(err, Document{Field: "spec.forProvider.assumeRolePolicyDocument", Policy: doc})
+	},
+	{Group: s3v1alpha3.Group, Kind: s3v1alpha3.BucketPolicyKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &s3v1alpha3.BucketPolicy{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		if cr.Spec.Parameters.RawPolicy != nil {
+			return documents(nil, Document{Field: "spec.forProvider.rawPolicy", Policy: *cr.Spec.Parameters.RawPolicy})
+		}
+		if cr.Spec.Parameters.Policy == nil {
+			return nil, nil
+		}
+		body, err := s3.Serialize(cr.Spec.Parameters.Policy)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(body)
+		return documents(err, Document{Field: "spec.forProvider.policy", Policy: string(b)})
+	},
+	{Group: sqsv1beta1.Group, Kind: sqsv1beta1.QueueKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &sqsv1beta1.Queue{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		doc, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+		return documents(err, Document{Field: "spec.forProvider.policy", Policy: awsclients.StringValue(doc)})
+	},
+	{Group: notificationv1alpha1.Group, Kind: notificationv1alpha1.SNSTopicKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &notificationv1alpha1.SNSTopic{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		doc, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+		return documents(err, Document{Field: "spec.forProvider.policy", Policy: awsclients.StringValue(doc)})
+	},
+	{Group: kmsv1alpha1.GroupVersion.Group, Kind: kmsv1alpha1.KeyKind}: func(d *admission.Decoder, req admission.Request) ([]Document, error) {
+		cr := &kmsv1alpha1.Key{}
+		if err := d.Decode(req, cr); err != nil {
+			return nil, err
+		}
+		doc, err := awsclients.PolicyDocumentString(cr.Spec.ForProvider.PolicyDocument, cr.Spec.ForProvider.Policy)
+		return documents(err, Document{Field: "spec.forProvider.policy", Policy: awsclients.StringValue(doc)})
+	},
+}
+
+// documents returns the supplied documents that are specified, unless there
+// is an error.
+func documents(err error, docs ...Document) ([]Document, error) {
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Document, 0, len(docs))
+	for _, d := range docs {
+		if d.Policy != "" {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+// A Handler admits managed resources whose policy documents do not violate
+// any rule of severity Deny, with a warning for each violation of a rule of
+// severity Warn.
+type Handler struct {
+	kube    client.Reader
+	config  types.NamespacedName
+	kinds   map[schema.GroupKind]DocumentsFn
+	decoder *admission.Decoder
+	log     logging.Logger
+}
+
+// NewHandler returns a Handler that reads its rules from the supplied
+// ConfigMap, if any. The rules are read on every request, so that changes to
+// the ConfigMap apply without restarting the provider.
+func NewHandler(kube client.Reader, config types.NamespacedName, l logging.Logger) *Handler {
+	return &Handler{kube: kube, config: config, kinds: Kinds, log: l}
+}
+
+// InjectDecoder injects the decoder of admitted objects.
+func (h *Handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle lints the policy documents of the admitted managed resource.
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		return admission.Allowed("")
+	}
+	fn, ok := h.kinds[schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}]
+	if !ok {
+		return admission.Allowed("")
+	}
+	docs, err := fn(h.decoder, req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+	if len(docs) == 0 {
+		return admission.Allowed("")
+	}
+	cfg, err := h.rules(ctx)
+	if err != nil {
+		// NOTE: a broken ConfigMap must not block every policy change, so
+		// the default rules apply until it is fixed.
+		h.log.Info("Using the default policy linter rules", "error", err)
+		cfg = DefaultConfig()
+	}
+
+	l := NewLinter(cfg)
+	var denied, warnings []string
+	for _, d := range docs {
+		for _, f := range l.Lint(req.Kind.Kind, d.Policy) {
+			msg := fmt.Sprintf("%s: %s", d.Field, f)
+			if f.Severity == SeverityDeny {
+				denied = append(denied, msg)
+				continue
+			}
+			warnings = append(warnings, msg)
+		}
+	}
+	if len(denied) > 0 {
+		return admission.Denied(strings.Join(denied, "; ")).WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+func (h *Handler) rules(ctx context.Context) (Config, error) {
+	if h.config.Name == "" {
+		return DefaultConfig(), nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := h.kube.Get(ctx, h.config, cm); err != nil {
+		if kerrors.IsNotFound(err) {
+			return DefaultConfig(), nil
+		}
+		return Config{}, errors.Wrap(err, errGetConfig)
+	}
+	c, err := ParseConfig(cm.Data)
+	return c, errors.Wrap(err, errParseConfig)
+}
+
+// Options configure the policy linter webhook.
+type Options struct {
+	// ConfigMap is the namespace and name of the rules ConfigMap. The
+	// default rules apply if it is empty or does not exist.
+	ConfigMap types.NamespacedName
+}
+
+// Setup registers the policy linter webhook with the webhook server of the
+// supplied manager. The rules ConfigMap is read from a cache that only
+// watches it, rather than from the API server on every admission.
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
+	var kube client.Reader = mgr.GetAPIReader()
+	if o.ConfigMap.Name != "" {
+		c, err := cache.New(mgr.GetConfig(), cache.Options{
+			Scheme:    mgr.GetScheme(),
+			Mapper:    mgr.GetRESTMapper(),
+			Namespace: o.ConfigMap.Namespace,
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", o.ConfigMap.Name)},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, errWatchConfig)
+		}
+		if _, err := c.GetInformer(context.Background(), &corev1.ConfigMap{}); err != nil {
+			return errors.Wrap(err, errWatchConfig)
+		}
+		if err := mgr.Add(c); err != nil {
+			return errors.Wrap(err, errWatchConfig)
+		}
+		kube = c
+	}
+	h := NewHandler(kube, o.ConfigMap, l.WithValues("webhook", "policylint"))
+	mgr.GetWebhookServer().Register(Path, &webhook.Admission{Handler: h})
+	return nil
+}
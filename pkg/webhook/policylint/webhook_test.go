@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policylint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-aws/apis"
+	identityv1alpha1 "github.com/crossplane/provider-aws/apis/identity/v1alpha1"
+)
+
+const wildcardPolicy = `{
+	"apiVersion": "identity.aws.crossplane.io/v1alpha1",
+	"kind": "IAMPolicy",
+	"metadata": {"name": "admin"},
+	"spec": {"forProvider": {"name": "admin", "document": "{\"Statement\": {\"Effect\": \"Allow\", \"Action\": \"*\", \"Resource\": \"*\"}}"}}
+}`
+
+func TestHandle(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatalf("apis.AddToScheme(...): %s", err)
+	}
+	d, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("admission.NewDecoder(...): %s", err)
+	}
+	policy := metav1.GroupVersionKind{Group: identityv1alpha1.Group, Version: identityv1alpha1.Version, Kind: identityv1alpha1.IAMPolicyKind}
+	config := types.NamespacedName{Namespace: "crossplane-system", Name: "policy-lint-rules"}
+
+	type want struct {
+		allowed  bool
+		warnings []string
+	}
+
+	cases := map[string]struct {
+		kube   client.Reader
+		config types.NamespacedName
+		req    admission.Request
+		want   want
+	}{
+		"Delete": {
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Delete,
+				Kind:      policy,
+			}},
+			want: want{allowed: true},
+		},
+		"UnknownKind": {
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      metav1.GroupVersionKind{Group: "ec2.aws.crossplane.io", Version: "v1beta1", Kind: "VPC"},
+				Object:    runtime.RawExtension{Raw: []byte(`{}`)},
+			}},
+			want: want{allowed: true},
+		},
+		"InvalidObject": {
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      policy,
+				Object:    runtime.RawExtension{Raw: []byte(`{`)},
+			}},
+			want: want{allowed: false},
+		},
+		"DefaultRulesDeny": {
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      policy,
+				Object:    runtime.RawExtension{Raw: []byte(wildcardPolicy)},
+			}},
+			want: want{allowed: false},
+		},
+		"ConfigMapNotFound": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.Name)),
+			},
+			config: config,
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      policy,
+				Object:    runtime.RawExtension{Raw: []byte(wildcardPolicy)},
+			}},
+			want: want{allowed: false},
+		},
+		"ConfiguredRulesWarn": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*corev1.ConfigMap).Data = map[string]string{RuleWildcardActionResource: "Warn"}
+					return nil
+				}),
+			},
+			config: config,
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      policy,
+				Object:    runtime.RawExtension{Raw: []byte(wildcardPolicy)},
+			}},
+			want: want{
+				allowed:  true,
+				warnings: []string{"spec.forProvider.document: wildcard-action-resource: statement 0 allows every action on every resource"},
+			},
+		},
+		"InvalidConfigMap": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*corev1.ConfigMap).Data = map[string]string{RuleWildcardActionResource: "Maybe"}
+					return nil
+				}),
+			},
+			config: config,
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      policy,
+				Object:    runtime.RawExtension{Raw: []byte(wildcardPolicy)},
+			}},
+			want: want{allowed: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.kube, tc.config, logging.NewNopLogger())
+			if err := h.InjectDecoder(d); err != nil {
+				t.Fatalf("h.InjectDecoder(...): %s", err)
+			}
+			resp := h.Handle(context.Background(), tc.req)
+			got := want{allowed: resp.Allowed, warnings: resp.Warnings}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("h.Handle(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}